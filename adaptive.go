@@ -0,0 +1,89 @@
+package cacheMachine
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//AdaptiveTTL adjusts timeouts of entries to how often they are read. Whenever the timer of an entry fires, the entry
+//is kept for another timeout if it has been read in the meantime: twice as long if it's been read at least Hits times,
+//half as long otherwise, always staying within Min and Max. Entries that haven't been read at all expire as usual
+type AdaptiveTTL struct {
+	//Bounds of the timeout
+	Min time.Duration
+	Max time.Duration
+
+	//Number of reads within one timeout after which the timeout is lengthened. Defaults to 1
+	Hits int
+}
+
+//------PRIVATE------
+
+//next returns the timeout that follows the timeout supplied given the number of reads within it. Returns 0 if the
+//entry should expire
+func (a *AdaptiveTTL) next(ttl time.Duration, hits uint32) time.Duration {
+	if hits < 1 {
+		return 0
+	}
+
+	threshold := a.Hits
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	if int(hits) >= threshold {
+		ttl *= 2
+	} else {
+		ttl /= 2
+	}
+
+	if a.Max > 0 && ttl > a.Max {
+		ttl = a.Max
+	}
+
+	if ttl < a.Min {
+		ttl = a.Min
+	}
+
+	return ttl
+}
+
+//hit counts read of the entry, separately for adaptive timeouts if they are on, and restarts the timer of the entry
+//if timeouts are sliding, unless expiry is paused. It's safe to call this method under the read lock
+func (c *Cache[TKey, TValue]) hit(e *entry[TValue]) {
+	atomic.AddUint32(&e.reads, 1)
+	atomic.StoreInt64(&e.lastRead, c.now().UnixNano())
+
+	if c.cache.Requirements.AdaptiveTTL != nil {
+		atomic.AddUint32(&e.hits, 1)
+	}
+
+	if c.cache.Requirements.SlidingTimeout && !c.expiryPaused {
+		e.mx.Lock()
+		e.resetTimer(e.ttl)
+		e.mx.Unlock()
+	}
+}
+
+//adapt restarts the timer of the entry that has just fired with the timeout adjusted to the reads of the entry.
+//Returns false if the entry should expire instead. This method has no mutex protection
+func (c *Cache[TKey, TValue]) adapt(e *entry[TValue]) bool {
+	a := c.cache.Requirements.AdaptiveTTL
+	if a == nil {
+		return false
+	}
+
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	ttl := a.next(e.ttl, atomic.SwapUint32(&e.hits, 0))
+	if ttl < 1 {
+		return false
+	}
+
+	e.resetTimer(ttl)
+
+	return true
+}
@@ -0,0 +1,100 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestAdaptiveTTL(t *testing.T) {
+	a := AdaptiveTTL{Min: time.Second, Max: time.Second * 8, Hits: 3}
+
+	cases := []struct {
+		ttl      time.Duration
+		hits     uint32
+		expected time.Duration
+	}{
+		{time.Second * 2, 0, 0},
+		{time.Second * 2, 3, time.Second * 4},
+		{time.Second * 6, 5, time.Second * 8},
+		{time.Second * 4, 1, time.Second * 2},
+		{time.Second, 2, time.Second},
+	}
+
+	for _, tc := range cases {
+		if got := a.next(tc.ttl, tc.hits); got != tc.expected {
+			t.Errorf("Expected timeout %s after %s with %d hits, got %s", tc.expected, tc.ttl, tc.hits, got)
+		}
+	}
+}
+
+func TestRequirements_AdaptiveTTL(t *testing.T) {
+	c := initializeFullCache(0, &Requirements{AdaptiveTTL: &AdaptiveTTL{Min: time.Millisecond * 50, Max: time.Millisecond * 400}})
+
+	c.AddWithTimeout(1, 1, time.Millisecond*100)
+	c.AddWithTimeout(2, 2, time.Millisecond*100)
+
+	c.Get(1)
+
+	time.Sleep(time.Millisecond * 150)
+
+	if !c.Exist(1) || c.Exist(2) {
+		t.Errorf("Expected only the entry that was read to outlive its timeout, got 1 - %t, 2 - %t", c.Exist(1), c.Exist(2))
+	}
+
+	c.mx.RLock()
+	ttl := c.data[1].ttl
+	c.mx.RUnlock()
+
+	if ttl != time.Millisecond*200 {
+		t.Errorf("Expected timeout of the entry to be lengthened to %s, got %s", time.Millisecond*200, ttl)
+	}
+
+	time.Sleep(time.Millisecond * 300)
+
+	if c.Exist(1) {
+		t.Errorf("Expected the entry to expire once it's no longer read, but it did not")
+	}
+}
+
+func TestRequirements_SlidingTimeout(t *testing.T) {
+	c := New[int, int](&Requirements{SlidingTimeout: true})
+
+	c.AddWithTimeout(1, 1, time.Millisecond*50)
+	c.AddWithTimeout(2, 2, time.Millisecond*50)
+
+	for i := 0; i < 4; i++ {
+		time.Sleep(time.Millisecond * 25)
+		c.Get(1)
+	}
+
+	if !c.Exist(1) || c.Exist(2) {
+		t.Errorf("Expected only the entry that was read to outlive its timeout, got 1 - %t, 2 - %t", c.Exist(1), c.Exist(2))
+	}
+
+	time.Sleep(time.Millisecond * 100)
+
+	if c.Exist(1) {
+		t.Errorf("Expected the entry to expire once it's no longer read, but it did not")
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkAdaptiveTTL(b *testing.B) {
+	a := AdaptiveTTL{Min: time.Second, Max: time.Minute}
+
+	for n := 0; n < b.N; n++ {
+		a.next(time.Second*10, uint32(n%3))
+	}
+}
+
+func BenchmarkRequirements_SlidingTimeout(b *testing.B) {
+	c := New[int, int](&Requirements{SlidingTimeout: true, DefaultTimeout: time.Minute})
+	c.Add(1, 1)
+
+	for n := 0; n < b.N; n++ {
+		c.Get(1)
+	}
+}
@@ -0,0 +1,123 @@
+package cacheMachine
+
+import (
+	"sync"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//Number of rows of the frequency sketch, each indexed by a different hash of the key
+const sketchDepth = 4
+
+//Counters of the sketch saturate at this value, as estimates beyond it make no difference for admission
+const sketchMaxCount = 15
+
+//===========[STRUCTS]==================================================================================================
+
+//Count-min sketch estimating how often keys have been used recently, as used by TinyLFU. Once the number of uses
+//recorded reaches the sample size, all the counters are halved, so that keys popular in the past fade away
+type frequencySketch struct {
+	rows   [sketchDepth][]uint8
+	mask   uint64
+	uses   int
+	sample int
+	mx     sync.Mutex
+}
+
+//------PRIVATE------
+
+//increment records single use of the key
+func (s *frequencySketch) increment(hash uint64) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for i := range s.rows {
+		if j := s.index(hash, i); s.rows[i][j] < sketchMaxCount {
+			s.rows[i][j]++
+		}
+	}
+
+	if s.uses++; s.uses >= s.sample {
+		s.age()
+	}
+}
+
+//estimate returns the estimated number of recent uses of the key
+func (s *frequencySketch) estimate(hash uint64) uint8 {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	count := uint8(sketchMaxCount)
+
+	for i := range s.rows {
+		if c := s.rows[i][s.index(hash, i)]; c < count {
+			count = c
+		}
+	}
+
+	return count
+}
+
+//index returns position of the key in the row specified, derived from the hash using double hashing
+func (s *frequencySketch) index(hash uint64, row int) uint64 {
+	return (hash + uint64(row)*(hash>>32|1)) & s.mask
+}
+
+//age halves all the counters
+func (s *frequencySketch) age() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] /= 2
+		}
+	}
+
+	s.uses /= 2
+}
+
+//recordUse counts single use of the key for the admission filter. It's safe to call this method under the read lock
+func (c *Cache[TKey, TValue]) recordUse(key TKey) {
+	if c.admission != nil {
+		c.admission.increment(scanHash(key))
+	}
+}
+
+//admit decides whether a new key may enter the cache. Once the cache is full, the key is admitted only if it has
+//been used more often than the key that would be evicted to make room for it. This method has no mutex protection
+func (c *Cache[TKey, TValue]) admit(key TKey, e *entry[TValue]) bool {
+	if c.admission == nil {
+		return true
+	}
+
+	c.recordUse(key)
+
+	if len(c.data) < c.cache.Requirements.MaxEntries {
+		return true
+	}
+
+	victim, id, ok := c.victim()
+	if !ok || e.priority != id.priority {
+		//Entries of lower priority than the victim would be evicted straight away anyway
+		return !ok || e.priority > id.priority
+	}
+
+	return c.admission.estimate(scanHash(key)) > c.admission.estimate(scanHash(victim))
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//newFrequencySketch creates a sketch suitable for the cache of the size specified. Every row has at least four
+//counters per entry to keep collisions rare, and the counters are aged every ten uses per entry
+func newFrequencySketch(size int) *frequencySketch {
+	width := 64
+	for width < size*4 {
+		width *= 2
+	}
+
+	s := &frequencySketch{mask: uint64(width - 1), sample: width / 4 * 10}
+
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+
+	return s
+}
@@ -0,0 +1,80 @@
+package cacheMachine
+
+import (
+	"testing"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestRequirements_Admission(t *testing.T) {
+	c := New[int, int](&Requirements{MaxEntries: 10, Admission: true})
+
+	for i := 0; i < 10; i++ {
+		c.Add(i, i)
+
+		for j := 0; j < 5; j++ {
+			c.Get(i)
+		}
+	}
+
+	//One-off scan must not flush the entries read frequently
+	for i := 100; i < 120; i++ {
+		c.Add(i, i)
+	}
+
+	for i := 0; i < 10; i++ {
+		if !c.Exist(i) {
+			t.Errorf("Expected frequently read key %d to stay in the cache", i)
+		}
+	}
+
+	if c.Count() != 10 {
+		t.Errorf("Expected to have %d entries in the cache, got %d", 10, c.Count())
+	}
+
+	//Key used more often than the entries present is eventually admitted
+	for j := 0; j < 10; j++ {
+		c.Get(200)
+	}
+	c.Add(200, 200)
+
+	if !c.Exist(200) || c.Count() != 10 {
+		t.Errorf("Expected frequently read key %d to be admitted, got %t and %d entries", 200, c.Exist(200), c.Count())
+	}
+
+	//Replacing existing key is always admitted
+	c.Add(200, 201)
+
+	if c.GetValue(200) != 201 {
+		t.Errorf("Expected value of key %d to be replaced, got %d", 200, c.GetValue(200))
+	}
+}
+
+func TestFrequencySketch_age(t *testing.T) {
+	s := newFrequencySketch(1)
+
+	for i := 0; i < s.sample-1; i++ {
+		s.increment(uint64(i % 5))
+	}
+
+	if n := s.estimate(0); n != sketchMaxCount {
+		t.Errorf("Expected estimate to saturate at %d, got %d", sketchMaxCount, n)
+	}
+
+	s.increment(0)
+
+	if n := s.estimate(0); n > sketchMaxCount/2 {
+		t.Errorf("Expected counters to be halved, got estimate %d", n)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkRequirements_Admission(b *testing.B) {
+	c := New[int, int](&Requirements{MaxEntries: 100, Admission: true})
+
+	for n := 0; n < b.N; n++ {
+		c.Add(n%1000, n)
+		c.Get(n % 200)
+	}
+}
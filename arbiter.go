@@ -0,0 +1,134 @@
+package cacheMachine
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+//===========[INTERFACES]====================================================================================================
+
+//Budgeted is a cache whose cost Arbiter keeps within the budget. Cache of any key and value types is Budgeted
+type Budgeted interface {
+	Cost() int64
+	Count() int
+	EvictN(n int) int
+}
+
+//===========[STRUCTS]==================================================================================================
+
+//Arbiter keeps the total cost of all the caches registered with it within a single budget, e.g. so that caches owned
+//by different packages of one service can't run out of memory together. Whenever the budget is exceeded, every cache
+//evicts the same share of its entries until the total fits
+type Arbiter struct {
+	budget int64
+
+	members    map[int]Budgeted
+	nextMember int
+	mx         sync.Mutex
+}
+
+//------PRIVATE------
+
+//snapshot returns all the caches registered
+func (a *Arbiter) snapshot() []Budgeted {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	members := make([]Budgeted, 0, len(a.members))
+	for _, m := range a.members {
+		members = append(members, m)
+	}
+
+	return members
+}
+
+//------PUBLIC------
+
+//Register adds the cache to the caches kept within the budget. Call the function returned to remove it
+func (a *Arbiter) Register(c Budgeted) (unregister func()) {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	id := a.nextMember
+	a.nextMember++
+	a.members[id] = c
+
+	return func() {
+		a.mx.Lock()
+		delete(a.members, id)
+		a.mx.Unlock()
+	}
+}
+
+//Cost returns the total cost of all the caches registered
+func (a *Arbiter) Cost() int64 {
+	var total int64
+	for _, m := range a.snapshot() {
+		total += m.Cost()
+	}
+
+	return total
+}
+
+//Enforce evicts entries of all the caches registered in proportion to their number of entries until their total cost
+//fits within the budget, or there is nothing left to evict. Entries are chosen by the eviction policies of the caches.
+//Returns number of entries evicted
+func (a *Arbiter) Enforce() int {
+	members := a.snapshot()
+	evicted := 0
+
+	for {
+		var total int64
+		for _, m := range members {
+			total += m.Cost()
+		}
+
+		if total <= a.budget {
+			return evicted
+		}
+
+		share := float64(total-a.budget) / float64(total)
+		n := 0
+
+		for _, m := range members {
+			n += m.EvictN(int(math.Ceil(float64(m.Count()) * share)))
+		}
+
+		if n == 0 {
+			return evicted
+		}
+
+		evicted += n
+	}
+}
+
+//Watch starts a goroutine calling Enforce every interval. Call the function returned to stop it, calling it again
+//has no effect
+func (a *Arbiter) Watch(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	once := sync.Once{}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				a.Enforce()
+			}
+		}
+	}()
+
+	return func() { once.Do(func() { close(done) }) }
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//NewArbiter creates arbiter keeping the total cost of the caches registered with it within the budget supplied
+func NewArbiter(budget int64) *Arbiter {
+	return &Arbiter{budget: budget, members: make(map[int]Budgeted)}
+}
@@ -0,0 +1,60 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestArbiter_Enforce(t *testing.T) {
+	a := NewArbiter(60)
+
+	c1 := initializeFullCache(100, nil)
+	c2 := New[string, int](nil)
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		c2.Add(k, 1)
+	}
+
+	a.Register(&c1)
+	unregister := a.Register(&c2)
+
+	if cost := a.Cost(); cost != 110 {
+		t.Errorf("Expected total cost of 110, got %d", cost)
+	}
+
+	if n := a.Enforce(); n < 50 || a.Cost() > 60 {
+		t.Errorf("Expected at least 50 entries to be evicted to fit the budget, got %d evicted and cost %d", n, a.Cost())
+	}
+
+	if c1.Count() < 45 || c2.Count() < 4 || c2.Count() > 6 {
+		t.Errorf("Expected both caches to shrink in proportion, got %d and %d entries", c1.Count(), c2.Count())
+	}
+
+	unregister()
+
+	if cost := a.Cost(); cost != c1.Cost() {
+		t.Errorf("Expected the cache unregistered not to count, got %d", cost)
+	}
+}
+
+func TestArbiter_Watch(t *testing.T) {
+	a := NewArbiter(10)
+	c := initializeFullCache(0, nil)
+	a.Register(&c)
+
+	stop := a.Watch(time.Millisecond)
+	defer stop()
+
+	for i := 0; i < 100; i++ {
+		c.Add(i, i)
+	}
+
+	time.Sleep(time.Millisecond * 50)
+
+	if c.Count() > 10 {
+		t.Errorf("Expected the arbiter to shrink the cache to the budget, got %d entries", c.Count())
+	}
+
+	stop()
+}
@@ -0,0 +1,164 @@
+package cacheMachine
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//Defaults of ArchiveOptions
+const (
+	defaultArchiveQueueSize    = 1024
+	defaultArchiveMaxRetries   = 3
+	defaultArchiveRetryBackoff = time.Millisecond * 100
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//ArchiveOptions configures archiving of expired entries set by SetExpireArchive
+type ArchiveOptions struct {
+	//Number of expired entries waiting to be archived. Entries expiring while the queue is full are dropped.
+	//Defaults to 1024
+	QueueSize int
+
+	//Number of times a failed archive is retried before the entry is given up on. Set it to a negative number to
+	//disable retries. Defaults to 3
+	MaxRetries int
+
+	//Delay before the first retry. It doubles with every following retry. Defaults to 100ms
+	RetryBackoff time.Duration
+}
+
+//ArchiveStats counts the expired entries handed over to the archive function
+type ArchiveStats struct {
+	Archived uint64
+
+	//Entries given up on after all the retries failed
+	Failed uint64
+
+	//Entries that expired while the queue was full
+	Dropped uint64
+}
+
+//Expired entry waiting to be archived
+type archiveItem[TKey Key, TValue any] struct {
+	key TKey
+	val TValue
+}
+
+//Archives expired entries one at a time in the background
+type archiver[TKey Key, TValue any] struct {
+	archive func(TKey, TValue) error
+	opts    ArchiveOptions
+	queue   chan archiveItem[TKey, TValue]
+	stats   ArchiveStats
+	wg      sync.WaitGroup
+}
+
+//------PRIVATE------
+
+//enqueue schedules the entry to be archived, dropping it if the queue is full
+func (a *archiver[TKey, TValue]) enqueue(key TKey, val TValue) {
+	select {
+	case a.queue <- archiveItem[TKey, TValue]{key: key, val: val}:
+	default:
+		atomic.AddUint64(&a.stats.Dropped, 1)
+	}
+}
+
+//run archives the entries queued until the queue is closed
+func (a *archiver[TKey, TValue]) run() {
+	defer a.wg.Done()
+
+	for item := range a.queue {
+		a.send(item)
+	}
+}
+
+//send archives the entry, retrying failures with exponential backoff
+func (a *archiver[TKey, TValue]) send(item archiveItem[TKey, TValue]) {
+	backoff := a.opts.RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		if a.archive(item.key, item.val) == nil {
+			atomic.AddUint64(&a.stats.Archived, 1)
+			return
+		}
+
+		if attempt >= a.opts.MaxRetries {
+			atomic.AddUint64(&a.stats.Failed, 1)
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+//stop waits for all the entries queued to be archived. Nothing can be queued once stop is called
+func (a *archiver[TKey, TValue]) stop() {
+	close(a.queue)
+	a.wg.Wait()
+}
+
+//swapArchiver replaces the archiver of the cache and waits for the old one to archive the entries queued
+func (c *Cache[TKey, TValue]) swapArchiver(next *archiver[TKey, TValue]) {
+	c.mx.Lock()
+	old := c.archiver
+	c.archiver = next
+	c.mx.Unlock()
+
+	if old != nil {
+		old.stop()
+	}
+}
+
+//------PUBLIC------
+
+//SetExpireArchive sets the function archiving entries once they expire, e.g. to cold storage, so that they aren't
+//lost. Entries are archived one at a time by a background goroutine, failures are retried as configured by the
+//options. Entries queued when the function is replaced, or when the cache is closed, are archived first. Nil stops
+//archiving
+func (c *Cache[TKey, TValue]) SetExpireArchive(archive func(TKey, TValue) error, opts ArchiveOptions) {
+	if archive == nil {
+		c.swapArchiver(nil)
+		return
+	}
+
+	if opts.QueueSize < 1 {
+		opts.QueueSize = defaultArchiveQueueSize
+	}
+
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = defaultArchiveMaxRetries
+	}
+
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = defaultArchiveRetryBackoff
+	}
+
+	a := &archiver[TKey, TValue]{archive: archive, opts: opts, queue: make(chan archiveItem[TKey, TValue], opts.QueueSize)}
+	a.wg.Add(1)
+	go a.run()
+
+	c.swapArchiver(a)
+}
+
+//ArchiveStats returns the counts of the archiver set by SetExpireArchive. Returns zeros if there is no archiver
+func (c *Cache[TKey, TValue]) ArchiveStats() ArchiveStats {
+	c.mx.RLock()
+	a := c.archiver
+	c.mx.RUnlock()
+
+	if a == nil {
+		return ArchiveStats{}
+	}
+
+	return ArchiveStats{
+		Archived: atomic.LoadUint64(&a.stats.Archived),
+		Failed:   atomic.LoadUint64(&a.stats.Failed),
+		Dropped:  atomic.LoadUint64(&a.stats.Dropped),
+	}
+}
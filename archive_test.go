@@ -0,0 +1,86 @@
+package cacheMachine
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_SetExpireArchive(t *testing.T) {
+	c := New[int, int](nil)
+
+	var mx sync.Mutex
+	archived := make(map[int]int)
+	attempts := 0
+
+	c.SetExpireArchive(func(k, v int) error {
+		mx.Lock()
+		defer mx.Unlock()
+
+		if attempts++; attempts == 1 {
+			return errors.New("cold storage unavailable")
+		}
+
+		archived[k] = v
+		return nil
+	}, ArchiveOptions{RetryBackoff: time.Millisecond})
+
+	c.AddWithTimeout(1, 1, time.Millisecond)
+	c.Add(2, 2)
+	time.Sleep(time.Millisecond * 10)
+	c.Remove(2)
+
+	c.Close()
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	if len(archived) != 1 || archived[1] != 1 {
+		t.Errorf("Expected only the expired entry to be archived, got %v", archived)
+	}
+
+	if s := c.ArchiveStats(); s != (ArchiveStats{}) {
+		t.Errorf("Expected no archiver once the cache is closed, got %+v", s)
+	}
+}
+
+func TestCache_ArchiveStats(t *testing.T) {
+	c := New[int, int](nil)
+	defer c.Close()
+
+	block := make(chan struct{})
+	c.SetExpireArchive(func(k, v int) error {
+		<-block
+		return errors.New("failed")
+	}, ArchiveOptions{QueueSize: 1, MaxRetries: -1})
+
+	for i := 0; i < 3; i++ {
+		c.AddWithTimeout(i, i, time.Millisecond)
+	}
+
+	time.Sleep(time.Millisecond * 10)
+	close(block)
+	time.Sleep(time.Millisecond * 10)
+
+	if s := c.ArchiveStats(); s.Failed != 2 || s.Dropped != 1 {
+		t.Errorf("Expected 2 failed and 1 dropped entries, got %+v", s)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_SetExpireArchive(b *testing.B) {
+	c := New[int, int](nil)
+	defer c.Close()
+
+	c.SetExpireArchive(func(k, v int) error { return nil }, ArchiveOptions{})
+
+	for n := 0; n < b.N; n++ {
+		c.mx.Lock()
+		c.removed(n, &entry[int]{Val: n}, RemovalExpired)
+		c.mx.Unlock()
+	}
+}
@@ -0,0 +1,291 @@
+//Package benchmarks compares the cache against sync.Map, a map guarded by sync.RWMutex and ristretto across workloads
+//of different read/write ratios and writes the results as markdown or CSV reports. It's a module of its own, so that
+//the caches compared don't become dependencies of cacheMachine. Run "go test -bench ." within the module for the
+//standard benchmark output or "go run ./report" for the report
+package benchmarks
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgraph-io/ristretto"
+	"github.com/emillis/cacheMachine"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//Scenarios run by default, from read heavy to write heavy workloads
+var DefaultScenarios = []Scenario{
+	{Name: "read 100%", Reads: 1, Keys: 10000},
+	{Name: "read 90%", Reads: 0.9, Keys: 10000},
+	{Name: "read 50%", Reads: 0.5, Keys: 10000},
+	{Name: "read 10%", Reads: 0.1, Keys: 10000},
+}
+
+//Stores compared by default
+var DefaultStores = map[string]func() Store{
+	"cacheMachine": NewCacheStore,
+	"sync.Map":     NewSyncMapStore,
+	"RWMutex map":  NewMutexStore,
+	"ristretto":    NewRistrettoStore,
+}
+
+//Duration every scenario runs against every store for in the report, the same as the default of -benchtime
+const runDuration = time.Second
+
+//===========[INTERFACES]===============================================================================================
+
+//Store is the common interface of the key/value stores compared
+type Store interface {
+	Get(key int) (int, bool)
+	Set(key, val int)
+}
+
+//===========[STRUCTS]==================================================================================================
+
+//Scenario is a single workload run against every store
+type Scenario struct {
+	Name string
+
+	//Share of the operations that are reads, between 0 and 1. The rest are writes
+	Reads float64
+
+	//Number of distinct keys used. The store is filled with all of them before the run
+	Keys int
+}
+
+//Result is the outcome of a single scenario run against a single store
+type Result struct {
+	Store    string
+	Scenario string
+
+	NsPerOp     float64
+	AllocsPerOp int64
+}
+
+//Store backed by the cache
+type cacheStore struct {
+	c cacheMachine.Cache[int, int]
+}
+
+//Store backed by sync.Map
+type syncMapStore struct {
+	m sync.Map
+}
+
+//Store backed by a plain map guarded by sync.RWMutex
+type mutexStore struct {
+	m  map[int]int
+	mx sync.RWMutex
+}
+
+//Store backed by ristretto. Sets are buffered by ristretto, so values may not be readable straight away
+type ristrettoStore struct {
+	c *ristretto.Cache
+}
+
+//------PUBLIC------
+
+func (s *cacheStore) Get(key int) (int, bool) { return s.c.Get(key) }
+
+func (s *cacheStore) Set(key, val int) { s.c.Add(key, val) }
+
+func (s *syncMapStore) Get(key int) (int, bool) {
+	v, ok := s.m.Load(key)
+	if !ok {
+		return 0, false
+	}
+	return v.(int), true
+}
+
+func (s *syncMapStore) Set(key, val int) { s.m.Store(key, val) }
+
+func (s *mutexStore) Get(key int) (int, bool) {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+	v, ok := s.m[key]
+	return v, ok
+}
+
+func (s *mutexStore) Set(key, val int) {
+	s.mx.Lock()
+	s.m[key] = val
+	s.mx.Unlock()
+}
+
+func (s *ristrettoStore) Get(key int) (int, bool) {
+	v, ok := s.c.Get(key)
+	if !ok {
+		return 0, false
+	}
+	return v.(int), true
+}
+
+func (s *ristrettoStore) Set(key, val int) { s.c.Set(key, val, 1) }
+
+//Wait waits for the sets buffered by ristretto to be applied
+func (s *ristrettoStore) Wait() { s.c.Wait() }
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//NewCacheStore creates store backed by the cache with default requirements
+func NewCacheStore() Store {
+	return &cacheStore{c: cacheMachine.New[int, int](nil)}
+}
+
+//NewSyncMapStore creates store backed by sync.Map
+func NewSyncMapStore() Store {
+	return &syncMapStore{}
+}
+
+//NewMutexStore creates store backed by a plain map guarded by sync.RWMutex
+func NewMutexStore() Store {
+	return &mutexStore{m: make(map[int]int)}
+}
+
+//NewRistrettoStore creates store backed by ristretto sized so that the keys of the default scenarios are never
+//evicted
+func NewRistrettoStore() Store {
+	c, err := ristretto.NewCache(&ristretto.Config{NumCounters: 1e6, MaxCost: 1e5, BufferItems: 64})
+	if err != nil {
+		panic(err)
+	}
+
+	return &ristrettoStore{c: c}
+}
+
+//Fill adds all the keys of the scenario to the store, waiting for them to be applied if the store buffers sets
+func Fill(store Store, s Scenario) {
+	for i := 0; i < s.Keys; i++ {
+		store.Set(i, i)
+	}
+
+	if w, ok := store.(interface{ Wait() }); ok {
+		w.Wait()
+	}
+}
+
+//Op performs a single operation of the scenario picked using the random source supplied
+func Op(store Store, s Scenario, rnd *rand.Rand) {
+	key := rnd.Intn(s.Keys)
+
+	if rnd.Float64() < s.Reads {
+		store.Get(key)
+	} else {
+		store.Set(key, key)
+	}
+}
+
+//measure runs the scenario against a new store for the duration specified using GOMAXPROCS goroutines, the same
+//way b.RunParallel does
+func measure(newStore func() Store, s Scenario, d time.Duration) (nsPerOp float64, allocsPerOp int64) {
+	store := newStore()
+	Fill(store, s)
+
+	var stop int32
+	var ops int64
+	wg := sync.WaitGroup{}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	for g := 0; g < runtime.GOMAXPROCS(0); g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			rnd := rand.New(rand.NewSource(rand.Int63()))
+			n := int64(0)
+
+			for atomic.LoadInt32(&stop) == 0 {
+				Op(store, s, rnd)
+				n++
+			}
+
+			atomic.AddInt64(&ops, n)
+		}()
+	}
+
+	time.Sleep(d)
+	atomic.StoreInt32(&stop, 1)
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	if ops < 1 {
+		return 0, 0
+	}
+
+	return float64(elapsed.Nanoseconds()) / float64(ops), int64(after.Mallocs-before.Mallocs) / ops
+}
+
+//Run runs every scenario against every store and returns the results sorted by scenario and store
+func Run(stores map[string]func() Store, scenarios []Scenario) []Result {
+	var results []Result
+
+	for _, s := range scenarios {
+		for name, newStore := range stores {
+			ns, allocs := measure(newStore, s, runDuration)
+
+			results = append(results, Result{
+				Store:       name,
+				Scenario:    s.Name,
+				NsPerOp:     ns,
+				AllocsPerOp: allocs,
+			})
+		}
+	}
+
+	order := make(map[string]int, len(scenarios))
+	for i, s := range scenarios {
+		order[s.Name] = i
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Scenario != results[j].Scenario {
+			return order[results[i].Scenario] < order[results[j].Scenario]
+		}
+		return results[i].Store < results[j].Store
+	})
+
+	return results
+}
+
+//WriteMarkdown writes the results as a markdown table
+func WriteMarkdown(w io.Writer, results []Result) error {
+	if _, err := fmt.Fprintln(w, "| Scenario | Store | ns/op | allocs/op |\n|---|---|---:|---:|"); err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		if _, err := fmt.Fprintf(w, "| %s | %s | %.1f | %d |\n", r.Scenario, r.Store, r.NsPerOp, r.AllocsPerOp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//WriteCSV writes the results as CSV with a header row
+func WriteCSV(w io.Writer, results []Result) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"scenario", "store", "ns_per_op", "allocs_per_op"})
+
+	for _, r := range results {
+		cw.Write([]string{r.Scenario, r.Store, strconv.FormatFloat(r.NsPerOp, 'f', 1, 64), strconv.FormatInt(r.AllocsPerOp, 10)})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
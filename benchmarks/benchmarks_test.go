@@ -0,0 +1,127 @@
+package benchmarks
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/emillis/cacheMachine"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestWriteMarkdown(t *testing.T) {
+	buf := bytes.Buffer{}
+	results := []Result{{Store: "sync.Map", Scenario: "read 90%", NsPerOp: 12.34, AllocsPerOp: 1}}
+
+	if err := WriteMarkdown(&buf, results); err != nil {
+		t.Fatalf("Expected the report to be written, got error: %s", err)
+	}
+
+	if !strings.Contains(buf.String(), "| read 90% | sync.Map | 12.3 | 1 |") {
+		t.Errorf("Expected the result to be written as a table row, got:\n%s", buf.String())
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	buf := bytes.Buffer{}
+	results := []Result{{Store: "sync.Map", Scenario: "read 90%", NsPerOp: 12.34, AllocsPerOp: 1}}
+
+	if err := WriteCSV(&buf, results); err != nil {
+		t.Fatalf("Expected the report to be written, got error: %s", err)
+	}
+
+	if expected := "scenario,store,ns_per_op,allocs_per_op\nread 90%,sync.Map,12.3,1\n"; buf.String() != expected {
+		t.Errorf("Expected CSV %q, got %q", expected, buf.String())
+	}
+}
+
+func TestStores(t *testing.T) {
+	for name, newStore := range DefaultStores {
+		s := newStore()
+		s.Set(1, 10)
+		if w, ok := s.(interface{ Wait() }); ok {
+			w.Wait()
+		}
+
+		if v, ok := s.Get(1); !ok || v != 10 {
+			t.Errorf("Expected store %s to return value %d, got %d and %t", name, 10, v, ok)
+		}
+
+		if _, ok := s.Get(2); ok {
+			t.Errorf("Expected store %s not to find missing key", name)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	results := Run(DefaultStores, []Scenario{{Name: "read 50%", Reads: 0.5, Keys: 100}})
+
+	if len(results) != len(DefaultStores) || results[0].Store != "RWMutex map" {
+		t.Fatalf("Expected a result for every store sorted by name, got %+v", results)
+	}
+
+	for _, r := range results {
+		if r.NsPerOp <= 0 {
+			t.Errorf("Expected store %s to be measured, got %+v", r.Store, r)
+		}
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkStores(b *testing.B) {
+	for _, s := range DefaultScenarios {
+		for name, newStore := range DefaultStores {
+			b.Run(s.Name+"/"+name, func(b *testing.B) { bench(b, newStore, s) })
+		}
+	}
+}
+
+//BenchmarkReplayTrace replays the trace recorded by RecordTrace from the file specified in CACHEMACHINE_TRACE
+//environment variable, so that performance can be measured against real workloads
+func BenchmarkReplayTrace(b *testing.B) {
+	path := os.Getenv("CACHEMACHINE_TRACE")
+	if path == "" {
+		b.Skip("CACHEMACHINE_TRACE is not set")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer f.Close()
+
+	ops, err := cacheMachine.ReadTrace(f)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		c := cacheMachine.New[int64, struct{}](nil)
+		cacheMachine.ReplayTrace(&c, ops, struct{}{})
+	}
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//bench runs the scenario against a new store in parallel using all the goroutines b.RunParallel starts
+func bench(b *testing.B, newStore func() Store, s Scenario) {
+	store := newStore()
+	Fill(store, s)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		rnd := rand.New(rand.NewSource(rand.Int63()))
+
+		for pb.Next() {
+			Op(store, s, rnd)
+		}
+	})
+}
@@ -0,0 +1,26 @@
+//Command report runs the default benchmark scenarios and prints the comparison as markdown, or as CSV with -csv
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/emillis/cacheMachine/benchmarks"
+)
+
+func main() {
+	asCSV := flag.Bool("csv", false, "print the report as CSV rather than markdown")
+	flag.Parse()
+
+	results := benchmarks.Run(benchmarks.DefaultStores, benchmarks.DefaultScenarios)
+
+	write := benchmarks.WriteMarkdown
+	if *asCSV {
+		write = benchmarks.WriteCSV
+	}
+
+	if err := write(os.Stdout, results); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,98 @@
+package cacheMachine
+
+import (
+	"errors"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//ErrResultTooLarge is returned along with the values that fit when the result exceeds Requirements.MaxBulkResult
+var ErrResultTooLarge = errors.New("cacheMachine: result exceeds MaxBulkResult")
+
+//------PRIVATE------
+
+//getAll returns at most MaxBulkResult values stored in the cache. Returns true if there were more values than that
+func (c *Cache[TKey, TValue]) getAll() (map[TKey]TValue, bool) {
+	limit := c.cache.Requirements.MaxBulkResult
+
+	c.mx.RLock()
+	if c.cache.Requirements.LockChunkSize < 1 {
+		defer c.mx.RUnlock()
+		return c.copyValuesUpTo(limit)
+	}
+	keys := c.keys()
+	c.mx.RUnlock()
+
+	cpy := make(map[TKey]TValue, len(keys))
+	truncated := false
+
+	c.inChunks(keys, false, func(key TKey) {
+		e, exist := c.lookup(key)
+		if !exist {
+			return
+		}
+
+		if limit > 0 && len(cpy) >= limit {
+			truncated = true
+			return
+		}
+
+		cpy[key] = e.Val
+	})
+
+	return cpy, truncated
+}
+
+//getBulk returns at most MaxBulkResult values of the keys supplied. Returns true if more of the keys were found
+func (c *Cache[TKey, TValue]) getBulk(d []TKey) (map[TKey]TValue, bool) {
+	limit := c.cache.Requirements.MaxBulkResult
+	results := make(map[TKey]TValue)
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	for _, k := range d {
+		c.trace(TraceGet, k)
+
+		e, exist := c.lookup(k)
+		if !exist {
+			c.recordKey(k, false)
+			continue
+		}
+
+		if limit > 0 && len(results) >= limit {
+			return results, true
+		}
+
+		results[k] = e.Val
+		c.recordKey(k, true)
+		c.touch(k)
+		c.hit(e)
+	}
+
+	return results, false
+}
+
+//------PUBLIC------
+
+//GetAllChecked does the same as GetAll, but returns ErrResultTooLarge along with the values that fit if there are
+//more values than Requirements.MaxBulkResult
+func (c *Cache[TKey, TValue]) GetAllChecked() (map[TKey]TValue, error) {
+	cpy, truncated := c.getAll()
+	if truncated {
+		return cpy, ErrResultTooLarge
+	}
+
+	return cpy, nil
+}
+
+//GetBulkChecked does the same as GetBulk, but returns ErrResultTooLarge along with the values that fit if more than
+//Requirements.MaxBulkResult of the keys are found
+func (c *Cache[TKey, TValue]) GetBulkChecked(d []TKey) (map[TKey]TValue, error) {
+	results, truncated := c.getBulk(d)
+	if truncated {
+		return results, ErrResultTooLarge
+	}
+
+	return results, nil
+}
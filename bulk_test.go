@@ -0,0 +1,31 @@
+package cacheMachine
+
+import (
+	"testing"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestRequirements_MaxBulkResult(t *testing.T) {
+	for _, chunk := range []int{0, 3} {
+		c := initializeFullCache(10, &Requirements{MaxBulkResult: 4, LockChunkSize: chunk})
+
+		if all := c.GetAll(); len(all) != 4 {
+			t.Errorf("Expected GetAll to return 4 values with chunk size %d, got %d", chunk, len(all))
+		}
+
+		if _, err := c.GetAllChecked(); err != ErrResultTooLarge {
+			t.Errorf("Expected ErrResultTooLarge with chunk size %d, got %v", chunk, err)
+		}
+	}
+
+	c := initializeFullCache(10, &Requirements{MaxBulkResult: 4})
+
+	if bulk, err := c.GetBulkChecked([]int{1, 2, 3, 4, 100}); err != nil || len(bulk) != 4 {
+		t.Errorf("Expected 4 values without error, got %d values and %v", len(bulk), err)
+	}
+
+	if bulk, err := c.GetBulkChecked([]int{1, 2, 3, 4, 5}); err != ErrResultTooLarge || len(bulk) != 4 {
+		t.Errorf("Expected 4 values with ErrResultTooLarge, got %d values and %v", len(bulk), err)
+	}
+}
@@ -0,0 +1,106 @@
+package cacheMachine
+
+import (
+	"errors"
+	"time"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//ErrBusy is returned when the write lock couldn't be acquired within Requirements.MaxLockWait
+var ErrBusy = errors.New("cacheMachine: cache is busy")
+
+//Bounds of the pause between attempts to acquire the write lock within MaxLockWait
+const (
+	minLockBackoff = time.Microsecond * 10
+	maxLockBackoff = time.Millisecond
+)
+
+//------PRIVATE------
+
+//lockWithin acquires the write lock, giving up with ErrBusy once MaxLockWait passes. Readers are not held back while
+//waiting, so under heavy reads the lock may not be acquired at all until they pause
+func (c *Cache[TKey, TValue]) lockWithin() error {
+	wait := c.cache.Requirements.MaxLockWait
+	if wait <= 0 {
+		c.mx.Lock()
+		return nil
+	}
+
+	if c.mx.TryLock() {
+		return nil
+	}
+
+	deadline := time.Now().Add(wait)
+	backoff := minLockBackoff
+
+	for {
+		time.Sleep(backoff)
+
+		if c.mx.TryLock() {
+			return nil
+		}
+
+		if !time.Now().Before(deadline) {
+			return ErrBusy
+		}
+
+		if backoff *= 2; backoff > maxLockBackoff {
+			backoff = maxLockBackoff
+		}
+	}
+}
+
+//------PUBLIC------
+
+//TryAdd does the same as method "Add", but fails with ErrBusy instead of waiting for the write lock longer than
+//Requirements.MaxLockWait
+func (c *Cache[TKey, TValue]) TryAdd(key TKey, val TValue) (Entry[TValue], error) {
+	if err := c.lockWithin(); err != nil {
+		return nil, err
+	}
+	if c.isClosed() {
+		c.mx.Unlock()
+		return nil, c.closedErr()
+	}
+	e := c.add(key, val, 0, PriorityNormal)
+	c.mx.Unlock()
+
+	c.writeThrough(key, val)
+
+	return e, nil
+}
+
+//TryAddBulk does the same as method "AddBulk", but fails with ErrBusy instead of waiting for the write lock longer
+//than Requirements.MaxLockWait. Nothing is added in case of error. LockChunkSize is not applied
+func (c *Cache[TKey, TValue]) TryAddBulk(d map[TKey]TValue) error {
+	if err := c.lockWithin(); err != nil {
+		return err
+	}
+	if c.isClosed() {
+		c.mx.Unlock()
+		return c.closedErr()
+	}
+	for k, v := range d {
+		c.add(k, v, 0, PriorityNormal)
+	}
+	c.mx.Unlock()
+
+	for k, v := range d {
+		c.writeThrough(k, v)
+	}
+
+	return nil
+}
+
+//TryRemove does the same as method "Remove", but fails with ErrBusy instead of waiting for the write lock longer
+//than Requirements.MaxLockWait
+func (c *Cache[TKey, TValue]) TryRemove(key TKey) error {
+	if err := c.lockWithin(); err != nil {
+		return err
+	}
+	c.discard(key)
+	c.mx.Unlock()
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package cacheMachine
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestRequirements_MaxLockWait(t *testing.T) {
+	c := New[int, int](&Requirements{MaxLockWait: time.Millisecond * 10})
+
+	if _, err := c.TryAdd(1, 1); err != nil {
+		t.Fatalf("Expected no error adding to an idle cache, got %v", err)
+	}
+
+	c.mx.RLock()
+	start := time.Now()
+
+	_, addErr := c.TryAdd(2, 2)
+	bulkErr := c.TryAddBulk(map[int]int{3: 3})
+	removeErr := c.TryRemove(1)
+
+	elapsed := time.Since(start)
+	c.mx.RUnlock()
+
+	for _, err := range []error{addErr, bulkErr, removeErr} {
+		if !errors.Is(err, ErrBusy) {
+			t.Errorf("Expected %v while the cache is locked, got %v", ErrBusy, err)
+		}
+	}
+
+	if elapsed > time.Millisecond*200 {
+		t.Errorf("Expected the calls to give up after around %s each, took %s", time.Millisecond*10, elapsed)
+	}
+
+	if c.Count() != 1 || !c.Exist(1) {
+		t.Errorf("Expected the cache to be left as it was, got %v", c.GetAll())
+	}
+
+	if err := c.TryRemove(1); err != nil || c.Exist(1) {
+		t.Errorf("Expected the key to be removed once the cache is free, got %v", err)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_TryAdd(b *testing.B) {
+	c := New[int, int](&Requirements{MaxLockWait: time.Millisecond})
+
+	for n := 0; n < b.N; n++ {
+		c.TryAdd(n%100, n)
+	}
+}
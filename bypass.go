@@ -0,0 +1,63 @@
+package cacheMachine
+
+import (
+	"context"
+	"errors"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//ErrBypassed is returned instead of ErrClosed when the cache is closed and Requirements.SoftFail is set, meaning the
+//operation went on without the cache
+var ErrBypassed = errors.New("cacheMachine: cache bypassed")
+
+//------PRIVATE------
+
+//closedErr returns the error of operations that can't be done because the cache is closed
+func (c *Cache[TKey, TValue]) closedErr() error {
+	if c.cache.Requirements.SoftFail {
+		return ErrBypassed
+	}
+
+	return ErrClosed
+}
+
+//bypassLoad loads the value of the key straight away, without caching it, if Requirements.SoftFail is set
+func (c *Cache[TKey, TValue]) bypassLoad(ctx context.Context, key TKey, loader Loader[TKey, TValue]) (TValue, error) {
+	if !c.cache.Requirements.SoftFail {
+		var nilVal TValue
+		return nilVal, ErrClosed
+	}
+
+	v, err := loader(ctx, key)
+	if err != nil {
+		return v, err
+	}
+
+	return c.postProcess(key, v), nil
+}
+
+//bypassLoadBulk loads the values of the keys straight away, without caching them, if Requirements.SoftFail is set
+func (c *Cache[TKey, TValue]) bypassLoadBulk(ctx context.Context, keys []TKey, loader BulkLoader[TKey, TValue]) (map[TKey]TValue, error) {
+	if !c.cache.Requirements.SoftFail {
+		return nil, ErrClosed
+	}
+
+	d, err := loader(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := make(map[TKey]TValue, len(keys))
+	for _, key := range keys {
+		if v, found := d[key]; found {
+			loaded[key] = c.postProcess(key, v)
+		}
+	}
+
+	if len(loaded) < len(keys) {
+		return loaded, ErrKeyNotFound
+	}
+
+	return loaded, nil
+}
@@ -0,0 +1,55 @@
+package cacheMachine
+
+import (
+	"context"
+	"testing"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestRequirements_SoftFail(t *testing.T) {
+	c := New[int, int](&Requirements{SoftFail: true})
+	c.Close()
+
+	loads := 0
+	loader := func(ctx context.Context, key int) (int, error) {
+		loads++
+		return key * 10, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if v, err := c.GetOrLoad(context.Background(), 1, loader); err != nil || v != 10 {
+			t.Errorf("Expected the load to pass through the closed cache, got %d and %v", v, err)
+		}
+	}
+
+	if loads != 2 || c.Count() != 0 {
+		t.Errorf("Expected every load to reach the loader without caching, got %d loads and %d entries", loads, c.Count())
+	}
+
+	d, err := c.GetBulkOrLoad(context.Background(), []int{1, 2}, func(ctx context.Context, keys []int) (map[int]int, error) {
+		return map[int]int{1: 10}, nil
+	})
+
+	if err != ErrKeyNotFound || len(d) != 1 || d[1] != 10 {
+		t.Errorf("Expected the bulk load to pass through reporting missing keys, got %v and %v", d, err)
+	}
+
+	if _, err := c.TryAdd(1, 1); err != ErrBypassed {
+		t.Errorf("Expected %v, got %v", ErrBypassed, err)
+	}
+}
+
+func TestRequirements_SoftFail_off(t *testing.T) {
+	c := New[int, int](nil)
+	c.Close()
+
+	_, err := c.GetOrLoad(context.Background(), 1, func(ctx context.Context, key int) (int, error) {
+		t.Errorf("Expected the loader not to be called")
+		return 0, nil
+	})
+
+	if err != ErrClosed {
+		t.Errorf("Expected %v, got %v", ErrClosed, err)
+	}
+}
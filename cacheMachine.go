@@ -1,7 +1,11 @@
 package cacheMachine
 
 import (
+	"math/rand"
+	"sort"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -28,20 +32,215 @@ type BulkAdder[TKey Key, TValue any] interface {
 	AddBulk(d map[TKey]TValue)
 }
 
+type NewerBulkAdder[TKey Key, TValue any] interface {
+	AddBulkIfNewer(d map[TKey]TValue, newer func(a, b TValue) bool) int
+}
+
 type Entry[TValue any] interface {
 	Value() TValue
 	ResetTimer(time.Duration)
+	ExtendTimer(time.Duration)
+	SetDeadline(time.Time)
 	StopTimer()
 	TimerExist() bool
+	TTL() time.Duration
+	ExpiresAt() time.Time
+	Pin()
+	Unpin()
 }
 
 //===========[STRUCTS]==================================================================================================
 
+//Priority defines how important an entry is when the cache has to evict entries in order to stay within
+//Requirements.MaxEntries. Entries with lower priority are always evicted before entries with higher priority
+type Priority int
+
+const (
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 1
+)
+
+//String returns the name of the priority
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	}
+
+	return "Priority(" + strconv.Itoa(int(p)) + ")"
+}
+
+//ExpiryMode defines against which clock the timeouts of entries are evaluated
+type ExpiryMode int
+
+const (
+	//ExpiryMonotonic removes entries once their timers fire. Timers measure elapsed time on the monotonic clock,
+	//which doesn't advance while the machine is suspended, so entries can outlive their timeout by the time spent
+	//in suspension
+	ExpiryMonotonic ExpiryMode = iota
+
+	//ExpiryWallClock additionally checks the timeout against the wall clock whenever the entry is accessed.
+	//Entries past their wall clock deadline are reported as missing even if their timer hasn't fired yet, e.g.
+	//after resuming from suspension. Timers still remove such entries in the background
+	ExpiryWallClock
+)
+
+//String returns the name of the expiry mode
+func (m ExpiryMode) String() string {
+	switch m {
+	case ExpiryMonotonic:
+		return "monotonic"
+	case ExpiryWallClock:
+		return "wall clock"
+	}
+
+	return "ExpiryMode(" + strconv.Itoa(int(m)) + ")"
+}
+
 type Requirements struct {
 	//If this is set, by default, every cache entry will have a timeout of this duration after which
 	//the element will be removed from the cache. This timeout can be changed for individual entry
 	DefaultTimeout time.Duration
 
+	//If this is set, every entry given DefaultTimeout has a random duration of up to TimeoutJitter added to it, so
+	//that entries added at the same time, e.g. by a bulk load, don't all expire at the same instant
+	TimeoutJitter time.Duration
+
+	//If this is set, entries get no timers of their own. Instead, entries past their timeout are reported as missing
+	//straight away and removed by a janitor goroutine sweeping the whole cache every CleanupInterval, which saves
+	//the memory and scheduling of a timer per entry. Close stops the janitor. Idle timers of MaxIdleTime and time
+	//buckets are not affected
+	CleanupInterval time.Duration
+
+	//Maximum number of timers of entries scheduled at the same time, e.g. to put a ceiling on the timers of huge
+	//caches. Entries that would need a timer past the limit are handled according to TimerOverflow. 0 means there
+	//is no limit
+	MaxTimers int
+
+	//Defines what happens to entries with a timeout once MaxTimers timers are scheduled. Defaults to TimerOverflowLazy
+	TimerOverflow TimerOverflow
+
+	//If this is set, expired entries are kept as stale for this long instead of being forgotten straight away, so that
+	//GetStale can still serve them, e.g. while a fresh value is being loaded. Stale entries are not counted nor
+	//returned by any other method and get dropped once the key is added or removed again
+	StaleFor time.Duration
+
+	//Maximum number of values returned by GetAll and GetBulk, so that a single call can't copy the whole of a huge
+	//cache by accident. GetAllChecked and GetBulkChecked report ErrResultTooLarge when the limit cuts the result
+	//short, Scan and Visit are meant for going through more entries than that. 0 means there is no limit
+	MaxBulkResult int
+
+	//If this is set, a closed cache degrades to no caching instead of failing: GetOrLoad and GetBulkOrLoad pass
+	//straight through to their loaders and TryAdd and TryAddBulk fail with ErrBypassed instead of ErrClosed, so
+	//that callers can tell the value was not cached without treating it as an error. Otherwise the loads fail with
+	//ErrClosed as well
+	SoftFail bool
+
+	//Source of time for timeouts, deadlines and timers of entries, MaxIdleTime, time buckets and StaleFor, e.g.
+	//ManualClock in tests. Background goroutines such as the janitor of CleanupInterval tick by the system clock
+	//regardless. Defaults to the system clock
+	TimeSource TimeSource
+
+	//If this is set, entries that haven't been read for MaxIdleTime are removed, even if their timeout hasn't passed
+	//yet. Adding the entry counts as a read. Pinned entries are not removed. 0 means entries never become idle
+	MaxIdleTime time.Duration
+
+	//If this is set, every read of the entry, e.g. by Get or GetEntry, restarts its timer with the timeout it was
+	//last set to, so that entries are only removed once they haven't been read for their timeout. Entries without
+	//a timer and entries in time buckets are not affected
+	SlidingTimeout bool
+
+	//Maximum number of entries the cache can hold. When the limit is exceeded, an entry with the lowest Priority
+	//gets evicted as chosen by the eviction Policy. 0 means there is no limit
+	MaxEntries int
+
+	//Soft limit of the number of entries. Once the cache grows to HighWatermark entries, OnHighWatermark is called,
+	//e.g. to start shedding load, while nothing is evicted until MaxEntries is exceeded. It's called again only after
+	//the cache shrinks below HighWatermark and grows back. Both limits can be changed at runtime using SetWatermarks.
+	//0 means there is no soft limit
+	HighWatermark int
+
+	//Receives the number of entries once HighWatermark is reached. It's called in its own goroutine
+	OnHighWatermark func(entries int)
+
+	//Maximum total cost of the entries the cache can hold. When the limit is exceeded, entries are evicted the same
+	//way as when MaxEntries is exceeded. Cost of every entry is computed by the function set using SetCostFunc. If
+	//there's no such function, values implementing Sizer cost their size in bytes, making MaxCost a memory budget,
+	//and other values cost 1. 0 means there is no limit
+	MaxCost int64
+
+	//Built-in eviction policy used to choose which entry to evict within the same Priority. Defaults to FIFO.
+	//Custom policies can be set using SetEvictionPolicy method. If neither MaxEntries nor MaxCost is set, the policy
+	//only learns about the keys once a limit is set or entries are evicted, e.g. by EvictN, and reads made before that
+	//are not accounted for
+	Policy Policy
+
+	//Share of the keys of every Priority level the SLRU policy keeps in its protected segment, between 0 and 1.
+	//Defaults to 0.8
+	ProtectedRatio float64
+
+	//If this is set, entries of every owner, added using AddOwned, are evicted separately, so that one owner
+	//can't flush the entries of another. Within the lowest Priority, the victim is chosen from the owner that uses
+	//the largest share of its weight. Entries without an owner are treated as one more owner
+	FairEviction bool
+
+	//Weights of the owners used by FairEviction, e.g. proportional to their quotas. Owners not listed have weight 1
+	OwnerWeights map[string]int
+
+	//If this is set, every call of the callbacks supplied to ForEach, RemoveWhere, InvalidateN and GetOrLoad that runs longer
+	//than CallbackTimeout is reported to OnSlowCallback, or logged if OnSlowCallback is not set. Callbacks of
+	//RemoveWhere and InvalidateN run under the write lock, so slow callbacks block the whole cache
+	CallbackTimeout time.Duration
+
+	//Receives reports of slow callbacks. It's called right after the slow callback returns
+	OnSlowCallback func(SlowCallback)
+
+	//Maximum number of loads run by GetOrLoad at the same time, so that a storm of misses can't overwhelm the
+	//backing store. Further loads wait for a free slot. 0 means there is no limit
+	MaxConcurrentLoads int
+
+	//Maximum number of loads waiting for a free slot when MaxConcurrentLoads is set. Loads over this limit fail
+	//with ErrLoadQueueFull straight away. 0 means there is no limit
+	MaxQueuedLoads int
+
+	//Longest time TryAdd, TryAddBulk and TryRemove wait for the write lock, e.g. behind a long GetAll, before failing
+	//with ErrBusy. 0 means they wait as long as it takes, the same way as Add, AddBulk and Remove
+	MaxLockWait time.Duration
+
+	//Maximum number of writes pending in the write-through window before Healthy reports the cache unhealthy.
+	//0 means there is no limit
+	MaxPendingWrites int
+
+	//If this is set, it's called by Healthy to check whether the backing store is reachable
+	HealthCheck func() error
+
+	//Number of the most active keys for which KeyStats are retained. 0 means per-key statistics are off
+	TrackedKeys int
+
+	//Defines how timeouts of entries are evaluated. Defaults to ExpiryMonotonic
+	ExpiryMode ExpiryMode
+
+	//If this is set, timeouts of entries are lengthened or shortened depending on how often the entries are read.
+	//Entries in time buckets are not affected
+	AdaptiveTTL *AdaptiveTTL
+
+	//If this is set, GetAll, Reset, RemoveWhere and AddBulk, and so merges, release and re-acquire the lock every
+	//LockChunkSize entries so that they never block other goroutines for the whole operation. Such operations
+	//are no longer atomic: GetAll may miss changes made while it runs and AddBulk may be partially visible.
+	//0 means these operations hold the lock for their whole duration
+	LockChunkSize int
+
+	//If this is set along with MaxEntries, a new key enters the full cache only if it's estimated to be used more
+	//often than the entry that would be evicted to make room for it, so that one-off scans can't flush frequently
+	//used entries. Uses are counted as in TinyLFU: both reads, including misses, and additions of every key are
+	//recorded in a compact sketch that forgets old uses over time. Replacing values of existing keys is always admitted
+	Admission bool
+
 	//Defines whether the DefaultTimeout is in use
 	timeoutInUse bool
 }
@@ -51,8 +250,81 @@ type entry[TValue any] struct {
 	//The value stored in the cache
 	Val TValue `json:"value" bson:"value"`
 
-	//This is the timer that monitors auto-removal of the element
-	timer *time.Timer
+	//This is the timer that monitors auto-removal of the element. Nil if the entry has no timeout or the janitor of
+	//Requirements.CleanupInterval removes it instead
+	timer Timer
+
+	//Wall clock time at which the timer is due. Zero if the timer is stopped or there is no timer
+	deadline time.Time
+
+	//Clock of the cache the entry belongs to
+	clock TimeSource
+
+	//Counter of the timers scheduled by the cache, updated as the timer of the entry is stopped and restarted
+	timers *int64
+
+	//Defines whether the entry expires without a timer because MaxTimers was reached
+	lazy bool
+
+	//Defines whether the entry was refused because MaxTimers was reached
+	refused bool
+
+	//Time remaining until the timeout while expiry is paused by PauseExpiry. Zero if the entry isn't paused
+	paused time.Duration
+
+	//Defines whether expiry is paused by PauseExpiry, so that changes of the timer only update the time remaining
+	frozen bool
+
+	//Duration the timer was last set to
+	ttl time.Duration
+
+	//Wall clock time after which the entry is served as stale. Zero if the entry never goes stale
+	staleAt time.Time
+
+	//Time as of which the value was current at its source, as supplied by AddWithOrigin. Zero if unknown
+	origin time.Time
+
+	//Number of reads since the timer was last set, counted for AdaptiveTTL only
+	hits uint32
+
+	//Priority of the entry used when choosing which entry to evict
+	priority Priority
+
+	//Expiry boundary of the bucket this entry belongs to. Zero if the entry is not in a bucket
+	bucket time.Time
+
+	//Owner the entry belongs to. Empty if the entry has no owner
+	owner string
+
+	//Generation of the eviction policy the entry is registered with
+	gen uint32
+
+	//Time the entry was stored at
+	created time.Time
+
+	//Number of reads of the entry
+	reads uint32
+
+	//Time of the last read, or of the addition if there was no read, in unix nanoseconds
+	lastRead int64
+
+	//Value of the change counter of the cache at which the entry was stored
+	version uint64
+
+	//Timer removing the entry once it's idle for too long. Nil if MaxIdleTime is not set
+	idle Timer
+
+	//Cost of the entry counted towards MaxCost
+	cost int64
+
+	//Metadata supplied along with the value, e.g. by AddWithMetadata. Nil if there is none
+	meta Metadata
+
+	//Pinned entries are neither evicted nor expired. Pinning is protected by the lock of the cache
+	pinned bool
+
+	//Pins or unpins the entry in the cache it's stored in. Nil if the entry has never been stored
+	pin func(bool)
 
 	//Locks
 	mx sync.RWMutex
@@ -62,16 +334,48 @@ type entry[TValue any] struct {
 
 //Resets timeout duration to the duration specified. If 0 is supplied, it stops the timer
 func (e *entry[TValue]) resetTimer(t time.Duration) {
-	if e.timer == nil {
+	if e.timer == nil && e.ttl < 1 {
+		return
+	}
+
+	//Timers stay stopped while expiry is paused, the countdown starts from the time remaining on ResumeExpiry
+	if e.frozen {
+		e.paused = t
+		if t > 0 {
+			e.ttl = t
+		}
 		return
 	}
 
 	if t.String() == "0s" {
-		e.timer.Stop()
+		if e.timer != nil && e.timer.Stop() {
+			e.countTimer(-1)
+		}
+		e.deadline = time.Time{}
 		return
 	}
 
-	e.timer.Reset(t)
+	if e.timer != nil && !e.timer.Reset(t) {
+		e.countTimer(1)
+	}
+	e.deadline = e.now().Add(t)
+	e.ttl = t
+}
+
+//expired checks whether the wall clock deadline of the entry has passed
+func (e *entry[TValue]) expired() bool {
+	e.mx.RLock()
+	defer e.mx.RUnlock()
+	return !e.deadline.IsZero() && !e.now().Before(e.deadline)
+}
+
+//now returns current wall clock time of the clock of the cache the entry belongs to
+func (e *entry[TValue]) now() time.Time {
+	if e.clock == nil {
+		return wallClock()
+	}
+
+	return e.clock.Now().Round(0)
 }
 
 //------PUBLIC------
@@ -88,59 +392,694 @@ func (e *entry[TValue]) ResetTimer(t time.Duration) {
 	e.mx.Unlock()
 }
 
+//ExtendTimer adds the duration to the time remaining until the removal of this entry. It has no effect if the timer
+//is stopped or there is no timer
+func (e *entry[TValue]) ExtendTimer(d time.Duration) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	if e.frozen {
+		if e.paused < 1 {
+			return
+		}
+
+		remaining := e.paused + d
+		if remaining < 1 {
+			remaining = time.Nanosecond
+		}
+
+		e.resetTimer(remaining)
+		return
+	}
+
+	if e.deadline.IsZero() {
+		return
+	}
+
+	e.resetTimer(e.deadline.Sub(e.now()) + d)
+}
+
+//SetDeadline resets the timer so that this entry is removed at the wall clock time specified, e.g. the expiry of a
+//token. Deadlines that have passed already remove the entry straight away. Same as ResetTimer, it has no effect if the
+//entry has no timeout
+func (e *entry[TValue]) SetDeadline(t time.Time) {
+	e.mx.Lock()
+	e.resetTimer(untilDeadline(t, e.now()))
+	e.mx.Unlock()
+}
+
 //TimerExist checks whether the timer exist and returns boolean accordingly
 func (e *entry[TValue]) TimerExist() bool {
-	if e.timer != nil {
+	e.mx.RLock()
+	defer e.mx.RUnlock()
+
+	if e.timer != nil || e.ttl > 0 {
 		return true
 	}
 
 	return false
 }
 
-//StopTimer stops the countdown timer until the element is removed
-func (e *entry[TValue]) StopTimer() {
-	if e.timer == nil {
-		return
+//ExpiresAt returns the wall clock time at which the entry expires, whether by its timer or its time bucket. Zero
+//if the entry never expires
+func (e *entry[TValue]) ExpiresAt() time.Time {
+	if !e.bucket.IsZero() {
+		return e.bucket
+	}
+
+	e.mx.RLock()
+	defer e.mx.RUnlock()
+
+	return e.deadline
+}
+
+//TTL returns the time remaining until the entry expires, e.g. for the max-age of Cache-Control headers. Zero if
+//the entry never expires or is due to expire already
+func (e *entry[TValue]) TTL() time.Duration {
+	at := e.ExpiresAt()
+	if at.IsZero() {
+		return 0
+	}
+
+	if ttl := at.Sub(e.now()); ttl > 0 {
+		return ttl
+	}
+
+	return 0
+}
+
+//Pin protects the entry from eviction and expiry until Unpin is called. It has no effect once the entry has been
+//removed or replaced
+func (e *entry[TValue]) Pin() {
+	if e.pin != nil {
+		e.pin(true)
+	}
+}
+
+//Unpin makes the entry subject to eviction and expiry again
+func (e *entry[TValue]) Unpin() {
+	if e.pin != nil {
+		e.pin(false)
 	}
+}
 
+//StopTimer stops the countdown timer until the element is removed
+func (e *entry[TValue]) StopTimer() {
 	e.mx.Lock()
 	e.resetTimer(0)
 	e.mx.Unlock()
 }
 
+//Group of keys that all expire together at the boundary of the bucket
+type bucket[TKey Key] struct {
+	keys  map[TKey]struct{}
+	timer Timer
+}
+
 //Cache is the main definition of the cache
 type cache[TKey Key, TValue any] struct {
 	Requirements Requirements
 	data         map[TKey]*entry[TValue]
-	mx           sync.RWMutex
+
+	//Eviction bookkeeping for each priority level and, if FairEviction is on, for each owner within it
+	levels map[levelID]*level[TKey]
+
+	//Creates eviction policy for a new priority level
+	newPolicy func() EvictionPolicy[TKey]
+
+	//Defines whether the eviction policy was set using SetEvictionPolicy rather than being built-in
+	customPolicy bool
+
+	//Defines whether keys are kept out of the eviction policies because the cache has no limit, so that neither
+	//adds nor reads pay for the bookkeeping. Keys are handed over to the policies by track once they are needed
+	untracked bool
+
+	//Generation of the eviction policy in use, incremented by every migration
+	gen uint32
+
+	//Levels of the previous eviction policy and its constructor while the keys are being migrated to the current
+	//one. Nil if no migration is in progress
+	drainLevels map[levelID]*level[TKey]
+	drainPolicy func() EvictionPolicy[TKey]
+
+	//Allows only one migration of the eviction policy at a time
+	migrateMx sync.Mutex
+
+	//Hooks set by SetHooks for tests
+	hooks atomic.Value
+
+	//Coalescer of writes set by SetWriteThrough
+	writer atomic.Value
+
+	//Post-processing of loaded values set by SetPostLoad
+	postLoad atomic.Value
+
+	//Middleware set by Use along with the operation composed of it
+	middleware []Middleware[TKey, TValue]
+	operation  atomic.Value
+
+	//Called for every entry leaving the cache, set by SetOnRemove
+	onRemove func(TKey, TValue, RemovalReason)
+
+	//Called as keys appear in and disappear from the cache, set by SetKeyTransitions
+	transitions *KeyTransitions[TKey, TValue]
+
+	//Archives expired entries, set by SetExpireArchive
+	archiver *archiver[TKey, TValue]
+
+	//Functions subscribed to the changes of the cache by their ids
+	listeners    map[int]func(Event[TKey])
+	nextListener int
+
+	//Functions receiving expired entries by the ids of their subscriptions, which share the ids with listeners. Nil
+	//until WatchExpired is used
+	expiryListeners map[int]func(TKey, TValue)
+
+	//Computes costs of entries set by SetCostFunc. Nil if every entry costs 1
+	costFunc func(TKey, TValue) int64
+
+	//Total cost of all the entries
+	cost int64
+
+	//Number of timers of entries scheduled at the moment
+	timers int64
+
+	//Defines whether timeouts of entries are frozen by PauseExpiry
+	expiryPaused bool
+
+	//Expired entries kept by StaleFor. Nil until the first entry goes stale
+	stale map[TKey]*staleEntry[TValue]
+
+	//Counter of the changes made to the cache, and the changes at which the keys were removed. Removals are only
+	//recorded once SaveIncremental is used
+	version    uint64
+	tombstones map[TKey]uint64
+
+	//Computes timeouts of entries added without one, set by SetTTLFunc
+	ttlFunc func(TKey, TValue) time.Duration
+
+	//Breaks entries down into classes for ClassStats, set by SetClassifier
+	classifier func(TValue) string
+
+	//Number of pinned entries
+	pinned int
+
+	//Closed by Close to stop the background goroutines of the cache
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	//Defines whether the cache has reached HighWatermark and hasn't shrunk below it since
+	aboveWatermark bool
+
+	//Encodes values in snapshots. Nil if encoding/gob is used directly
+	codec EntryCodec[TValue]
+
+	//Limits concurrent loads. Nil if there is no limit
+	loads *loadLimiter
+
+	//Loads in progress by key, shared by GetOrLoad and GetBulkOrLoad
+	flights  map[TKey]*loadCall[TValue]
+	flightMx sync.Mutex
+
+	//Protects eviction policies when keys are read under the read lock
+	policyMx sync.Mutex
+
+	//Time buckets indexed by their expiry boundary in unix nanoseconds
+	buckets map[int64]*bucket[TKey]
+
+	//Keys of the entries belonging to each owner
+	owners map[string]map[TKey]struct{}
+
+	//Groups of the entries added by AddGroup by the keys of their members. Nil until AddGroup is used
+	groups map[TKey]group[TKey]
+
+	//Keys declared dependent on each key by DependOn, and the other way around. Nil until DependOn is used
+	dependents   map[TKey]map[TKey]struct{}
+	dependencies map[TKey]map[TKey]struct{}
+
+	//Records operations performed on the cache. Nil if recording is off
+	tracer *tracer
+
+	//Statistics of the most active keys. Nil if per-key statistics are off
+	keyStats *keySketch[TKey]
+
+	//Estimates how often keys are used for the admission filter. Nil if admission is off
+	admission *frequencySketch
+
+	mx sync.RWMutex
 }
 type Cache[TKey Key, TValue any] struct {
-	cache[TKey, TValue]
+	*cache[TKey, TValue]
 }
 
 //------PRIVATE------
 
+//addLoaded adds the value loaded from the backing store without writing it through, along with the metadata supplied
+func (c *Cache[TKey, TValue]) addLoaded(key TKey, val TValue, meta Metadata) {
+	c.mx.Lock()
+	e := c.newEntry(key, val, 0, PriorityNormal)
+	e.meta = meta
+	c.insert(key, e)
+	c.mx.Unlock()
+}
+
 //add method adds an item. This method has no mutex protection
-func (c *Cache[TKey, TValue]) add(key TKey, val TValue, t time.Duration) Entry[TValue] {
-	e := entry[TValue]{
-		Val: val,
-		mx:  sync.RWMutex{},
+func (c *Cache[TKey, TValue]) add(key TKey, val TValue, t time.Duration, p Priority) *entry[TValue] {
+	e := c.newEntry(key, val, t, p)
+	c.insert(key, e)
+	return e
+}
+
+//newEntry creates an entry for the key and starts its timer, if the entry should have one
+func (c *Cache[TKey, TValue]) newEntry(key TKey, val TValue, t time.Duration, p Priority) *entry[TValue] {
+	e := &entry[TValue]{
+		Val:      val,
+		priority: p,
+		clock:    c.cache.Requirements.TimeSource,
+		frozen:   c.expiryPaused,
+		mx:       sync.RWMutex{},
+	}
+
+	//Negative timeouts computed by the TTL function mean the entry has no timeout at all
+	if t.String() == "0s" && c.ttlFunc != nil {
+		if t = c.ttlFunc(key, val); t < 0 {
+			return e
+		}
 	}
 
 	//Timer implementation
 	if t.String() != "0s" || c.cache.Requirements.timeoutInUse {
 		if t.String() == "0s" {
 			t = c.cache.Requirements.DefaultTimeout
+
+			if j := c.cache.Requirements.TimeoutJitter; j > 0 {
+				t += time.Duration(rand.Int63n(int64(j) + 1))
+			}
+		}
+
+		if c.expiryPaused {
+			e.paused, e.ttl = t, t
+			return e
 		}
 
-		e.timer = time.AfterFunc(t, func() {
-			c.Remove(key)
-		})
+		if !c.startTimer(key, e, t) {
+			e.refused = true
+			return e
+		}
+		e.deadline = c.now().Add(t)
+		e.ttl = t
 	}
 
-	c.data[key] = &e
+	return e
+}
 
-	return &e
+//expire removes the entry once its timer fires, but only if the key still holds this very entry. This guarantees
+//that the expiry of an entry never removes a newer entry that has replaced it under the same key
+func (c *Cache[TKey, TValue]) expire(key TKey, e *entry[TValue]) {
+	c.beforeExpire(key)
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.expiryPaused {
+		return
+	}
+
+	if current, exist := c.data[key]; exist && current == e && !e.pinned && !c.adapt(e) {
+		c.remove(key)
+		c.emit(EventExpire, key, e)
+	}
+}
+
+//insert stores the entry under the key specified, replacing any existing entry, and evicts entries if the
+//cache grows past MaxEntries or MaxCost. New keys refused by the admission filter are not stored at all, neither are
+//entries refused because MaxTimers was reached. This method has no mutex protection
+func (c *Cache[TKey, TValue]) insert(key TKey, e *entry[TValue]) {
+	if e.refused {
+		return
+	}
+
+	if c.isClosed() {
+		e.StopTimer()
+		return
+	}
+
+	c.dropStale(key)
+
+	old, exist := c.data[key]
+	if exist {
+		c.unlink(key, old)
+		c.unbucket(key, old)
+		c.disown(key, old)
+		c.ungroup(key)
+		old.StopTimer()
+		old.stopIdle()
+		c.cost -= old.cost
+		c.removed(key, old, RemovalReplaced)
+
+		//Pins survive replacing the value of the key
+		if old.pinned {
+			e.pinned = true
+			c.pinned--
+		}
+	} else if !c.admit(key, e) {
+		e.StopTimer()
+		return
+	}
+
+	c.version++
+	e.version = c.version
+	delete(c.tombstones, key)
+
+	e.created = c.now()
+	e.lastRead = e.created.UnixNano()
+	c.startIdle(key, e)
+	e.cost = c.costOf(key, e.Val)
+	c.cost += e.cost
+	c.recordBytes(key, e.cost)
+	e.pin = func(pinned bool) { c.setPinned(key, e, pinned) }
+
+	c.data[key] = e
+	if e.pinned {
+		c.pinned++
+	} else {
+		c.link(key, e)
+	}
+	c.trace(TraceAdd, key)
+	c.emit(EventAdd, key, e)
+
+	if !exist && c.transitions != nil && c.transitions.OnPresent != nil {
+		c.transitions.OnPresent(key, e.Val)
+	}
+
+	if !e.bucket.IsZero() {
+		c.addToBucket(key, e.bucket)
+	}
+
+	if e.owner != "" {
+		c.own(key, e.owner)
+	}
+
+	c.evict()
+	c.checkWatermark()
+}
+
+//addToBucket registers the key in the bucket with the boundary specified, creating the bucket and its timer
+//if it doesn't exist yet. This method has no mutex protection
+func (c *Cache[TKey, TValue]) addToBucket(key TKey, boundary time.Time) {
+	id := boundary.UnixNano()
+
+	b, exist := c.buckets[id]
+	if !exist {
+		b = &bucket[TKey]{keys: make(map[TKey]struct{})}
+		b.timer = c.cache.Requirements.TimeSource.AfterFunc(boundary.Sub(c.now()), func() { c.expireBucket(id, b) })
+		c.buckets[id] = b
+	}
+
+	b.keys[key] = struct{}{}
+}
+
+//unbucket removes the key from the bucket the entry belongs to. Empty buckets are discarded along with
+//their timers. This method has no mutex protection
+func (c *Cache[TKey, TValue]) unbucket(key TKey, e *entry[TValue]) {
+	if e.bucket.IsZero() {
+		return
+	}
+
+	id := e.bucket.UnixNano()
+
+	b, exist := c.buckets[id]
+	if !exist {
+		return
+	}
+
+	delete(b.keys, key)
+
+	if len(b.keys) < 1 {
+		b.timer.Stop()
+		delete(c.buckets, id)
+	}
+}
+
+//expireBucket removes all the keys that are still present in the bucket along with the bucket itself. Nothing is
+//removed if the bucket has been replaced by a newer bucket with the same boundary
+func (c *Cache[TKey, TValue]) expireBucket(id int64, b *bucket[TKey]) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if current, exist := c.buckets[id]; !exist || current != b {
+		return
+	}
+
+	delete(c.buckets, id)
+
+	for key := range b.keys {
+		e, exist := c.data[key]
+		if !exist || e.pinned {
+			continue
+		}
+
+		c.remove(key)
+		c.emit(EventExpire, key, e)
+	}
+}
+
+//own registers the key in the index of the owner specified. This method has no mutex protection
+func (c *Cache[TKey, TValue]) own(key TKey, owner string) {
+	keys, exist := c.owners[owner]
+	if !exist {
+		keys = make(map[TKey]struct{})
+		c.owners[owner] = keys
+	}
+
+	keys[key] = struct{}{}
+}
+
+//disown removes the key from the index of the owner the entry belongs to. This method has no mutex protection
+func (c *Cache[TKey, TValue]) disown(key TKey, e *entry[TValue]) {
+	if e.owner == "" {
+		return
+	}
+
+	keys, exist := c.owners[e.owner]
+	if !exist {
+		return
+	}
+
+	delete(keys, key)
+
+	if len(keys) < 1 {
+		delete(c.owners, e.owner)
+	}
+}
+
+//levelOf returns identifier of the eviction level the entry belongs to
+func (c *Cache[TKey, TValue]) levelOf(e *entry[TValue]) levelID {
+	if c.cache.Requirements.FairEviction {
+		return levelID{priority: e.priority, owner: e.owner}
+	}
+
+	return levelID{priority: e.priority}
+}
+
+//ownerWeight returns the weight of the owner used by FairEviction
+func (c *Cache[TKey, TValue]) ownerWeight(owner string) int {
+	if w, exist := c.cache.Requirements.OwnerWeights[owner]; exist && w > 0 {
+		return w
+	}
+
+	return 1
+}
+
+//link registers the key with the eviction policy of the priority level of the entry. While the eviction policy is
+//being migrated, new keys join the policies being drained, so that they are migrated in the order they were
+//added. This method has no mutex protection
+func (c *Cache[TKey, TValue]) link(key TKey, e *entry[TValue]) {
+	if c.untracked {
+		return
+	}
+
+	if c.drainLevels != nil {
+		e.gen = c.gen - 1
+		c.linkInto(c.drainLevels, c.drainPolicy, key, e)
+		return
+	}
+
+	e.gen = c.gen
+	c.linkInto(c.levels, c.newPolicy, key, e)
+}
+
+//linkInto registers the key with the eviction policy of the level among the levels supplied, creating the level
+//using the constructor supplied if it doesn't exist yet. This method has no mutex protection
+func (c *Cache[TKey, TValue]) linkInto(levels map[levelID]*level[TKey], newPolicy func() EvictionPolicy[TKey], key TKey, e *entry[TValue]) {
+	id := c.levelOf(e)
+
+	l, exist := levels[id]
+	if !exist {
+		l = &level[TKey]{policy: newPolicy()}
+		if p, ok := l.policy.(ReadIgnoringPolicy); ok {
+			l.ignoresReads = p.IgnoresReads()
+		}
+		levels[id] = l
+	}
+
+	l.policy.OnAdd(key)
+	l.size++
+}
+
+//track hands all the keys over to the eviction policies in the order they were added, unless they are tracked
+//already, and keeps the policies informed from then on. It's called once a limit is set or entries are evicted by
+//the eviction policy of a cache that had no limit. This method has no mutex protection
+func (c *Cache[TKey, TValue]) track() {
+	if !c.untracked {
+		return
+	}
+	c.untracked = false
+
+	keys := make([]TKey, 0, len(c.data)-c.pinned)
+	for key, e := range c.data {
+		if !e.pinned {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return c.data[keys[i]].version < c.data[keys[j]].version })
+
+	for _, key := range keys {
+		c.link(key, c.data[key])
+	}
+}
+
+//levelsOf returns the eviction levels the entry is registered with. This method has no mutex protection
+func (c *Cache[TKey, TValue]) levelsOf(e *entry[TValue]) map[levelID]*level[TKey] {
+	if c.drainLevels != nil && e.gen != c.gen {
+		return c.drainLevels
+	}
+
+	return c.levels
+}
+
+//unlink removes the key from the eviction policy of the priority level of the entry. This method has no mutex protection
+func (c *Cache[TKey, TValue]) unlink(key TKey, e *entry[TValue]) {
+	if e.pinned || c.untracked {
+		return
+	}
+
+	levels := c.levelsOf(e)
+	id := c.levelOf(e)
+
+	l, exist := levels[id]
+	if !exist {
+		return
+	}
+
+	l.policy.OnRemove(key)
+	l.size--
+
+	if l.size < 1 {
+		delete(levels, id)
+	}
+}
+
+//touch notifies the eviction policy that the key has been read. It's safe to call this method under the read lock
+func (c *Cache[TKey, TValue]) touch(key TKey) {
+	if c.untracked {
+		return
+	}
+
+	e, exist := c.data[key]
+	if !exist || e.pinned {
+		return
+	}
+
+	l, exist := c.levelsOf(e)[c.levelOf(e)]
+	if !exist || l.ignoresReads {
+		return
+	}
+
+	c.policyMx.Lock()
+	l.policy.OnGet(key)
+	c.policyMx.Unlock()
+}
+
+//lowestLevel returns the level among the levels supplied that should give up an entry first: the level of the
+//lowest priority and, within it, of the owner using the largest share of its weight
+func (c *Cache[TKey, TValue]) lowestLevel(levels map[levelID]*level[TKey]) (*level[TKey], levelID) {
+	var lowest *level[TKey]
+	var lowestID levelID
+
+	for id, l := range levels {
+		if lowest == nil || id.priority < lowestID.priority ||
+			id.priority == lowestID.priority && l.size*c.ownerWeight(lowestID.owner) > lowest.size*c.ownerWeight(id.owner) {
+			lowest, lowestID = l, id
+		}
+	}
+
+	return lowest, lowestID
+}
+
+//victim returns the key that should be evicted next along with its level. Returns false if there's no such key.
+//This method has no mutex protection
+func (c *Cache[TKey, TValue]) victim() (TKey, levelID, bool) {
+	//Migrated keys were the first in line to be evicted, so they go before the keys still being drained
+	lowest, lowestID := c.lowestLevel(c.levels)
+
+	if drained, drainedID := c.lowestLevel(c.drainLevels); drained != nil && (lowest == nil || drainedID.priority < lowestID.priority) {
+		lowest, lowestID = drained, drainedID
+	}
+
+	var nilKey TKey
+
+	if lowest == nil {
+		return nilKey, lowestID, false
+	}
+
+	key, ok := lowest.policy.Victim()
+	if _, exist := c.data[key]; !ok || !exist {
+		return nilKey, lowestID, false
+	}
+
+	return key, lowestID, true
+}
+
+//evict removes entries chosen by the eviction policy of the lowest priority level until the cache fits within
+//MaxEntries and MaxCost. This method has no mutex protection
+func (c *Cache[TKey, TValue]) evict() {
+	c.evictN(-1)
+}
+
+//evictN does the same as evict, but removes at most n entries, unless n is negative. Returns number of entries
+//removed. This method has no mutex protection
+func (c *Cache[TKey, TValue]) evictN(n int) int {
+	removed := 0
+
+	for c.overLimit() && (n < 0 || removed < n) && c.evictVictim() {
+		removed++
+	}
+
+	return removed
+}
+
+//evictVictim evicts the key chosen by the eviction policy of the lowest priority level regardless of the limits.
+//Returns false if there's nothing to evict. This method has no mutex protection
+func (c *Cache[TKey, TValue]) evictVictim() bool {
+	c.track()
+
+	key, _, ok := c.victim()
+	if !ok {
+		return false
+	}
+
+	c.evictKey(key)
+
+	return true
+}
+
+//evictKey evicts the key specified. This method has no mutex protection
+func (c *Cache[TKey, TValue]) evictKey(key TKey) {
+	e := c.data[key]
+	c.remove(key)
+	c.emit(EventEvict, key, e)
+	c.afterEvict(key)
 }
 
 //addTImer adds new timer with specified duration if it doesn't yet exist. If timer is already present,
@@ -152,38 +1091,193 @@ func (c *Cache[TKey, TValue]) addTimer(key TKey, t time.Duration) {
 		return
 	}
 
-	if e.timer != nil {
-		e.timer.Reset(t)
-		return
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	if c.expiryPaused {
+		e.paused, e.ttl = t, t
+		return
+	}
+
+	if e.timer != nil {
+		if !e.timer.Reset(t) {
+			e.countTimer(1)
+		}
+	} else if !c.startTimer(key, e, t) {
+		return
+	}
+
+	e.deadline = c.now().Add(t)
+	e.ttl = t
+}
+
+//remove method removes an item, but is not protected by a mutex
+func (c *Cache[TKey, TValue]) remove(key TKey) bool {
+	e, exist := c.data[key]
+	if !exist {
+		return false
+	}
+
+	c.unlink(key, e)
+	c.unbucket(key, e)
+	c.disown(key, e)
+	e.StopTimer()
+	e.stopIdle()
+
+	c.cost -= e.cost
+	if e.pinned {
+		c.pinned--
+	}
+
+	delete(c.data, key)
+	c.rearmWatermark()
+
+	if c.tombstones != nil {
+		c.version++
+		c.tombstones[key] = c.version
+	}
+
+	return true
+}
+
+//discard removes the key on request of the user, recording the removal in the trace and notifying subscribers.
+//This method has no mutex protection
+func (c *Cache[TKey, TValue]) discard(key TKey) {
+	c.trace(TraceRemove, key)
+	c.dropStale(key)
+
+	if e, exist := c.data[key]; exist {
+		c.remove(key)
+		c.emit(EventRemove, key, e)
+	}
+}
+
+//Creates a copy of the data. This function is not protected by locks
+func (c *Cache[TKey, TValue]) copyValues() map[TKey]TValue {
+	cpy, _ := c.copyValuesUpTo(0)
+	return cpy
+}
+
+//copyValuesUpTo creates a copy of at most limit values, unless limit is 0. Returns true if there were more values
+//than that. This method has no mutex protection
+func (c *Cache[TKey, TValue]) copyValuesUpTo(limit int) (map[TKey]TValue, bool) {
+	cpy := make(map[TKey]TValue)
+	for key, entry := range c.data {
+		if !c.live(entry) {
+			continue
+		}
+
+		if limit > 0 && len(cpy) >= limit {
+			return cpy, true
+		}
+
+		cpy[key] = entry.Val
+	}
+	return cpy, false
+}
+
+//keys returns all the keys present in the cache. This method has no mutex protection
+func (c *Cache[TKey, TValue]) keys() []TKey {
+	keys := make([]TKey, 0, len(c.data))
+	for key := range c.data {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+//inChunks runs the function for every key supplied holding the lock. The lock is released and re-acquired every
+//LockChunkSize keys, so the function must check whether the key is still present
+func (c *Cache[TKey, TValue]) inChunks(keys []TKey, write bool, f func(TKey)) {
+	lock, unlock := c.mx.RLock, c.mx.RUnlock
+	if write {
+		lock, unlock = c.mx.Lock, c.mx.Unlock
+	}
+
+	for len(keys) > 0 {
+		n := c.cache.Requirements.LockChunkSize
+		if n > len(keys) {
+			n = len(keys)
+		}
+
+		lock()
+		for _, key := range keys[:n] {
+			f(key)
+		}
+		unlock()
+
+		keys = keys[n:]
+	}
+}
+
+//reset clears the cache, but it's not using locks
+func (c *Cache[TKey, TValue]) reset() {
+	for _, b := range c.buckets {
+		b.timer.Stop()
+	}
+
+	if c.onRemove != nil || c.transitions != nil {
+		for key, e := range c.data {
+			c.removed(key, e, RemovalExplicit)
+		}
+	}
+
+	if c.tombstones != nil {
+		c.version++
+		for key := range c.data {
+			c.tombstones[key] = c.version
+		}
 	}
 
-	e.timer = time.AfterFunc(t, func() { c.Remove(key) })
+	c.data = make(map[TKey]*entry[TValue])
+	c.cost, c.pinned = 0, 0
+	c.aboveWatermark = false
+	c.levels = make(map[levelID]*level[TKey])
+	c.drainLevels, c.drainPolicy = nil, nil
+	c.buckets = make(map[int64]*bucket[TKey])
+	c.owners = make(map[string]map[TKey]struct{})
+	c.groups = nil
+	c.dependents, c.dependencies = nil, nil
+	c.dropAllStale()
 }
 
-//remove method removes an item, but is not protected by a mutex
-func (c *Cache[TKey, TValue]) remove(key TKey) {
-	delete(c.data, key)
-}
+//live checks whether the entry hasn't expired yet. Entries can only be found expired before their timers fire
+//in ExpiryWallClock mode, or before the janitor sweeps them out if CleanupInterval is set
+func (c *Cache[TKey, TValue]) live(e *entry[TValue]) bool {
+	r := &c.cache.Requirements
+	if r.ExpiryMode == ExpiryWallClock || r.CleanupInterval > 0 {
+		return !e.expired()
+	}
 
-//Creates a copy of the data. This function is not protected by locks
-func (c *Cache[TKey, TValue]) copyValues() map[TKey]TValue {
-	cpy := make(map[TKey]TValue)
-	for key, entry := range c.data {
-		cpy[key] = entry.Val
+	if r.MaxTimers < 1 {
+		return true
 	}
-	return cpy
+
+	e.mx.RLock()
+	defer e.mx.RUnlock()
+	return !e.lazy || e.deadline.IsZero() || c.now().Before(e.deadline)
 }
 
-//reset clears the cache, but it's not using locks
-func (c *Cache[TKey, TValue]) reset() {
-	c.data = make(map[TKey]*entry[TValue])
+//lookup returns the entry stored under the key if it's present and hasn't expired. This method has no mutex protection
+func (c *Cache[TKey, TValue]) lookup(key TKey) (*entry[TValue], bool) {
+	e, exist := c.data[key]
+	if !exist || !c.live(e) {
+		return nil, false
+	}
+	return e, true
 }
 
-//getEntry is a private method tha returns Entry or nil and is not using mutexes
+//getEntry is a private method tha returns Entry or nil and is not using mutexes. Reading the entry counts as
+//its use for the eviction policy
 func (c *Cache[TKey, TValue]) getEntry(key TKey) Entry[TValue] {
-	if entry, exist := c.data[key]; !exist {
+	c.trace(TraceGet, key)
+
+	if entry, exist := c.lookup(key); !exist {
+		c.recordKey(key, false)
 		return nil
 	} else {
+		c.recordKey(key, true)
+		c.touch(key)
+		c.hit(entry)
 		return entry
 	}
 }
@@ -197,37 +1291,176 @@ func (c *Cache[TKey, TValue]) AddTimer(key TKey, t time.Duration) {
 	c.mx.Unlock()
 }
 
-//Add inserts new key:value pair into the cache
+//Add inserts new key:value pair into the cache. If middleware is set by Use, the operation goes through it and nil is
+//returned if the middleware doesn't pass it on
 func (c *Cache[TKey, TValue]) Add(key TKey, val TValue) Entry[TValue] {
+	if chain := c.chain(); chain != nil {
+		op := &Op[TKey, TValue]{Kind: OpAdd, Key: key, Value: val}
+		chain(op)
+		return op.entry
+	}
+
+	return c.put(key, val)
+}
+
+//put does the same as Add, bypassing the middleware
+func (c *Cache[TKey, TValue]) put(key TKey, val TValue) Entry[TValue] {
 	c.mx.Lock()
-	defer c.mx.Unlock()
-	return c.add(key, val, 0)
+	e := c.add(key, val, 0, PriorityNormal)
+	c.mx.Unlock()
+
+	c.writeThrough(key, val)
+
+	return e
+}
+
+//AddWithPriority does the same as method "Add" but also sets the priority of the entry. When the cache exceeds
+//Requirements.MaxEntries, entries with lower priority are evicted first regardless of how recently they were added
+func (c *Cache[TKey, TValue]) AddWithPriority(key TKey, val TValue, p Priority) Entry[TValue] {
+	c.mx.Lock()
+	e := c.add(key, val, 0, p)
+	c.mx.Unlock()
+
+	c.writeThrough(key, val)
+
+	return e
 }
 
-//AddWithTimeout does the same as method "Add" but also sets timer for automatic removal of the entry
+//AddWithTimeout does the same as method "Add" but also sets timer for automatic removal of the entry. The timer belongs
+//to this entry only: once the key is replaced or removed, the timer is stopped and can never remove the newer entry
 func (c *Cache[TKey, TValue]) AddWithTimeout(key TKey, val TValue, timeout time.Duration) Entry[TValue] {
 	c.mx.Lock()
-	defer c.mx.Unlock()
-	return c.add(key, val, timeout)
+	e := c.add(key, val, timeout, PriorityNormal)
+	c.mx.Unlock()
+
+	c.writeThrough(key, val)
+
+	return e
+}
+
+//AddWithDeadline does the same as method "AddWithTimeout" but the entry is removed at the wall clock time specified
+//rather than after a duration, e.g. at the expiry of a token. Deadlines that have passed already remove the entry
+//straight away
+func (c *Cache[TKey, TValue]) AddWithDeadline(key TKey, val TValue, t time.Time) Entry[TValue] {
+	return c.AddWithTimeout(key, val, untilDeadline(t, c.now()))
+}
+
+//AddToBucket inserts new key:value pair into the time bucket that expires at the boundary specified. All the entries
+//within the same bucket are removed together once the boundary is reached, using a single timer for the whole bucket
+//rather than one per entry. Entries in a bucket ignore DefaultTimeout
+func (c *Cache[TKey, TValue]) AddToBucket(boundary time.Time, key TKey, val TValue) Entry[TValue] {
+	e := entry[TValue]{
+		Val:      val,
+		priority: PriorityNormal,
+		bucket:   boundary,
+		clock:    c.cache.Requirements.TimeSource,
+		mx:       sync.RWMutex{},
+	}
+
+	c.mx.Lock()
+	c.insert(key, &e)
+	c.mx.Unlock()
+
+	c.writeThrough(key, val)
+
+	return &e
+}
+
+//AddOwned inserts new key:value pair into the cache on behalf of the owner specified, e.g. a user. All the entries
+//belonging to the same owner can be removed at once using RemoveByOwner. Empty owner means the entry has no owner
+func (c *Cache[TKey, TValue]) AddOwned(owner string, key TKey, val TValue) Entry[TValue] {
+	c.mx.Lock()
+	e := c.newEntry(key, val, 0, PriorityNormal)
+	e.owner = owner
+	c.insert(key, e)
+	c.mx.Unlock()
+
+	c.writeThrough(key, val)
+
+	return e
 }
 
 //AddBulk adds items to cache in bulk
 func (c *Cache[TKey, TValue]) AddBulk(d map[TKey]TValue) {
+	c.addBulk(d)
+
+	for k, v := range d {
+		c.writeThrough(k, v)
+	}
+}
+
+//addBulk adds items to cache in bulk without writing them through
+func (c *Cache[TKey, TValue]) addBulk(d map[TKey]TValue) {
+	c.addBulkWithMetadata(d, nil)
+}
+
+//addBulkWithMetadata does the same as addBulk, but all the entries added get the metadata supplied
+func (c *Cache[TKey, TValue]) addBulkWithMetadata(d map[TKey]TValue, meta Metadata) {
 	if d == nil {
 		return
 	}
 
+	chunk := c.cache.Requirements.LockChunkSize
+	n := 0
+
+	c.mx.Lock()
+	for k, v := range d {
+		e := c.newEntry(k, v, 0, PriorityNormal)
+		e.meta = meta
+		c.insert(k, e)
+
+		if n++; chunk > 0 && n%chunk == 0 {
+			c.mx.Unlock()
+			c.mx.Lock()
+		}
+	}
+	c.mx.Unlock()
+}
+
+//AddBulkIfNewer does the same as AddBulk, but values of the keys already present are only replaced if newer reports
+//the value supplied to be newer than the one in the cache, e.g. by the version or timestamp embedded in the values.
+//Newer is called under the write lock. Returns number of values added
+func (c *Cache[TKey, TValue]) AddBulkIfNewer(d map[TKey]TValue, newer func(a, b TValue) bool) int {
+	chunk := c.cache.Requirements.LockChunkSize
+	added := make(map[TKey]TValue, len(d))
+	n := 0
+
 	c.mx.Lock()
 	for k, v := range d {
-		c.add(k, v, 0)
+		if e, exist := c.lookup(k); !exist || newer(v, e.Val) {
+			c.add(k, v, 0, PriorityNormal)
+			added[k] = v
+		}
+
+		if n++; chunk > 0 && n%chunk == 0 {
+			c.mx.Unlock()
+			c.mx.Lock()
+		}
 	}
 	c.mx.Unlock()
+
+	for k, v := range added {
+		c.writeThrough(k, v)
+	}
+
+	return len(added)
 }
 
-//Remove removes Val from the cache based on the key provided
+//Remove removes Val from the cache based on the key provided. If middleware is set by Use, the operation goes
+//through it
 func (c *Cache[TKey, TValue]) Remove(key TKey) {
+	if chain := c.chain(); chain != nil {
+		chain(&Op[TKey, TValue]{Kind: OpRemove, Key: key})
+		return
+	}
+
+	c.erase(key)
+}
+
+//erase does the same as Remove, bypassing the middleware
+func (c *Cache[TKey, TValue]) erase(key TKey) {
 	c.mx.Lock()
-	c.remove(key)
+	c.discard(key)
 	c.mx.Unlock()
 }
 
@@ -239,13 +1472,104 @@ func (c *Cache[TKey, TValue]) RemoveBulk(keys []TKey) {
 
 	c.mx.Lock()
 	for _, key := range keys {
-		c.remove(key)
+		c.discard(key)
 	}
 	c.mx.Unlock()
 }
 
-//Get returns Value and boolean depending on whether the value exist in the cache
+//RemoveByOwner removes all the entries added on behalf of the owner specified. It takes time proportional to the
+//number of entries the owner has rather than the size of the cache. Returns number of entries removed
+func (c *Cache[TKey, TValue]) RemoveByOwner(owner string) int {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	keys := c.owners[owner]
+	n := len(keys)
+
+	for key := range keys {
+		c.discard(key)
+	}
+
+	return n
+}
+
+//InvalidateN removes at most maxN entries for which the predicate returns true and returns number of entries
+//removed. The write lock is held only while these entries are found, so large invalidations can be spread over time
+//by calling this method repeatedly until it removes fewer than maxN entries
+func (c *Cache[TKey, TValue]) InvalidateN(pred func(TKey, TValue) bool, maxN int) int {
+	if pred == nil || maxN < 1 {
+		return 0
+	}
+
+	pred = c.watchPredicate("InvalidateN", pred)
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	n := 0
+
+	for key, e := range c.data {
+		if n >= maxN {
+			break
+		}
+
+		if !pred(key, e.Val) {
+			continue
+		}
+
+		c.discard(key)
+		n++
+	}
+
+	return n
+}
+
+//RemoveWhere removes all the entries for which the predicate returns true and returns number of entries removed
+func (c *Cache[TKey, TValue]) RemoveWhere(pred func(TKey, TValue) bool) int {
+	if pred == nil {
+		return 0
+	}
+
+	pred = c.watchPredicate("RemoveWhere", pred)
+	removed := 0
+	remove := func(key TKey) {
+		if e, exist := c.data[key]; exist && pred(key, e.Val) {
+			c.discard(key)
+			removed++
+		}
+	}
+
+	if c.cache.Requirements.LockChunkSize < 1 {
+		c.mx.Lock()
+		for key := range c.data {
+			remove(key)
+		}
+		c.mx.Unlock()
+
+		return removed
+	}
+
+	c.mx.RLock()
+	keys := c.keys()
+	c.mx.RUnlock()
+
+	c.inChunks(keys, true, remove)
+
+	return removed
+}
+
+//Get returns Value and boolean depending on whether the value exist in the cache. If middleware is set by Use, the
+//operation goes through it
 func (c *Cache[TKey, TValue]) Get(key TKey) (TValue, bool) {
+	if chain := c.chain(); chain != nil {
+		return chain(&Op[TKey, TValue]{Kind: OpGet, Key: key})
+	}
+
+	return c.get(key)
+}
+
+//get does the same as Get, bypassing the middleware
+func (c *Cache[TKey, TValue]) get(key TKey) (TValue, bool) {
 	c.mx.RLock()
 	defer c.mx.RUnlock()
 	if e := c.getEntry(key); e == nil {
@@ -256,6 +1580,25 @@ func (c *Cache[TKey, TValue]) Get(key TKey) (TValue, bool) {
 	}
 }
 
+//GetOrSet returns the value of the key if it's present, otherwise adds the value supplied and returns it, all under
+//a single lock, so that concurrent calls for the same key all get the same value. Returns true if the value was
+//already present. Middleware set by Use is not applied
+func (c *Cache[TKey, TValue]) GetOrSet(key TKey, val TValue) (TValue, bool) {
+	c.mx.Lock()
+
+	if e := c.getEntry(key); e != nil {
+		c.mx.Unlock()
+		return e.Value(), true
+	}
+
+	c.add(key, val, 0, PriorityNormal)
+	c.mx.Unlock()
+
+	c.writeThrough(key, val)
+
+	return val, false
+}
+
 //GetValue returns only Value based on the key provided
 func (c *Cache[TKey, TValue]) GetValue(key TKey) TValue {
 	c.mx.RLock()
@@ -275,15 +1618,30 @@ func (c *Cache[TKey, TValue]) GetEntry(key TKey) Entry[TValue] {
 	return c.getEntry(key)
 }
 
-//GetBulk returns a map of key -> Val pairs where key is one provided in the slice
+//GetBulk returns a map of key -> Val pairs where key is one provided in the slice. If Requirements.MaxBulkResult is
+//set, at most that many values are returned
 func (c *Cache[TKey, TValue]) GetBulk(d []TKey) map[TKey]TValue {
-	results := make(map[TKey]TValue)
+	results, _ := c.getBulk(d)
+	return results
+}
+
+//GetBulkWithDefault does the same as GetBulk, but keys missing from the cache are included in the map returned
+//with the value produced by def, or with the zero value if def is nil. Values produced are not added to the cache
+func (c *Cache[TKey, TValue]) GetBulkWithDefault(d []TKey, def func(TKey) TValue) map[TKey]TValue {
+	results := c.GetBulk(d)
 
-	c.mx.RLock()
 	for _, k := range d {
-		results[k] = c.data[k].Val
+		if _, found := results[k]; found {
+			continue
+		}
+
+		if def == nil {
+			var nilVal TValue
+			results[k] = nilVal
+		} else {
+			results[k] = def(k)
+		}
 	}
-	c.mx.RUnlock()
 
 	return results
 }
@@ -292,24 +1650,32 @@ func (c *Cache[TKey, TValue]) GetBulk(d []TKey) map[TKey]TValue {
 func (c *Cache[TKey, TValue]) GetAndRemove(key TKey) (TValue, bool) {
 	c.mx.Lock()
 	defer c.mx.Unlock()
-	defer c.remove(key)
-	e, exist := c.data[key]
-	return e.Val, exist
+	defer c.discard(key)
+	c.trace(TraceGet, key)
+	if e, exist := c.lookup(key); exist {
+		return e.Val, true
+	}
+	var nilVal TValue
+	return nilVal, false
 }
 
 //GetAndRemoveEntry returns Entry interface and removes the entity from the cache immediately
 func (c *Cache[TKey, TValue]) GetAndRemoveEntry(key TKey) Entry[TValue] {
 	c.mx.Lock()
 	defer c.mx.Unlock()
-	defer c.remove(key)
-	return c.data[key]
+	defer c.discard(key)
+	c.trace(TraceGet, key)
+	if e, exist := c.lookup(key); exist {
+		return e
+	}
+	return nil
 }
 
-//GetAll returns all the values stored in the cache
+//GetAll returns all the values stored in the cache. If Requirements.MaxBulkResult is set, at most that many values
+//are returned
 func (c *Cache[TKey, TValue]) GetAll() map[TKey]TValue {
-	c.mx.RLock()
-	defer c.mx.RUnlock()
-	return c.copyValues()
+	cpy, _ := c.getAll()
+	return cpy
 }
 
 //GetAllAndRemove returns and removes all the elements from the cache
@@ -342,7 +1708,48 @@ func (c *Cache[TKey, TValue]) GetRandomSamples(n int) map[TKey]TValue {
 func (c *Cache[TKey, TValue]) Exist(key TKey) bool {
 	c.mx.RLock()
 	defer c.mx.RUnlock()
-	_, exist := c.data[key]
+	_, exist := c.lookup(key)
+	return exist
+}
+
+//Touch restarts the timer of the key with the duration supplied, or with DefaultTimeout if none is supplied, without
+//reading the value. Entries that have no timer get one. If no duration is supplied and DefaultTimeout is not set,
+//the timer restarts with the duration it was last set to. While expiry is paused by PauseExpiry, only the time
+//remaining changes and the countdown starts on ResumeExpiry. Returns false if the key is not present
+func (c *Cache[TKey, TValue]) Touch(key TKey, d ...time.Duration) bool {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	e, exist := c.lookup(key)
+	if !exist {
+		return false
+	}
+
+	switch {
+	case len(d) > 0:
+		c.addTimer(key, d[0])
+	case c.cache.Requirements.timeoutInUse:
+		c.addTimer(key, c.cache.Requirements.DefaultTimeout)
+	default:
+		e.mx.Lock()
+		e.resetTimer(e.ttl)
+		e.mx.Unlock()
+	}
+
+	return true
+}
+
+//ExtendTimer adds the duration to the time remaining until the removal of the key, the same way as
+//Entry.ExtendTimer. Returns false if the key is not present
+func (c *Cache[TKey, TValue]) ExtendTimer(key TKey, d time.Duration) bool {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	e, exist := c.lookup(key)
+	if exist {
+		e.ExtendTimer(d)
+	}
+
 	return exist
 }
 
@@ -353,34 +1760,173 @@ func (c *Cache[TKey, TValue]) Count() int {
 	return len(c.data)
 }
 
+//CountWhere returns number of entries matching the predicate. The predicate is called for every entry under the
+//read lock, so for very large caches CountWhereApprox may be a better choice
+func (c *Cache[TKey, TValue]) CountWhere(pred func(TKey, TValue) bool) int {
+	return c.CountWhereApprox(pred, -1)
+}
+
+//CountWhereApprox estimates number of entries matching the predicate by calling it for sample entries only and
+//scaling the number of matches up to the size of the cache. Entries are sampled in the order of iteration over the
+//map, which starts at a random position every time. If sample is negative or not smaller than the size of the cache,
+//every entry is checked and the count is exact
+func (c *Cache[TKey, TValue]) CountWhereApprox(pred func(TKey, TValue) bool, sample int) int {
+	if pred == nil {
+		return 0
+	}
+
+	pred = c.watchPredicate("CountWhere", pred)
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	size := len(c.data)
+	if sample < 0 || sample > size {
+		sample = size
+	}
+
+	checked, matched := 0, 0
+	for key, e := range c.data {
+		if checked >= sample {
+			break
+		}
+
+		checked++
+
+		if c.live(e) && pred(key, e.Val) {
+			matched++
+		}
+	}
+
+	if checked < 1 || checked == size {
+		return matched
+	}
+
+	return int(float64(matched) * float64(size) / float64(checked))
+}
+
 //ForEach runs a loop for each element in the cache. Take care using this method as it locks reading/writing the
 //cache until ForEach completes.
 func (c *Cache[TKey, TValue]) ForEach(f func(TKey, TValue)) {
 	d := c.GetAll()
+	f = c.watchVisitor("ForEach", f)
 
 	for k, v := range d {
 		f(k, v)
 	}
 }
 
-//Reset empties the cache and resets all the counters
+//Visit calls the function for every entry in the cache until it returns false. Unlike ForEach, the entries are not
+//copied, e.g. for exporters of big caches, but the function runs under the read lock, so it must not block and must
+//not modify the cache. Reading the entries doesn't count as their use
+func (c *Cache[TKey, TValue]) Visit(f func(TKey, TValue) bool) {
+	f = c.watchPredicate("Visit", f)
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	for key, e := range c.data {
+		if c.live(e) && !f(key, e.Val) {
+			return
+		}
+	}
+}
+
+//Reset empties the cache and resets all the counters. If Requirements.LockChunkSize is set, entries are removed
+//LockChunkSize at a time, releasing the lock in between
 func (c *Cache[TKey, TValue]) Reset() {
 	c.mx.Lock()
+	if c.cache.Requirements.LockChunkSize < 1 {
+		c.reset()
+		c.mx.Unlock()
+		return
+	}
+
+	keys := c.keys()
+	c.mx.Unlock()
+
+	c.inChunks(keys, true, func(key TKey) {
+		if e, exist := c.data[key]; exist {
+			c.remove(key)
+			c.removed(key, e, RemovalExplicit)
+		}
+	})
+
+	//Whatever was added in the meantime goes along with the counters. Bucket timers are stopped once the lock is
+	//released
+	c.mx.Lock()
+	buckets := c.buckets
+	c.buckets = make(map[int64]*bucket[TKey])
 	c.reset()
 	c.mx.Unlock()
+
+	for _, b := range buckets {
+		b.timer.Stop()
+	}
+}
+
+//SetEvictionPolicy replaces the eviction policy of the cache with policies created by the constructor supplied, one
+//for every Priority level. Existing keys are handed over to the new policies in the order the old policies would
+//have evicted them. If nil is supplied, the built-in policy selected in Requirements is used
+func (c *Cache[TKey, TValue]) SetEvictionPolicy(newPolicy func() EvictionPolicy[TKey]) {
+	custom := newPolicy != nil
+	if !custom {
+		newPolicy = builtinPolicy[TKey](&c.cache.Requirements)
+	}
+
+	c.migrateMx.Lock()
+	defer c.migrateMx.Unlock()
+
+	c.mx.Lock()
+	c.startMigration(newPolicy)
+	c.migrateChunk(-1)
+	c.customPolicy = custom
+	c.mx.Unlock()
+}
+
+//SetDefaultTimeout changes Requirements.DefaultTimeout of the live cache, e.g. when the timeout comes from
+//configuration that changes while running. Only entries added afterwards are affected. 0 turns the default timeout off
+func (c *Cache[TKey, TValue]) SetDefaultTimeout(d time.Duration) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.cache.Requirements.DefaultTimeout = d
+	makeRequirementsSensible(&c.cache.Requirements)
 }
 
 //Requirements returns requirements used from this cache
 func (c *Cache[TKey, TValue]) Requirements() Requirements {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
 	return c.cache.Requirements
 }
 
 //===========[FUNCTIONALITY]====================================================================================================
 
+//wallClock returns current wall clock time stripped of the monotonic clock reading, so that comparisons account
+//for the time the machine spent suspended
+func wallClock() time.Time {
+	return time.Now().Round(0)
+}
+
+//untilDeadline returns the timeout reaching the deadline specified from now. Deadlines that have passed already give
+//the shortest timeout possible rather than 0, which would mean no timeout at all
+func untilDeadline(t, now time.Time) time.Duration {
+	if d := t.Sub(now); d > 0 {
+		return d
+	}
+
+	return time.Nanosecond
+}
+
 //Adjusts and parses the Requirements
 func makeRequirementsSensible(r *Requirements) {
 	//Checking whether the DefaultTimeout is in use. If yes, it sets timeoutInUse to true
 	r.timeoutInUse = r.DefaultTimeout.String() != "0s"
+
+	if r.TimeSource == nil {
+		r.TimeSource = systemClock{}
+	}
 }
 
 //New initiates new cache. It can also take in values that will be added to the cache immediately after initiation
@@ -394,26 +1940,79 @@ func New[TKey Key, TValue any](r *Requirements) Cache[TKey, TValue] {
 	c := cache[TKey, TValue]{
 		Requirements: *r,
 		data:         make(map[TKey]*entry[TValue]),
+		levels:       make(map[levelID]*level[TKey]),
+		newPolicy:    builtinPolicy[TKey](r),
+		untracked:    r.MaxEntries < 1 && r.MaxCost < 1,
+		buckets:      make(map[int64]*bucket[TKey]),
+		owners:       make(map[string]map[TKey]struct{}),
+		flights:      make(map[TKey]*loadCall[TValue]),
+		listeners:    make(map[int]func(Event[TKey])),
+		closed:       make(chan struct{}),
 		mx:           sync.RWMutex{},
 	}
 
-	return Cache[TKey, TValue]{c}
+	if r.MaxConcurrentLoads > 0 {
+		c.loads = newLoadLimiter(r.MaxConcurrentLoads, r.MaxQueuedLoads)
+	}
+
+	if r.TrackedKeys > 0 {
+		c.keyStats = newKeySketch[TKey](r.TrackedKeys)
+	}
+
+	if r.Admission && r.MaxEntries > 0 {
+		c.admission = newFrequencySketch(r.MaxEntries)
+	}
+
+	cm := Cache[TKey, TValue]{&c}
+
+	if r.CleanupInterval > 0 {
+		go cm.runJanitor(r.CleanupInterval)
+	}
+
+	return cm
 }
 
-//Copy creates identical copy of the cache supplied as an argument
+//Copy creates identical copy of the cache supplied as an argument. Entries of the copy expire at the same time as
+//the entries they were copied from and keep their priority and pins
 func Copy[TKey Key, TValue any](c *Cache[TKey, TValue]) Cache[TKey, TValue] {
-	req := c.Requirements()
-	nc := New[TKey, TValue](&req)
-	nc.AddBulk(c.GetAll())
-	return nc
+	return CopyWithOptions(c, MergeOptions{})
 }
 
-//Merge copies all data from cache2 into cache1
+//Merge copies all data from cache2 into cache1. If both are *Cache, the entries copied keep the time remaining until
+//they expire, their priority and their pins
 func Merge[TKey Key, TValue any](cache1 BulkAdder[TKey, TValue], cache2 AllGetter[TKey, TValue]) {
+	dst, dstOk := cache1.(*Cache[TKey, TValue])
+	src, srcOk := cache2.(*Cache[TKey, TValue])
+
+	if dstOk && srcOk {
+		MergeWithOptions(dst, src, MergeOptions{})
+		return
+	}
+
 	cache1.AddBulk(cache2.GetAll())
 }
 
-//MergeAndReset copies all data from cache2 into cache1 and wipes cache2 clean right after
+//MergeNewer copies data from cache2 into cache1, keeping the values of cache1 that newer doesn't report to be older
+//than the values of cache2 under the same keys
+func MergeNewer[TKey Key, TValue any](cache1 NewerBulkAdder[TKey, TValue], cache2 AllGetter[TKey, TValue], newer func(a, b TValue) bool) {
+	cache1.AddBulkIfNewer(cache2.GetAll(), newer)
+}
+
+//MergeAndReset copies all data from cache2 into cache1 and wipes cache2 clean right after. If both are *Cache, the
+//entries copied keep the time remaining until they expire, their priority and their pins
 func MergeAndReset[TKey Key, TValue any](cache1 BulkAdder[TKey, TValue], cache2 AllGetterAndRemover[TKey, TValue]) {
+	dst, dstOk := cache1.(*Cache[TKey, TValue])
+	src, srcOk := cache2.(*Cache[TKey, TValue])
+
+	if dstOk && srcOk {
+		src.mx.Lock()
+		d := src.timedValues()
+		src.reset()
+		src.mx.Unlock()
+
+		dst.mergeTimed(d, MergeOptions{})
+		return
+	}
+
 	cache1.AddBulk(cache2.GetAllAndRemove())
 }
@@ -1,6 +1,7 @@
 package cacheMachine
 
 import (
+	"sync"
 	"testing"
 	"time"
 )
@@ -63,6 +64,47 @@ func TestCache_Count(t *testing.T) {
 	}
 }
 
+func TestCache_CountWhere(t *testing.T) {
+	c := initializeFullCache(1000, nil)
+	even := func(k, v int) bool { return v%2 == 0 }
+
+	if n := c.CountWhere(even); n != 500 {
+		t.Errorf("Expected %d matching entries, received %d", 500, n)
+	}
+
+	if n := c.CountWhereApprox(even, 5000); n != 500 {
+		t.Errorf("Expected exact count of %d when the sample covers the cache, received %d", 500, n)
+	}
+
+	if n := c.CountWhereApprox(even, 200); n < 300 || n > 700 {
+		t.Errorf("Expected estimate close to %d, received %d", 500, n)
+	}
+}
+
+func TestCache_Visit(t *testing.T) {
+	c := initializeFullCache(10, nil)
+
+	sum := 0
+	c.Visit(func(k, v int) bool {
+		sum += v
+		return true
+	})
+
+	if sum != 45 {
+		t.Errorf("Expected values to sum up to %d, received %d", 45, sum)
+	}
+
+	visited := 0
+	c.Visit(func(k, v int) bool {
+		visited++
+		return visited < 3
+	})
+
+	if visited != 3 {
+		t.Errorf("Expected visiting to stop after %d entries, received %d", 3, visited)
+	}
+}
+
 func TestCache_Get(t *testing.T) {
 	requiredValue := 5
 
@@ -75,6 +117,36 @@ func TestCache_Get(t *testing.T) {
 	}
 }
 
+func TestCache_GetOrSet(t *testing.T) {
+	c := New[int, int](nil)
+
+	if v, ok := c.GetOrSet(1, 1); v != 1 || ok {
+		t.Errorf("Expected the value to be set, got %d and %t", v, ok)
+	}
+
+	if v, ok := c.GetOrSet(1, 2); v != 1 || !ok {
+		t.Errorf("Expected the existing value to be returned, got %d and %t", v, ok)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 50)
+
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = c.GetOrSet(2, i)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, v := range results {
+		if v != results[0] {
+			t.Fatalf("Expected every concurrent call to get the same value, got %v", results)
+		}
+	}
+}
+
 func TestCache_Exist(t *testing.T) {
 	c := initializeFullCache(10, nil)
 
@@ -163,6 +235,24 @@ func TestCache_GetAllAndRemove(t *testing.T) {
 	}
 }
 
+func TestCache_GetBulkWithDefault(t *testing.T) {
+	c := initializeFullCache(3, nil)
+
+	d := c.GetBulkWithDefault([]int{1, 2, 10}, nil)
+	if v, found := d[10]; len(d) != 3 || !found || v != 0 {
+		t.Errorf("Expected missing key to be included with zero value, got %v", d)
+	}
+
+	d = c.GetBulkWithDefault([]int{1, 10}, func(k int) int { return -k })
+	if d[1] != 1 || d[10] != -10 {
+		t.Errorf("Expected missing key to get the default value, got %v", d)
+	}
+
+	if c.Exist(10) {
+		t.Errorf("Expected default value not to be added to the cache")
+	}
+}
+
 func TestCache_GetAndRemove(t *testing.T) {
 	c := initializeFullCache(10, nil)
 
@@ -270,6 +360,28 @@ func TestMerge(t *testing.T) {
 	}
 }
 
+func TestMergeNewer(t *testing.T) {
+	main := initializeFullCache(10, nil)
+	secondary := New[int, int](nil)
+	secondary.Add(1, 100)
+	secondary.Add(2, 0)
+	secondary.Add(20, 20)
+
+	MergeNewer[int, int](&main, &secondary, func(a, b int) bool { return a > b })
+
+	if v, _ := main.Get(1); v != 100 {
+		t.Errorf("Expected the newer value %d to be kept, got %d", 100, v)
+	}
+
+	if v, _ := main.Get(2); v != 2 {
+		t.Errorf("Expected the newer value %d to be kept, got %d", 2, v)
+	}
+
+	if !main.Exist(20) || main.Count() != 11 {
+		t.Errorf("Expected the missing key to be added, got %d entries", main.Count())
+	}
+}
+
 func TestMergeAndReset(t *testing.T) {
 	main := initializeFullCache(10, nil)
 	secondary := initializeFullCache(20, nil)
@@ -336,6 +448,137 @@ func TestEntry_TimerExist(t *testing.T) {
 	}
 }
 
+func TestEntry_TTL(t *testing.T) {
+	c := New[int, int](nil)
+
+	if e := c.Add(1, 1); e.TTL() != 0 || !e.ExpiresAt().IsZero() {
+		t.Errorf("Expected no TTL of the entry without timeout, got %s", e.TTL())
+	}
+
+	e := c.AddWithTimeout(2, 2, time.Minute)
+	if ttl := e.TTL(); ttl <= time.Second*59 || ttl > time.Minute {
+		t.Errorf("Expected TTL close to %s, got %s", time.Minute, ttl)
+	}
+
+	e.StopTimer()
+	if e.TTL() != 0 {
+		t.Errorf("Expected no TTL once the timer is stopped, got %s", e.TTL())
+	}
+
+	boundary := time.Now().Add(time.Hour)
+	if e := c.AddToBucket(boundary, 3, 3); !e.ExpiresAt().Equal(boundary) {
+		t.Errorf("Expected the entry to expire at %s, got %s", boundary, e.ExpiresAt())
+	}
+}
+
+func TestCache_SetDefaultTimeout(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := New[int, int](&Requirements{TimeSource: clock})
+
+	c.Add(1, 1)
+	c.SetDefaultTimeout(time.Minute)
+	c.Add(2, 2)
+
+	if r := c.Requirements(); r.DefaultTimeout != time.Minute {
+		t.Errorf("Expected DefaultTimeout of a minute, got %s", r.DefaultTimeout)
+	}
+
+	clock.Advance(time.Minute)
+
+	if !c.Exist(1) || c.Exist(2) {
+		t.Errorf("Expected only the entry added after the change to expire")
+	}
+
+	c.SetDefaultTimeout(0)
+	c.Add(3, 3)
+
+	if c.GetEntry(3).TimerExist() {
+		t.Errorf("Expected no timer once the default timeout is turned off")
+	}
+}
+
+func TestCache_Touch(t *testing.T) {
+	c := New[int, int](&Requirements{DefaultTimeout: time.Millisecond * 40})
+
+	if c.Touch(1) {
+		t.Errorf("Expected Touch of a missing key to return false")
+	}
+
+	c.Add(1, 1)
+	c.Add(2, 2)
+
+	time.Sleep(time.Millisecond * 25)
+
+	if !c.Touch(1) || !c.Touch(2, time.Hour) {
+		t.Errorf("Expected Touch of present keys to return true")
+	}
+
+	time.Sleep(time.Millisecond * 25)
+
+	if !c.Exist(1) || !c.Exist(2) {
+		t.Errorf("Expected touched entries to outlive their original timeout, got 1 - %t, 2 - %t", c.Exist(1), c.Exist(2))
+	}
+
+	if ttl := c.GetEntry(2).TTL(); ttl < time.Minute*59 {
+		t.Errorf("Expected touched entry to get the duration supplied, got %s", ttl)
+	}
+}
+
+func TestCache_Touch_concurrent(t *testing.T) {
+	//Lazy timers past MaxTimers make reads check the deadline of the entry under the read lock
+	c := New[int, int](&Requirements{DefaultTimeout: time.Hour, MaxTimers: 1})
+	c.Add(1, 1)
+	c.Add(2, 2)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for n := 0; n < 200; n++ {
+				c.Touch(2, time.Duration(i+1)*time.Minute)
+				c.Touch(2)
+				c.Get(2)
+				c.ExpiringWithin(time.Minute)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if ttl := c.GetEntry(2).TTL(); ttl < time.Minute || ttl > time.Hour {
+		t.Errorf("Expected the entry to keep a timer set by Touch, got TTL of %s", ttl)
+	}
+}
+
+func TestCache_ExtendTimer(t *testing.T) {
+	c := New[int, int](nil)
+
+	c.AddWithTimeout(1, 1, time.Millisecond*30)
+	c.Add(2, 2)
+
+	time.Sleep(time.Millisecond * 20)
+
+	if !c.ExtendTimer(1, time.Millisecond*30) || !c.ExtendTimer(2, time.Hour) || c.ExtendTimer(3, time.Hour) {
+		t.Errorf("Expected ExtendTimer to report whether the keys are present")
+	}
+
+	if ttl := c.GetEntry(1).TTL(); ttl < time.Millisecond*30 || ttl > time.Millisecond*40 {
+		t.Errorf("Expected the remaining %s to be extended by %s, got %s", time.Millisecond*10, time.Millisecond*30, ttl)
+	}
+
+	if c.GetEntry(2).TimerExist() {
+		t.Errorf("Expected no timer to be started for the entry without timeout")
+	}
+
+	time.Sleep(time.Millisecond * 20)
+
+	if !c.Exist(1) {
+		t.Errorf("Expected the entry to outlive its original timeout")
+	}
+}
+
 func TestCache_GetEntry(t *testing.T) {
 	c := initializeFullCache(10, nil)
 
@@ -383,6 +626,371 @@ func TestCache_AddWithTimeout(t *testing.T) {
 	}
 }
 
+func TestCache_AddWithDeadline(t *testing.T) {
+	c := initializeFullCache(0, nil)
+
+	e := c.AddWithDeadline(1, 1, time.Now().Add(time.Hour))
+	c.AddWithDeadline(2, 2, time.Now().Add(-time.Hour))
+
+	e.SetDeadline(time.Now().Add(time.Millisecond * 30))
+
+	if at := e.ExpiresAt(); time.Until(at) > time.Millisecond*30 {
+		t.Errorf("Expected the entry to expire within 30ms, got %s", at)
+	}
+
+	time.Sleep(time.Millisecond * 100)
+
+	if c.Exist(1) || c.Exist(2) {
+		t.Errorf("Expected both entries to be removed at their deadlines")
+	}
+}
+
+func TestCache_AddWithTimeout_replaced(t *testing.T) {
+	c := initializeFullCache(0, nil)
+
+	c.AddWithTimeout(1, 1, time.Millisecond*100)
+	c.Add(1, 2)
+
+	c.AddWithTimeout(2, 2, time.Millisecond*100)
+	c.Remove(2)
+	c.AddWithTimeout(2, 3, time.Second*30)
+
+	time.Sleep(time.Millisecond * 250)
+
+	if v, ok := c.Get(1); !ok || v != 2 {
+		t.Errorf("Expected timer of the replaced entry not to remove key 1, got %d and %t", v, ok)
+	}
+
+	if !c.Exist(2) {
+		t.Errorf("Expected timer of the removed entry not to remove re-added key 2, but it did")
+	}
+}
+
+func TestRequirements_ExpiryMode(t *testing.T) {
+	c := initializeFullCache(0, &Requirements{ExpiryMode: ExpiryWallClock})
+
+	c.AddWithTimeout(1, 1, time.Second*30)
+	c.AddWithTimeout(2, 2, time.Second*30)
+	c.Add(3, 3)
+
+	//Simulating the machine being suspended past the deadline while the timer hasn't fired
+	c.data[1].deadline = wallClock().Add(-time.Second)
+
+	if c.Exist(1) {
+		t.Errorf("Expected entry past its wall clock deadline to be reported missing, but it was not")
+	}
+
+	if _, ok := c.Get(1); ok || !c.Exist(2) || !c.Exist(3) {
+		t.Errorf("Expected only key 1 to be expired, got 1 - %t, 2 - %t, 3 - %t", ok, c.Exist(2), c.Exist(3))
+	}
+
+	if l := len(c.GetAll()); l != 2 {
+		t.Errorf("Expected GetAll to skip expired entry and return %d items, got %d", 2, l)
+	}
+
+	c2 := initializeFullCache(0, nil)
+	c2.AddWithTimeout(1, 1, time.Second*30)
+	c2.data[1].deadline = wallClock().Add(-time.Second)
+
+	if !c2.Exist(1) {
+		t.Errorf("Expected wall clock deadline to be ignored in ExpiryMonotonic mode")
+	}
+}
+
+func TestCache_AddWithPriority(t *testing.T) {
+	c := initializeFullCache(0, &Requirements{MaxEntries: 3})
+
+	c.AddWithPriority(1, 1, PriorityHigh)
+	c.AddWithPriority(2, 2, PriorityLow)
+	c.Add(3, 3)
+	c.Add(4, 4)
+
+	if c.Exist(2) {
+		t.Errorf("Entry with key %d has the lowest priority and should have been evicted, but it was not!", 2)
+	}
+
+	c.Add(5, 5)
+
+	if c.Exist(3) || !c.Exist(4) || !c.Exist(5) {
+		t.Errorf("Expected the oldest entry with normal priority to be evicted, got 3 - %t, 4 - %t, 5 - %t", c.Exist(3), c.Exist(4), c.Exist(5))
+	}
+
+	c.AddWithPriority(6, 6, PriorityLow)
+
+	if c.Exist(6) || !c.Exist(1) {
+		t.Errorf("Expected entry 6 with low priority to be evicted instead of entry 1 with high priority, got 6 - %t, 1 - %t", c.Exist(6), c.Exist(1))
+	}
+
+	if cLen := c.Count(); cLen != 3 {
+		t.Errorf("Expected to have %d items in the cache, got %d", 3, cLen)
+	}
+}
+
+func TestCache_AddToBucket(t *testing.T) {
+	c := initializeFullCache(0, nil)
+
+	boundary := time.Now().Add(time.Millisecond * 250)
+
+	for i := 0; i < 5; i++ {
+		c.AddToBucket(boundary, i, i)
+	}
+	c.AddToBucket(boundary.Add(time.Second*30), 5, 5)
+
+	if n := len(c.buckets); n != 2 {
+		t.Errorf("Expected to have %d buckets, got %d", 2, n)
+	}
+
+	c.Remove(5)
+
+	if n := len(c.buckets); n != 1 {
+		t.Errorf("Expected empty bucket to be discarded leaving %d bucket, got %d", 1, n)
+	}
+
+	time.Sleep(time.Millisecond * 500)
+
+	if cLen := c.Count(); cLen != 0 {
+		t.Errorf("Expected to have 0 items in the cache after the bucket expired, got %d", cLen)
+	}
+}
+
+func TestCache_AddOwned(t *testing.T) {
+	c := initializeFullCache(0, &Requirements{MaxEntries: 3})
+
+	c.AddOwned("user1", 1, 1)
+	c.AddOwned("user1", 2, 2)
+	c.AddOwned("user2", 3, 3)
+
+	if n := len(c.owners["user1"]); n != 2 {
+		t.Errorf("Expected owner user1 to have %d entries, got %d", 2, n)
+	}
+
+	c.Add(4, 4)
+
+	if n := len(c.owners["user1"]); n != 1 {
+		t.Errorf("Expected evicted entry to be removed from the owner index leaving %d entry, got %d", 1, n)
+	}
+
+	c.Add(2, 2)
+
+	if _, exist := c.owners["user1"]; exist {
+		t.Errorf("Expected entry replaced without an owner to be removed from the owner index")
+	}
+}
+
+func TestCache_RemoveByOwner(t *testing.T) {
+	c := initializeFullCache(10, nil)
+
+	for i := 10; i < 15; i++ {
+		c.AddOwned("user", i, i)
+	}
+
+	if n := c.RemoveByOwner("user"); n != 5 {
+		t.Errorf("Expected to remove %d entries, got %d", 5, n)
+	}
+
+	if cLen := c.Count(); cLen != 10 || c.Exist(12) {
+		t.Errorf("Expected to have 10 items left in the cache without key 12, got %d items", cLen)
+	}
+
+	if n := c.RemoveByOwner("user"); n != 0 {
+		t.Errorf("Expected to remove %d entries for owner without entries, got %d", 0, n)
+	}
+}
+
+func TestCache_InvalidateN(t *testing.T) {
+	c := initializeFullCache(100, nil)
+
+	even := func(k, v int) bool { return k%2 == 0 }
+
+	if n := c.InvalidateN(even, 30); n != 30 {
+		t.Errorf("Expected to remove %d entries, got %d", 30, n)
+	}
+
+	if n := c.InvalidateN(even, 30); n != 20 {
+		t.Errorf("Expected to remove remaining %d entries, got %d", 20, n)
+	}
+
+	if cLen := c.Count(); cLen != 50 || c.Exist(42) || !c.Exist(43) {
+		t.Errorf("Expected to have 50 odd items left in the cache, got %d items", cLen)
+	}
+}
+
+func TestCache_RemoveWhere(t *testing.T) {
+	c := initializeFullCache(100, nil)
+
+	if n := c.RemoveWhere(func(k, v int) bool { return k%2 == 0 }); n != 50 {
+		t.Errorf("Expected to remove %d entries, got %d", 50, n)
+	}
+
+	c2 := initializeFullCache(100, &Requirements{LockChunkSize: 7})
+
+	if n := c2.RemoveWhere(func(k, v int) bool { return k < 30 }); n != 30 {
+		t.Errorf("Expected to remove %d entries in chunks, got %d", 30, n)
+	}
+
+	if c.Count() != 50 || c2.Count() != 70 {
+		t.Errorf("Expected to have 50 and 70 items left in the caches, got %d and %d", c.Count(), c2.Count())
+	}
+}
+
+func TestRequirements_TimeoutJitter(t *testing.T) {
+	c := initializeFullCache(100, &Requirements{DefaultTimeout: time.Minute, TimeoutJitter: time.Second})
+	c.AddWithTimeout(100, 100, time.Hour)
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	ttls := make(map[time.Duration]struct{})
+	for i := 0; i < 100; i++ {
+		ttl := c.data[i].ttl
+		if ttl < time.Minute || ttl > time.Minute+time.Second {
+			t.Errorf("Expected timeout between %s and %s, got %s", time.Minute, time.Minute+time.Second, ttl)
+		}
+		ttls[ttl] = struct{}{}
+	}
+
+	if len(ttls) < 50 {
+		t.Errorf("Expected timeouts to be spread, got only %d distinct timeouts", len(ttls))
+	}
+
+	if ttl := c.data[100].ttl; ttl != time.Hour {
+		t.Errorf("Expected explicit timeout of %s to be kept as is, got %s", time.Hour, ttl)
+	}
+}
+
+func TestRequirements_LockChunkSize(t *testing.T) {
+	c := initializeFullCache(0, &Requirements{LockChunkSize: 3})
+	c2 := initializeFullCache(10, nil)
+
+	c.AddToBucket(time.Now().Add(time.Second*30), -1, -1)
+	Merge[int, int](&c, &c2)
+
+	if l := len(c.GetAll()); l != 11 {
+		t.Errorf("Expected to get %d items in chunks, got %d", 11, l)
+	}
+
+	c.Reset()
+
+	if c.Count() != 0 || len(c.buckets) != 0 {
+		t.Errorf("Expected cache to be empty after the reset, got %d items and %d buckets", c.Count(), len(c.buckets))
+	}
+}
+
+func TestRequirements_LockChunkSize_reset(t *testing.T) {
+	c := initializeFullCache(10, &Requirements{LockChunkSize: 3})
+
+	removed := 0
+	var seen chan int
+
+	c.SetOnRemove(func(key, val int, reason RemovalReason) {
+		if removed++; removed > 1 {
+			return
+		}
+
+		//Reader blocked during the first chunk gets the lock before the rest of the entries are removed
+		seen = make(chan int, 1)
+		go func() {
+			c.mx.RLock()
+			seen <- removed
+			c.mx.RUnlock()
+		}()
+		time.Sleep(time.Millisecond * 10)
+	})
+
+	c.Reset()
+
+	if n := <-seen; n >= 10 {
+		t.Errorf("Expected the lock to be released between chunks, the reader only got in after %d removals", n)
+	}
+
+	if removed != 10 || c.Count() != 0 {
+		t.Errorf("Expected all 10 entries to be removed, got %d removals and %d entries left", removed, c.Count())
+	}
+}
+
+func TestRequirements_FairEviction(t *testing.T) {
+	c := initializeFullCache(0, &Requirements{MaxEntries: 6, FairEviction: true, OwnerWeights: map[string]int{"big": 2}})
+
+	c.AddOwned("big", 1, 1)
+	c.AddOwned("big", 2, 2)
+	c.AddOwned("small", 3, 3)
+	c.AddOwned("small", 4, 4)
+
+	//A scan by the third owner can only flush its own entries once it holds the largest share
+	for i := 100; i < 110; i++ {
+		c.AddOwned("scan", i, i)
+	}
+
+	for i := 1; i <= 4; i++ {
+		if !c.Exist(i) {
+			t.Errorf("Expected key %d of another owner to survive the scan, but it was evicted", i)
+		}
+	}
+
+	c2 := initializeFullCache(0, &Requirements{MaxEntries: 4, FairEviction: true, OwnerWeights: map[string]int{"big": 3}})
+
+	c2.AddOwned("big", 1, 1)
+	c2.AddOwned("big", 2, 2)
+	c2.AddOwned("big", 3, 3)
+	c2.AddOwned("small", 4, 4)
+
+	//big holds 3 entries of weight 3 while small would hold 2 of weight 1, so small gives up its oldest entry
+	c2.AddOwned("small", 5, 5)
+
+	if c2.Exist(4) || !c2.Exist(1) {
+		t.Errorf("Expected entry of the owner over its share to be evicted, got 1 - %t, 4 - %t", c2.Exist(1), c2.Exist(4))
+	}
+
+	c2.AddOwned("big", 6, 6)
+
+	if c2.Exist(1) || !c2.Exist(5) {
+		t.Errorf("Expected the oldest entry of big to be evicted once it's over its share, got 1 - %t, 5 - %t", c2.Exist(1), c2.Exist(5))
+	}
+}
+
+func TestCache_SetEvictionPolicy(t *testing.T) {
+	c := initializeFullCache(0, &Requirements{MaxEntries: 3, Policy: LRU})
+
+	c.Add(1, 1)
+	c.Add(2, 2)
+	c.Add(3, 3)
+	c.Get(1)
+	c.Add(4, 4)
+
+	if c.Exist(2) || !c.Exist(1) {
+		t.Errorf("Expected least recently used entry 2 to be evicted, got 1 - %t, 2 - %t", c.Exist(1), c.Exist(2))
+	}
+
+	c.SetEvictionPolicy(NewLFU[int])
+
+	c.Get(1)
+	c.Get(3)
+	c.Add(5, 5)
+
+	if c.Exist(4) || !c.Exist(1) || !c.Exist(3) {
+		t.Errorf("Expected least frequently used entry 4 to be evicted, got 1 - %t, 3 - %t, 4 - %t", c.Exist(1), c.Exist(3), c.Exist(4))
+	}
+
+	if cLen := c.Count(); cLen != 3 {
+		t.Errorf("Expected to have %d items in the cache, got %d", 3, cLen)
+	}
+}
+
+func TestRequirements_ProtectedRatio(t *testing.T) {
+	c := initializeFullCache(4, &Requirements{MaxEntries: 4, Policy: SLRU, ProtectedRatio: 0.5})
+
+	c.Get(0)
+	c.Get(1)
+
+	for i := 10; i < 20; i++ {
+		c.Add(i, i)
+	}
+
+	if !c.Exist(0) || !c.Exist(1) || c.Exist(2) {
+		t.Errorf("Expected keys read again to survive the scan, got 0 - %t, 1 - %t, 2 - %t", c.Exist(0), c.Exist(1), c.Exist(2))
+	}
+}
+
 func TestCache_AddTimer(t *testing.T) {
 	c := initializeFullCache(10, nil)
 
@@ -530,6 +1138,61 @@ func BenchmarkCache_AddWithTimeout(b *testing.B) {
 	}
 }
 
+func BenchmarkCache_AddWithPriority(b *testing.B) {
+	c := initializeFullCache(0, &Requirements{MaxEntries: 1000})
+
+	for n := 0; n < b.N; n++ {
+		c.AddWithPriority(n, n, Priority(n%3-1))
+	}
+}
+
+func BenchmarkCache_AddToBucket(b *testing.B) {
+	c := initializeFullCache(0, nil)
+
+	boundary := time.Now().Add(time.Second * 90)
+
+	for n := 0; n < b.N; n++ {
+		c.AddToBucket(boundary, n, n)
+	}
+}
+
+func BenchmarkCache_AddOwned(b *testing.B) {
+	c := initializeFullCache(0, nil)
+
+	for n := 0; n < b.N; n++ {
+		c.AddOwned("user", n, n)
+	}
+}
+
+func BenchmarkCache_RemoveByOwner(b *testing.B) {
+	c := initializeFullCache(0, nil)
+
+	for n := 0; n < b.N; n++ {
+		c.AddOwned("user", n, n)
+		c.RemoveByOwner("user")
+	}
+}
+
+func BenchmarkCache_InvalidateN(b *testing.B) {
+	c := initializeFullCache(1000, nil)
+
+	none := func(k, v int) bool { return false }
+
+	for n := 0; n < b.N; n++ {
+		c.InvalidateN(none, 10)
+	}
+}
+
+func BenchmarkCache_RemoveWhere(b *testing.B) {
+	c := initializeFullCache(1000, nil)
+
+	none := func(k, v int) bool { return false }
+
+	for n := 0; n < b.N; n++ {
+		c.RemoveWhere(none)
+	}
+}
+
 func BenchmarkCache_AddTimer(b *testing.B) {
 	c := initializeFullCache(10, nil)
 
@@ -598,6 +1261,14 @@ func BenchmarkCache_GetBulk(b *testing.B) {
 	}
 }
 
+func BenchmarkCache_GetBulkWithDefault(b *testing.B) {
+	c := initializeFullCache(1, nil)
+
+	for n := 0; n < b.N; n++ {
+		c.GetBulkWithDefault([]int{0, 1}, nil)
+	}
+}
+
 func BenchmarkCache_GetAndRemove(b *testing.B) {
 	c := initializeFullCache(2, nil)
 
@@ -622,6 +1293,22 @@ func BenchmarkCache_Count(b *testing.B) {
 	}
 }
 
+func BenchmarkCache_CountWhereApprox(b *testing.B) {
+	c := initializeFullCache(10000, nil)
+
+	for n := 0; n < b.N; n++ {
+		c.CountWhereApprox(func(k, v int) bool { return v%2 == 0 }, 100)
+	}
+}
+
+func BenchmarkCache_Visit(b *testing.B) {
+	c := initializeFullCache(1000, nil)
+
+	for n := 0; n < b.N; n++ {
+		c.Visit(func(k, v int) bool { return true })
+	}
+}
+
 func BenchmarkCache_Reset(b *testing.B) {
 	var c = initializeFullCache(10, nil)
 
@@ -657,6 +1344,16 @@ func BenchmarkMerge(b *testing.B) {
 
 }
 
+func BenchmarkMergeNewer(b *testing.B) {
+	var c1 = initializeFullCache(1, nil)
+	var c2 = initializeFullCache(2, nil)
+
+	for n := 0; n < b.N; n++ {
+		MergeNewer[int, int](&c1, &c2, func(a, b int) bool { return a > b })
+	}
+
+}
+
 func BenchmarkMergeAndReset(b *testing.B) {
 	var c1 = initializeFullCache(1, nil)
 	var c2 = initializeFullCache(2, nil)
@@ -0,0 +1,53 @@
+package cacheMachine
+
+//===========[STRUCTS]==================================================================================================
+
+//ClassStats holds the number of entries of a single class and their total cost, which is their memory if the
+//costs are sizes in bytes
+type ClassStats struct {
+	Entries int   `json:"entries"`
+	Cost    int64 `json:"cost"`
+}
+
+//------PRIVATE------
+
+//classStats breaks the entries down by the classifier. This method has no mutex protection
+func (c *Cache[TKey, TValue]) classStats() map[string]ClassStats {
+	stats := make(map[string]ClassStats)
+
+	for _, e := range c.data {
+		class := c.classifier(e.Val)
+
+		s := stats[class]
+		s.Entries++
+		s.Cost += e.cost
+		stats[class] = s
+	}
+
+	return stats
+}
+
+//------PUBLIC------
+
+//SetClassifier sets the function assigning every value a class, e.g. its type in a cache of any-typed values, so
+//that ClassStats and Describe can break the entries down by class. The function is called under the read lock, so
+//it must be fast and must not use the cache. Nil removes the classifier
+func (c *Cache[TKey, TValue]) SetClassifier(f func(TValue) string) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.classifier = f
+}
+
+//ClassStats returns the number of entries and their total cost in every class assigned by the classifier set using
+//SetClassifier. Returns nil if there is no classifier. It takes time proportional to the size of the cache
+func (c *Cache[TKey, TValue]) ClassStats() map[string]ClassStats {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if c.classifier == nil {
+		return nil
+	}
+
+	return c.classStats()
+}
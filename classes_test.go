@@ -0,0 +1,47 @@
+package cacheMachine
+
+import (
+	"fmt"
+	"testing"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_ClassStats(t *testing.T) {
+	c := New[string, any](nil)
+
+	if s := c.ClassStats(); s != nil {
+		t.Errorf("Expected no class statistics without classifier, got %v", s)
+	}
+
+	c.SetClassifier(func(v any) string { return fmt.Sprintf("%T", v) })
+
+	c.Add("a", blob("12345"))
+	c.Add("b", blob("123"))
+	c.Add("c", 1)
+
+	s := c.ClassStats()
+
+	if blobs := s["cacheMachine.blob"]; blobs.Entries != 2 || blobs.Cost != 8 {
+		t.Errorf("Expected 2 blobs costing 8, got %+v", blobs)
+	}
+
+	if ints := s["int"]; ints.Entries != 1 || ints.Cost != 1 {
+		t.Errorf("Expected 1 int costing 1, got %+v", ints)
+	}
+
+	if d := c.Describe(); len(d.Classes) != 2 {
+		t.Errorf("Expected description to list 2 classes, got %v", d.Classes)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_ClassStats(b *testing.B) {
+	c := initializeFullCache(100, nil)
+	c.SetClassifier(func(v int) string { return fmt.Sprint(v % 10) })
+
+	for n := 0; n < b.N; n++ {
+		c.ClassStats()
+	}
+}
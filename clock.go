@@ -0,0 +1,166 @@
+package cacheMachine
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+//===========[INTERFACES]====================================================================================================
+
+//TimeSource is the clock driving timeouts of entries, set by Requirements.TimeSource, e.g. ManualClock in tests that
+//would otherwise have to sleep until entries expire
+type TimeSource interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+//Timer is a timer scheduled by TimeSource, behaving the same as *time.Timer
+type Timer interface {
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+//===========[STRUCTS]==================================================================================================
+
+//Clock of the system used unless Requirements.TimeSource is set
+type systemClock struct{}
+
+//ManualClock is a TimeSource that only moves when Advance is called, firing the timers that come due in the meantime
+//straight away. It's meant for tests
+type ManualClock struct {
+	now    time.Time
+	timers []*manualTimer
+	mx     sync.Mutex
+}
+
+//Timer scheduled by ManualClock
+type manualTimer struct {
+	clock  *ManualClock
+	when   time.Time
+	f      func()
+	active bool
+}
+
+//------PRIVATE------
+
+//now returns current wall clock time of the clock of the cache
+func (c *Cache[TKey, TValue]) now() time.Time {
+	return c.cache.Requirements.TimeSource.Now().Round(0)
+}
+
+//due removes the earliest timer due at the time specified from the clock. Returns nil if there is none
+func (m *ManualClock) due(t time.Time) *manualTimer {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	sort.SliceStable(m.timers, func(i, j int) bool { return m.timers[i].when.Before(m.timers[j].when) })
+
+	if len(m.timers) < 1 || m.timers[0].when.After(t) {
+		return nil
+	}
+
+	timer := m.timers[0]
+	m.timers = m.timers[1:]
+	timer.active = false
+
+	if timer.when.After(m.now) {
+		m.now = timer.when
+	}
+
+	return timer
+}
+
+//unschedule removes the timer from the clock. Returns false if it wasn't scheduled. This method has no mutex
+//protection
+func (m *ManualClock) unschedule(timer *manualTimer) bool {
+	if !timer.active {
+		return false
+	}
+
+	for i, t := range m.timers {
+		if t == timer {
+			m.timers = append(m.timers[:i], m.timers[i+1:]...)
+			break
+		}
+	}
+
+	timer.active = false
+	return true
+}
+
+//------PUBLIC------
+
+//Now returns current time of the system
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+//AfterFunc schedules the function using time.AfterFunc
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+//Now returns current time of the clock
+func (m *ManualClock) Now() time.Time {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	return m.now
+}
+
+//AfterFunc schedules the function to be called once the clock is advanced by the duration specified
+func (m *ManualClock) AfterFunc(d time.Duration, f func()) Timer {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	timer := &manualTimer{clock: m, when: m.now.Add(d), f: f, active: true}
+	m.timers = append(m.timers, timer)
+
+	return timer
+}
+
+//Advance moves the clock forward by the duration specified, calling the functions of the timers that come due in
+//the order of their due times. Functions are called by the goroutine advancing the clock, so once Advance returns,
+//all the entries due have expired
+func (m *ManualClock) Advance(d time.Duration) {
+	m.mx.Lock()
+	target := m.now.Add(d)
+	m.mx.Unlock()
+
+	for timer := m.due(target); timer != nil; timer = m.due(target) {
+		timer.f()
+	}
+
+	m.mx.Lock()
+	m.now = target
+	m.mx.Unlock()
+}
+
+//Stop stops the timer. Returns false if the timer had fired or been stopped already
+func (t *manualTimer) Stop() bool {
+	t.clock.mx.Lock()
+	defer t.clock.mx.Unlock()
+	return t.clock.unschedule(t)
+}
+
+//Reset reschedules the timer to fire after the duration specified. Returns false if the timer had fired or been
+//stopped already
+func (t *manualTimer) Reset(d time.Duration) bool {
+	t.clock.mx.Lock()
+	defer t.clock.mx.Unlock()
+
+	active := t.clock.unschedule(t)
+
+	t.when = t.clock.now.Add(d)
+	t.active = true
+	t.clock.timers = append(t.clock.timers, t)
+
+	return active
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//NewManualClock creates ManualClock starting at the time specified
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start.Round(0)}
+}
@@ -0,0 +1,65 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestRequirements_TimeSource(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := New[int, int](&Requirements{TimeSource: clock, StaleFor: time.Minute})
+
+	c.AddWithTimeout(1, 1, time.Hour)
+	c.AddWithDeadline(2, 2, clock.Now().Add(time.Minute))
+	c.AddToBucket(clock.Now().Add(time.Minute*30), 3, 3)
+
+	if ttl := c.GetEntry(1).TTL(); ttl != time.Hour {
+		t.Errorf("Expected TTL of an hour by the manual clock, got %s", ttl)
+	}
+
+	clock.Advance(time.Minute)
+
+	if c.Exist(2) || !c.Exist(1) || !c.Exist(3) {
+		t.Errorf("Expected only the entry with deadline to expire after a minute")
+	}
+
+	if _, stale, ok := c.GetStale(2); !ok || !stale {
+		t.Errorf("Expected the expired entry to be kept as stale")
+	}
+
+	clock.Advance(time.Hour)
+
+	if c.Count() != 0 {
+		t.Errorf("Expected all the entries to expire after an hour, got %v", c.GetAll())
+	}
+
+	if _, _, ok := c.GetStale(2); ok {
+		t.Errorf("Expected the stale entry to be dropped after StaleFor")
+	}
+}
+
+func TestManualClock_Advance(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+
+	var fired []int
+	clock.AfterFunc(time.Second*2, func() { fired = append(fired, 2) })
+	timer := clock.AfterFunc(time.Second, func() { fired = append(fired, 1) })
+	stopped := clock.AfterFunc(time.Second, func() { fired = append(fired, 0) })
+
+	if !stopped.Stop() || stopped.Stop() {
+		t.Errorf("Expected Stop to report whether the timer was scheduled")
+	}
+
+	timer.Reset(time.Second * 3)
+	clock.Advance(time.Second * 3)
+
+	if len(fired) != 2 || fired[0] != 2 || fired[1] != 1 {
+		t.Errorf("Expected timers to fire in order of their due times, got %v", fired)
+	}
+
+	if now := clock.Now(); !now.Equal(time.Unix(3, 0)) {
+		t.Errorf("Expected the clock to be at %s, got %s", time.Unix(3, 0), now)
+	}
+}
@@ -0,0 +1,136 @@
+package cacheMachine
+
+import (
+	"sync"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//Compact is a cache storing values inline in its map, without an entry, timer or mutex per value, e.g. for caches
+//of counters or small structs where the bookkeeping of Cache would outweigh the values themselves. In exchange,
+//it supports none of the features that need per-entry state: timeouts, eviction, priorities, pins and events.
+//Compact is a separate minimal store rather than a storage mode of Cache, so it only shares the interfaces used by
+//Merge and MergeAndReset with it. Its zero value is an empty cache ready to use
+type Compact[TKey Key, TValue any] struct {
+	data map[TKey]TValue
+	mx   sync.RWMutex
+}
+
+//------PRIVATE------
+
+//put stores the value under the key, creating the map on first use so that the zero value is usable. This method
+//has no mutex protection
+func (c *Compact[TKey, TValue]) put(key TKey, val TValue) {
+	if c.data == nil {
+		c.data = make(map[TKey]TValue)
+	}
+
+	c.data[key] = val
+}
+
+//------PUBLIC------
+
+//Add inserts the key:value pair, replacing the value already present under the key
+func (c *Compact[TKey, TValue]) Add(key TKey, val TValue) {
+	c.mx.Lock()
+	c.put(key, val)
+	c.mx.Unlock()
+}
+
+//AddBulk inserts all the key:value pairs supplied
+func (c *Compact[TKey, TValue]) AddBulk(d map[TKey]TValue) {
+	c.mx.Lock()
+	for k, v := range d {
+		c.put(k, v)
+	}
+	c.mx.Unlock()
+}
+
+//Update replaces the value of the key with the result of the function supplied, which gets the current value and
+//whether the key was present, e.g. to increment a counter. Returns the new value. The function is called under the
+//write lock, so it must be fast and must not use the cache
+func (c *Compact[TKey, TValue]) Update(key TKey, f func(TValue, bool) TValue) TValue {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	v, exist := c.data[key]
+	v = f(v, exist)
+	c.put(key, v)
+
+	return v
+}
+
+//Get returns the value of the key and whether the key is present
+func (c *Compact[TKey, TValue]) Get(key TKey) (TValue, bool) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	v, exist := c.data[key]
+	return v, exist
+}
+
+//Exist checks whether the key is present
+func (c *Compact[TKey, TValue]) Exist(key TKey) bool {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	_, exist := c.data[key]
+	return exist
+}
+
+//Remove removes the key
+func (c *Compact[TKey, TValue]) Remove(key TKey) {
+	c.mx.Lock()
+	delete(c.data, key)
+	c.mx.Unlock()
+}
+
+//Count returns number of keys present
+func (c *Compact[TKey, TValue]) Count() int {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	return len(c.data)
+}
+
+//GetAll returns a copy of all the key:value pairs
+func (c *Compact[TKey, TValue]) GetAll() map[TKey]TValue {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	cpy := make(map[TKey]TValue, len(c.data))
+	for k, v := range c.data {
+		cpy[k] = v
+	}
+
+	return cpy
+}
+
+//GetAllAndRemove returns all the key:value pairs and empties the cache
+func (c *Compact[TKey, TValue]) GetAllAndRemove() map[TKey]TValue {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	d := c.data
+	if d == nil {
+		d = make(map[TKey]TValue)
+	}
+	c.data = make(map[TKey]TValue)
+
+	return d
+}
+
+//Reset empties the cache
+func (c *Compact[TKey, TValue]) Reset() {
+	c.mx.Lock()
+	c.data = make(map[TKey]TValue)
+	c.mx.Unlock()
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//NewCompact creates a cache storing values inline, the same as its zero value. It can be merged with Cache both ways
+//using Merge and MergeAndReset
+func NewCompact[TKey Key, TValue any]() *Compact[TKey, TValue] {
+	return &Compact[TKey, TValue]{data: make(map[TKey]TValue)}
+}
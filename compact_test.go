@@ -0,0 +1,65 @@
+package cacheMachine
+
+import (
+	"testing"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCompact_Update(t *testing.T) {
+	c := NewCompact[string, int]()
+	inc := func(v int, exist bool) int { return v + 1 }
+
+	c.Update("a", inc)
+	c.Update("a", inc)
+
+	if v, ok := c.Get("a"); !ok || v != 2 {
+		t.Errorf("Expected counter to be %d, got %d and %t", 2, v, ok)
+	}
+}
+
+func TestCompact_Merge(t *testing.T) {
+	c := NewCompact[int, int]()
+	c.AddBulk(map[int]int{1: 1, 2: 2})
+	c.Remove(2)
+
+	main := initializeFullCache(0, nil)
+	MergeAndReset[int, int](&main, c)
+
+	if main.Count() != 1 || c.Count() != 0 || c.Exist(1) {
+		t.Errorf("Expected the compact cache to be moved into the main cache, got %d and %d entries", main.Count(), c.Count())
+	}
+
+	Merge[int, int](c, &main)
+
+	if all := c.GetAll(); len(all) != 1 || all[1] != 1 {
+		t.Errorf("Expected the main cache to be merged into the compact cache, got %v", all)
+	}
+}
+
+func TestCompact_zeroValue(t *testing.T) {
+	c := Compact[int, int]{}
+
+	if _, ok := c.Get(1); ok || c.Count() != 0 || c.GetAllAndRemove() == nil {
+		t.Errorf("Expected the zero value to be an empty cache")
+	}
+
+	c.Add(1, 1)
+	c.AddBulk(map[int]int{2: 2})
+	c.Update(3, func(v int, exist bool) int { return 3 })
+
+	if all := c.GetAll(); len(all) != 3 || all[3] != 3 {
+		t.Errorf("Expected the zero value to be usable, got %v", all)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCompact_Update(b *testing.B) {
+	c := NewCompact[int, int]()
+	inc := func(v int, exist bool) int { return v + 1 }
+
+	for n := 0; n < b.N; n++ {
+		c.Update(n%100, inc)
+	}
+}
@@ -0,0 +1,61 @@
+package cacheMachine
+
+//===========[INTERFACES]===============================================================================================
+
+//Sizer is implemented by values that know their size in bytes. Unless SetCostFunc is used, such values cost their
+//size, so Requirements.MaxCost bounds the memory used by the values
+type Sizer interface {
+	SizeBytes() int64
+}
+
+//------PRIVATE------
+
+//costOf returns the cost of the value stored under the key. This method has no mutex protection
+func (c *Cache[TKey, TValue]) costOf(key TKey, val TValue) int64 {
+	if c.costFunc != nil {
+		return c.costFunc(key, val)
+	}
+
+	if s, ok := any(val).(Sizer); ok {
+		return s.SizeBytes()
+	}
+
+	return 1
+}
+
+//overLimit checks whether the cache holds more entries than MaxEntries or more cost than MaxCost allows. This
+//method has no mutex protection
+func (c *Cache[TKey, TValue]) overLimit() bool {
+	r := &c.cache.Requirements
+
+	return r.MaxEntries > 0 && len(c.data) > r.MaxEntries || r.MaxCost > 0 && c.cost > r.MaxCost
+}
+
+//------PUBLIC------
+
+//SetCostFunc sets the function computing the cost of every entry counted towards Requirements.MaxCost, e.g. the
+//size of the value in bytes. Costs of the entries already present are recomputed and entries are evicted if the
+//cache no longer fits within MaxCost. Nil resets every entry to its default cost: SizeBytes if the value implements
+//Sizer, 1 otherwise. The function is called under the write lock, so it must be fast and must not use the cache
+func (c *Cache[TKey, TValue]) SetCostFunc(f func(TKey, TValue) int64) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.costFunc = f
+	c.cost = 0
+
+	for key, e := range c.data {
+		e.cost = c.costOf(key, e.Val)
+		c.cost += e.cost
+	}
+
+	c.evict()
+}
+
+//Cost returns the total cost of all the entries in the cache
+func (c *Cache[TKey, TValue]) Cost() int64 {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	return c.cost
+}
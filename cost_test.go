@@ -0,0 +1,98 @@
+package cacheMachine
+
+import (
+	"testing"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//Value reporting its size in bytes
+type blob []byte
+
+func (b blob) SizeBytes() int64 { return int64(len(b)) }
+
+//===========[TESTING]====================================================================================================
+
+func TestRequirements_MaxCost(t *testing.T) {
+	c := New[string, []byte](&Requirements{MaxCost: 100})
+	c.SetCostFunc(func(key string, val []byte) int64 { return int64(len(val)) })
+
+	c.Add("small1", make([]byte, 10))
+	c.Add("small2", make([]byte, 10))
+	c.Add("large", make([]byte, 70))
+
+	if c.Count() != 3 || c.Cost() != 90 {
+		t.Errorf("Expected 3 entries of cost 90, got %d entries of cost %d", c.Count(), c.Cost())
+	}
+
+	c.Add("medium", make([]byte, 30))
+
+	if c.Exist("small1") || c.Exist("small2") || !c.Exist("large") || !c.Exist("medium") {
+		t.Errorf("Expected the oldest entries to be evicted until the cache fits within MaxCost, got keys %v", c.keys())
+	}
+
+	if c.Cost() != 100 {
+		t.Errorf("Expected total cost %d, got %d", 100, c.Cost())
+	}
+
+	c.Add("large", make([]byte, 5))
+
+	if c.Cost() != 35 {
+		t.Errorf("Expected replaced entry to be counted with its new cost, got total cost %d", c.Cost())
+	}
+
+	c.Remove("medium")
+
+	if c.Cost() != 5 {
+		t.Errorf("Expected removed entry not to be counted, got total cost %d", c.Cost())
+	}
+}
+
+func TestCache_SetCostFunc(t *testing.T) {
+	c := initializeFullCache(10, &Requirements{MaxCost: 20})
+
+	if c.Cost() != 10 {
+		t.Errorf("Expected every entry to cost 1 without cost function, got total cost %d", c.Cost())
+	}
+
+	c.SetCostFunc(func(key, val int) int64 { return 4 })
+
+	if c.Count() != 5 || c.Cost() != 20 {
+		t.Errorf("Expected 5 entries of cost 20 to be left, got %d entries of cost %d", c.Count(), c.Cost())
+	}
+
+	c.Reset()
+
+	if c.Cost() != 0 {
+		t.Errorf("Expected total cost to be 0 after reset, got %d", c.Cost())
+	}
+}
+
+func TestSizer(t *testing.T) {
+	c := New[string, blob](&Requirements{MaxCost: 1024})
+
+	c.Add("a", make(blob, 512))
+	c.Add("b", make(blob, 512))
+	c.Add("c", make(blob, 256))
+
+	if c.Exist("a") || c.Cost() != 768 {
+		t.Errorf("Expected values to be accounted by their size, got total cost %d", c.Cost())
+	}
+
+	c.SetCostFunc(func(key string, val blob) int64 { return 1 })
+
+	if c.Cost() != 2 {
+		t.Errorf("Expected cost function to take precedence over Sizer, got total cost %d", c.Cost())
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkRequirements_MaxCost(b *testing.B) {
+	c := New[int, int](&Requirements{MaxCost: 1000})
+	c.SetCostFunc(func(key, val int) int64 { return int64(val % 10) })
+
+	for n := 0; n < b.N; n++ {
+		c.Add(n%5000, n)
+	}
+}
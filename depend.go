@@ -0,0 +1,82 @@
+package cacheMachine
+
+//------PRIVATE------
+
+//removeDependents forgets the dependencies of the key that has just been removed and removes the keys depending on
+//it, emitting the same kind of event for every one of them. This method has no mutex protection
+func (c *Cache[TKey, TValue]) removeDependents(kind EventKind, key TKey) {
+	for parent := range c.dependencies[key] {
+		removeFromSet(c.dependents, parent, key)
+	}
+	delete(c.dependencies, key)
+
+	//Dependents are forgotten first, so that cycles of dependencies don't remove the key again
+	children := c.dependents[key]
+	delete(c.dependents, key)
+
+	for child := range children {
+		removeFromSet(c.dependencies, child, key)
+	}
+
+	for child := range children {
+		if e, exist := c.data[child]; exist {
+			c.remove(child)
+			c.emit(kind, child, e)
+		}
+	}
+}
+
+//------PUBLIC------
+
+//DependOn makes the child key depend on the parent key, e.g. derived objects on their source, so that whenever the
+//parent is removed, expired or evicted, the child is removed along with it, pinned or not. Removals cascade further
+//to the dependents of the child. Dependencies are kept when values of the keys are replaced and are forgotten once
+//either of the keys is removed. Returns false if either key is not present or they are the same key
+func (c *Cache[TKey, TValue]) DependOn(child, parent TKey) bool {
+	if child == parent {
+		return false
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if _, exist := c.data[child]; !exist {
+		return false
+	}
+
+	if _, exist := c.data[parent]; !exist {
+		return false
+	}
+
+	if c.dependents == nil {
+		c.dependents = make(map[TKey]map[TKey]struct{})
+		c.dependencies = make(map[TKey]map[TKey]struct{})
+	}
+
+	addToSet(c.dependents, parent, child)
+	addToSet(c.dependencies, child, parent)
+
+	return true
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//addToSet records the key in the set of keys indexed by the other key specified
+func addToSet[TKey Key](sets map[TKey]map[TKey]struct{}, by, key TKey) {
+	s, exist := sets[by]
+	if !exist {
+		s = make(map[TKey]struct{})
+		sets[by] = s
+	}
+	s[key] = struct{}{}
+}
+
+//removeFromSet removes the key from the set of keys indexed by the other key specified, dropping the set once it's empty
+func removeFromSet[TKey Key](sets map[TKey]map[TKey]struct{}, by, key TKey) {
+	if s, exist := sets[by]; exist {
+		delete(s, key)
+		if len(s) < 1 {
+			delete(sets, by)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_DependOn(t *testing.T) {
+	c := New[int, int](nil)
+
+	c.AddBulk(map[int]int{1: 1, 2: 2, 3: 3, 4: 4})
+
+	if !c.DependOn(2, 1) || !c.DependOn(3, 2) || c.DependOn(4, 100) || c.DependOn(4, 4) {
+		t.Fatalf("Expected dependencies only between different keys that are present")
+	}
+
+	c.Pin(3)
+	c.Add(1, 10)
+	c.Remove(1)
+
+	if c.Count() != 1 || !c.Exist(4) {
+		t.Errorf("Expected removal of the parent to cascade to its dependents, got %v", c.GetAll())
+	}
+
+	//Dependencies are forgotten with the keys, so that re-added keys don't depend on anything
+	c.AddBulk(map[int]int{1: 1, 2: 2})
+	c.Remove(1)
+
+	if !c.Exist(2) {
+		t.Errorf("Expected the re-added key not to depend on the parent any more")
+	}
+}
+
+func TestCache_DependOn_expiry(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := New[int, int](&Requirements{TimeSource: clock})
+
+	var events []change[int]
+	c.Subscribe(func(e Event[int]) {
+		if e.Kind != EventAdd {
+			events = append(events, change[int]{e.Kind, e.Key})
+		}
+	})
+
+	c.AddWithTimeout(1, 1, time.Minute)
+	c.Add(2, 2)
+	c.DependOn(2, 1)
+	c.DependOn(1, 2)
+
+	clock.Advance(time.Minute)
+
+	if c.Count() != 0 || len(events) != 2 || events[1] != (change[int]{EventExpire, 2}) {
+		t.Errorf("Expected the dependent to expire along with the parent, got %v and events %v", c.GetAll(), events)
+	}
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if len(c.dependents) != 0 || len(c.dependencies) != 0 {
+		t.Errorf("Expected no dependencies to be left, got %v and %v", c.dependents, c.dependencies)
+	}
+}
@@ -0,0 +1,160 @@
+package cacheMachine
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//Description is the effective configuration and state of the cache, meant for diagnostics. It can be marshalled
+//as JSON directly
+type Description struct {
+	//Name of the eviction policy in use, or "custom" if it was set using SetEvictionPolicy
+	Policy string `json:"policy"`
+
+	MaxEntries    int    `json:"max_entries"`
+	HighWatermark int    `json:"high_watermark"`
+	MaxCost       int64  `json:"max_cost"`
+	LockChunkSize int    `json:"lock_chunk_size"`
+	TrackedKeys   int    `json:"tracked_keys"`
+	ExpiryMode    string `json:"expiry_mode"`
+	FairEviction  bool   `json:"fair_eviction"`
+
+	SlidingTimeout  bool  `json:"sliding_timeout"`
+	CleanupInterval int64 `json:"cleanup_interval_ns"`
+
+	//Limit of the timers of entries and what happens past it, along with number of timers scheduled at the moment
+	MaxTimers     int    `json:"max_timers"`
+	TimerOverflow string `json:"timer_overflow"`
+	ActiveTimers  int64  `json:"active_timers"`
+
+	//How long expired entries are kept as stale in nanoseconds and number of them kept at the moment
+	StaleFor int64 `json:"stale_for_ns"`
+	Stale    int   `json:"stale"`
+
+	//Limits of concurrent loads
+	MaxConcurrentLoads int `json:"max_concurrent_loads"`
+	MaxQueuedLoads     int `json:"max_queued_loads"`
+
+	//Longest wait for the write lock of TryAdd, TryAddBulk and TryRemove in nanoseconds
+	MaxLockWait int64 `json:"max_lock_wait_ns"`
+
+	//Maximum number of values returned by GetAll and GetBulk
+	MaxBulkResult int `json:"max_bulk_result"`
+
+	//Whether a closed cache passes operations through instead of failing
+	SoftFail bool `json:"soft_fail"`
+
+	//Default timeout and its jitter in nanoseconds and whether the timeout is applied to new entries
+	DefaultTimeout int64 `json:"default_timeout_ns"`
+	TimeoutJitter  int64 `json:"timeout_jitter_ns"`
+	TimeoutInUse   bool  `json:"timeout_in_use"`
+
+	//Bounds of adaptive timeouts in nanoseconds. Nil if adaptive timeouts are off
+	AdaptiveTTL *AdaptiveTTLDescription `json:"adaptive_ttl,omitempty"`
+
+	//Features in use
+	KeyStats  bool `json:"key_stats"`
+	Tracing   bool `json:"tracing"`
+	Admission bool `json:"admission"`
+
+	//Current state
+	Entries int            `json:"entries"`
+	Cost    int64          `json:"cost"`
+	Levels  map[string]int `json:"levels"`
+	Pinned  int            `json:"pinned"`
+	Buckets int            `json:"buckets"`
+	Owners  int            `json:"owners"`
+
+	//Entries broken down by the classifier set by SetClassifier. Nil if there is no classifier
+	Classes map[string]ClassStats `json:"classes,omitempty"`
+}
+
+//AdaptiveTTLDescription is the AdaptiveTTL configuration in use
+type AdaptiveTTLDescription struct {
+	Min  int64 `json:"min_ns"`
+	Max  int64 `json:"max_ns"`
+	Hits int   `json:"hits"`
+}
+
+//------PUBLIC------
+
+//String returns the description as JSON
+func (d Description) String() string {
+	b, _ := json.Marshal(d)
+	return string(b)
+}
+
+//Describe returns the effective configuration of the cache including the settings derived from Requirements,
+//along with number of entries in every Priority level, number of time buckets and owners
+func (c *Cache[TKey, TValue]) Describe() Description {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	r := c.cache.Requirements
+
+	d := Description{
+		Policy:             r.Policy.String(),
+		MaxEntries:         r.MaxEntries,
+		HighWatermark:      r.HighWatermark,
+		MaxCost:            r.MaxCost,
+		LockChunkSize:      r.LockChunkSize,
+		TrackedKeys:        r.TrackedKeys,
+		ExpiryMode:         r.ExpiryMode.String(),
+		FairEviction:       r.FairEviction,
+		SlidingTimeout:     r.SlidingTimeout,
+		CleanupInterval:    int64(r.CleanupInterval),
+		MaxTimers:          r.MaxTimers,
+		TimerOverflow:      r.TimerOverflow.String(),
+		ActiveTimers:       atomic.LoadInt64(&c.timers),
+		StaleFor:           int64(r.StaleFor),
+		Stale:              len(c.stale),
+		MaxConcurrentLoads: r.MaxConcurrentLoads,
+		MaxQueuedLoads:     r.MaxQueuedLoads,
+		MaxLockWait:        int64(r.MaxLockWait),
+		MaxBulkResult:      r.MaxBulkResult,
+		SoftFail:           r.SoftFail,
+		DefaultTimeout:     int64(r.DefaultTimeout),
+		TimeoutJitter:      int64(r.TimeoutJitter),
+		TimeoutInUse:       r.timeoutInUse,
+		KeyStats:           c.keyStats != nil,
+		Tracing:            c.tracer != nil,
+		Admission:          c.admission != nil,
+		Entries:            len(c.data),
+		Cost:               c.cost,
+		Pinned:             c.pinned,
+		Levels:             make(map[string]int, len(c.levels)),
+		Buckets:            len(c.buckets),
+		Owners:             len(c.owners),
+	}
+
+	if c.classifier != nil {
+		d.Classes = c.classStats()
+	}
+
+	if c.customPolicy {
+		d.Policy = "custom"
+	}
+
+	if a := r.AdaptiveTTL; a != nil {
+		d.AdaptiveTTL = &AdaptiveTTLDescription{Min: int64(a.Min), Max: int64(a.Max), Hits: a.Hits}
+	}
+
+	//Keys of a cache without limits are not handed over to the eviction policies until they are needed
+	if c.untracked {
+		for _, e := range c.data {
+			if !e.pinned {
+				d.Levels[e.priority.String()]++
+			}
+		}
+	}
+
+	for _, levels := range []map[levelID]*level[TKey]{c.levels, c.drainLevels} {
+		for id, l := range levels {
+			d.Levels[id.priority.String()] += l.size
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,45 @@
+package cacheMachine
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_Describe(t *testing.T) {
+	c := initializeFullCache(3, &Requirements{DefaultTimeout: time.Second * 30, MaxEntries: 10, Policy: LRU})
+	c.AddWithPriority(10, 10, PriorityHigh)
+
+	d := c.Describe()
+
+	if d.Policy != "LRU" || d.MaxEntries != 10 || !d.TimeoutInUse || d.DefaultTimeout != int64(time.Second*30) {
+		t.Errorf("Unexpected configuration in the description: %+v", d)
+	}
+
+	if d.Entries != 4 || d.Levels["normal"] != 3 || d.Levels["high"] != 1 {
+		t.Errorf("Expected 4 entries with 3 normal and 1 high priority, got %d entries and levels %v", d.Entries, d.Levels)
+	}
+
+	c.SetEvictionPolicy(NewLFU[int])
+
+	if p := c.Describe().Policy; p != "custom" {
+		t.Errorf("Expected policy to be described as %q, got %q", "custom", p)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(d.String()), &decoded); err != nil || decoded["expiry_mode"] != "monotonic" {
+		t.Errorf("Expected description to be valid JSON with expiry mode, got %s and error %v", d.String(), err)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_Describe(b *testing.B) {
+	c := initializeFullCache(100, nil)
+
+	for n := 0; n < b.N; n++ {
+		c.Describe()
+	}
+}
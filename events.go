@@ -0,0 +1,122 @@
+package cacheMachine
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//EventKind is the kind of change made to the cache
+type EventKind int
+
+const (
+	//EventAdd is emitted when the key is added or its value replaced
+	EventAdd EventKind = iota
+
+	//EventRemove is emitted when the key is removed explicitly, e.g. by Remove or RemoveWhere
+	EventRemove
+
+	//EventExpire is emitted when the key is removed because its timeout or time bucket has expired
+	EventExpire
+
+	//EventEvict is emitted when the key is evicted to keep the cache within its limits
+	EventEvict
+)
+
+//Event describes a single change made to the cache
+type Event[TKey Key] struct {
+	Kind EventKind
+	Key  TKey
+
+	//Time the entry was stored at and the number of times it was read since, e.g. to tell entries that expired
+	//without ever being used from entries in use that were evicted
+	Created time.Time
+	Hits    uint32
+
+	//Metadata of the entry, e.g. the trace ID of the request that added it. Nil if the entry has no metadata
+	Metadata Metadata
+}
+
+//------PRIVATE------
+
+//emit notifies all the subscribers about the change of the entry stored under the key. Removals are also handed
+//over to the function set by SetOnRemove and take the rest of the group of the entry along. This method has no
+//mutex protection
+func (c *Cache[TKey, TValue]) emit(kind EventKind, key TKey, e *entry[TValue]) {
+	switch kind {
+	case EventRemove:
+		c.removed(key, e, RemovalExplicit)
+	case EventExpire:
+		c.removed(key, e, RemovalExpired)
+	case EventEvict:
+		c.removed(key, e, RemovalEvicted)
+	}
+
+	if kind != EventAdd && len(c.groups) > 0 {
+		defer c.removeGroup(kind, key)
+	}
+
+	if kind != EventAdd && len(c.dependencies)+len(c.dependents) > 0 {
+		defer c.removeDependents(kind, key)
+	}
+
+	if len(c.listeners) < 1 {
+		return
+	}
+
+	ev := Event[TKey]{Kind: kind, Key: key, Created: e.created, Hits: atomic.LoadUint32(&e.reads), Metadata: e.meta}
+
+	for _, f := range c.listeners {
+		f(ev)
+	}
+}
+
+//------PUBLIC------
+
+//String returns the name of the event kind
+func (k EventKind) String() string {
+	switch k {
+	case EventAdd:
+		return "add"
+	case EventRemove:
+		return "remove"
+	case EventExpire:
+		return "expire"
+	case EventEvict:
+		return "evict"
+	}
+
+	return "EventKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+//Subscribe calls the function supplied for every change made to the cache until the returned function is called.
+//Events of the same key are delivered in the order the changes were made, because the function is called while the
+//cache is still locked. Therefore it must return quickly and must not use the cache. Reset and GetAllAndRemove
+//don't emit events for the entries they remove
+func (c *Cache[TKey, TValue]) Subscribe(f func(Event[TKey])) (unsubscribe func()) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	id := c.nextListener
+	c.nextListener++
+	c.listeners[id] = f
+
+	return func() {
+		c.mx.Lock()
+		delete(c.listeners, id)
+		c.mx.Unlock()
+	}
+}
+
+//SubscribeWhere does the same as Subscribe, but only for the changes of the keys matching the predicate supplied,
+//e.g. a prefix check with strings.HasPrefix, so that consumers interested in a few keys aren't flooded by the rest.
+//The predicate is called under the lock as well
+func (c *Cache[TKey, TValue]) SubscribeWhere(match func(TKey) bool, f func(Event[TKey])) (unsubscribe func()) {
+	return c.Subscribe(func(e Event[TKey]) {
+		if match(e.Key) {
+			f(e)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package cacheMachine
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//Kind and key of an event, which is all the tests compare
+type change[TKey Key] struct {
+	kind EventKind
+	key  TKey
+}
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_Subscribe(t *testing.T) {
+	c := initializeFullCache(0, &Requirements{MaxEntries: 2})
+
+	var events []change[int]
+	unsubscribe := c.Subscribe(func(e Event[int]) { events = append(events, change[int]{e.Kind, e.Key}) })
+
+	c.Add(1, 1)
+	c.Add(2, 2)
+	c.Add(3, 3)
+	c.Remove(2)
+	c.Remove(100)
+	c.AddWithTimeout(3, 3, time.Millisecond*10)
+
+	time.Sleep(time.Millisecond * 100)
+
+	c.mx.RLock()
+	expected := []change[int]{{EventAdd, 1}, {EventAdd, 2}, {EventAdd, 3}, {EventEvict, 1}, {EventRemove, 2}, {EventAdd, 3}, {EventExpire, 3}}
+
+	if len(events) != len(expected) {
+		t.Fatalf("Expected to get events %v, got %v", expected, events)
+	}
+
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("Expected event %d to be %v, got %v", i, expected[i], events[i])
+		}
+	}
+
+	c.mx.RUnlock()
+
+	unsubscribe()
+	c.Add(4, 4)
+
+	if len(events) != len(expected) {
+		t.Errorf("Expected no events after unsubscribing, got %v", events[len(expected):])
+	}
+}
+
+func TestCache_SubscribeWhere(t *testing.T) {
+	c := New[string, int](nil)
+
+	var events []change[string]
+	c.SubscribeWhere(func(key string) bool { return strings.HasPrefix(key, "user:") }, func(e Event[string]) {
+		events = append(events, change[string]{e.Kind, e.Key})
+	})
+
+	c.Add("user:1", 1)
+	c.Add("session:1", 1)
+	c.Remove("user:1")
+	c.Remove("session:1")
+
+	expected := []change[string]{{EventAdd, "user:1"}, {EventRemove, "user:1"}}
+
+	if len(events) != len(expected) || events[0] != expected[0] || events[1] != expected[1] {
+		t.Errorf("Expected to get events %v, got %v", expected, events)
+	}
+}
+
+func TestEvent_Hits(t *testing.T) {
+	c := New[int, int](&Requirements{MaxEntries: 2})
+
+	var evicted []Event[int]
+	c.Subscribe(func(e Event[int]) {
+		if e.Kind == EventEvict {
+			evicted = append(evicted, e)
+		}
+	})
+
+	start := time.Now()
+
+	c.Add(1, 1)
+	c.Add(2, 2)
+	c.Get(1)
+	c.Get(1)
+	c.Add(3, 3)
+
+	if len(evicted) != 1 || evicted[0].Key != 1 || evicted[0].Hits != 2 || evicted[0].Created.Before(start) {
+		t.Errorf("Expected eviction of key 1 with 2 hits created after %s, got %+v", start, evicted)
+	}
+}
+
+func TestEventKind_String(t *testing.T) {
+	if s := EventExpire.String(); s != "expire" {
+		t.Errorf("Expected %q, got %q", "expire", s)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_Subscribe(b *testing.B) {
+	c := initializeFullCache(0, nil)
+	c.Subscribe(func(e Event[int]) {})
+
+	for n := 0; n < b.N; n++ {
+		c.Add(n%100, n)
+	}
+}
@@ -0,0 +1,671 @@
+package cacheMachine
+
+import (
+	"container/list"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//Number of keys sampled by the Random policy when choosing the victim
+const randomSamples = 5
+
+//Share of the keys the SLRU policy keeps in the protected segment if Requirements.ProtectedRatio is not set
+const defaultProtectedRatio = 0.8
+
+//All the built-in eviction policies
+var builtinPolicies = []Policy{FIFO, LRU, LFU, Random, SLRU, Clock, TwoQueue}
+
+//Shares of the keys the TwoQueue policy keeps in the queue of keys seen once and remembers after evicting them
+const (
+	twoQueueInRatio    = 0.25
+	twoQueueGhostRatio = 0.5
+)
+
+//===========[INTERFACES]===============================================================================================
+
+//EvictionPolicy decides which key gets evicted when the cache grows past Requirements.MaxEntries. The cache keeps
+//a separate policy for every Priority level and notifies it about keys being added, read and removed. Methods are
+//never called concurrently on the same policy
+type EvictionPolicy[TKey Key] interface {
+	//OnAdd is called when a new key is added to the cache
+	OnAdd(key TKey)
+
+	//OnGet is called when the value of the key is read from the cache
+	OnGet(key TKey)
+
+	//OnRemove is called when the key is removed from the cache, whether by eviction or otherwise
+	OnRemove(key TKey)
+
+	//Victim returns the key that should be evicted next without forgetting it. The cache calls OnRemove once the
+	//key is actually removed. Returns false if the policy doesn't track any keys
+	Victim() (TKey, bool)
+}
+
+//ReadIgnoringPolicy can be implemented by eviction policies whose OnGet does nothing, e.g. FIFO. If IgnoresReads
+//returns true when the policy is created, OnGet is never called, so reads don't have to take turns notifying it
+type ReadIgnoringPolicy interface {
+	IgnoresReads() bool
+}
+
+//===========[STRUCTS]==================================================================================================
+
+//Policy selects one of the built-in eviction policies
+type Policy int
+
+const (
+	//FIFO evicts the key that was added the earliest
+	FIFO Policy = iota
+
+	//LRU evicts the key that was read or added the least recently
+	LRU
+
+	//LFU evicts the key that was read the least number of times. Ties are broken by evicting the oldest key
+	LFU
+
+	//Random evicts the oldest of a few keys picked at random. Reads need no bookkeeping at all, which makes it the
+	//cheapest policy, at the cost of evicting keys that are still in use more often
+	Random
+
+	//SLRU evicts the least recently used key among the keys read at most once since they were added. Keys read
+	//again are protected until they become the least recently used among the protected keys, so one-off scans
+	//can't flush keys in use. The share of protected keys is set by Requirements.ProtectedRatio
+	SLRU
+
+	//Clock approximates LRU by giving keys read since the hand of the clock last passed them a second chance. Reads
+	//only set a flag rather than moving the key, which makes them cheaper than with LRU
+	Clock
+
+	//TwoQueue evicts keys seen once in the order they were added, until they are only a quarter of the keys. Keys
+	//added again soon after being evicted that way, and keys read while in the main queue, are kept in least recently
+	//used order. Unlike with LRU, a scan of the whole keyspace can only flush the keys seen once
+	TwoQueue
+)
+
+//Identifies eviction level by its priority and, if FairEviction is on, by the owner of its entries
+type levelID struct {
+	priority Priority
+	owner    string
+}
+
+//Eviction bookkeeping of a single priority level
+type level[TKey Key] struct {
+	policy EvictionPolicy[TKey]
+	size   int
+
+	//Defines whether the policy declared that it ignores reads, see ReadIgnoringPolicy
+	ignoresReads bool
+}
+
+//First in first out eviction policy. If recency is set, reading the key moves it to the back of the queue,
+//which makes this least recently used policy
+type fifo[TKey Key] struct {
+	order    *list.List
+	elements map[TKey]*list.Element
+	recency  bool
+}
+
+//Group of keys that have been read the same number of times
+type lfuBucket[TKey Key] struct {
+	freq int
+	keys *list.List
+}
+
+//Position of the key within LFU buckets
+type lfuItem[TKey Key] struct {
+	bucket  *list.Element
+	element *list.Element
+}
+
+//Least frequently used eviction policy. Buckets are ordered by frequency, the least frequent bucket at the front,
+//so that all the operations take constant time
+type lfu[TKey Key] struct {
+	buckets *list.List
+	items   map[TKey]*lfuItem[TKey]
+}
+
+//Key tracked by the random policy along with the order in which it was added
+type sampledKey[TKey Key] struct {
+	key TKey
+	seq uint64
+}
+
+//Random eviction policy. Keys are kept in a slice, so that they can be picked at random in constant time. It can't
+//sample through GetRandomSamples: that walks the whole cache, pinned entries and other Priority levels included,
+//needs the lock of the cache and returns keys in map iteration order, which is neither random nor constant time
+type random[TKey Key] struct {
+	keys    []sampledKey[TKey]
+	indexes map[TKey]int
+	seq     uint64
+	rnd     *rand.Rand
+
+	//Victim chosen last time, kept until the keys change so that Victim keeps returning the same key
+	victim *TKey
+}
+
+//Position of the key within SLRU segments
+type slruItem[TKey Key] struct {
+	key       TKey
+	protected bool
+}
+
+//Segmented least recently used eviction policy. New keys enter the probation segment and are promoted to the
+//protected segment when read. Once the protected segment grows past its share, its least recently used key is
+//demoted back to probation
+type slru[TKey Key] struct {
+	probation *list.List
+	protected *list.List
+	elements  map[TKey]*list.Element
+	ratio     float64
+}
+
+//Slot of the clock holding a single key
+type clockSlot[TKey Key] struct {
+	key  TKey
+	ref  bool
+	used bool
+}
+
+//Clock eviction policy. Keys are kept in a ring of slots the hand goes around, slots of removed keys are reused
+type clock[TKey Key] struct {
+	slots   []clockSlot[TKey]
+	indexes map[TKey]int
+	free    []int
+	hand    int
+}
+
+//Position of the key within 2Q queues
+type twoQueueItem[TKey Key] struct {
+	key  TKey
+	main bool
+}
+
+//2Q eviction policy. New keys enter the first in first out queue. Keys evicted from it are remembered as ghosts for
+//a while, and if they are added again, they enter the main least recently used queue instead
+type twoQueue[TKey Key] struct {
+	in       *list.List
+	main     *list.List
+	elements map[TKey]*list.Element
+
+	//Keys recently evicted from the queue of keys seen once, oldest at the front
+	ghosts      *list.List
+	ghostByKeys map[TKey]*list.Element
+
+	//Victim chosen last time, so that its removal can be told apart from other removals
+	victim *TKey
+}
+
+//------PUBLIC------
+
+//String returns the name of the policy
+func (p Policy) String() string {
+	switch p {
+	case FIFO:
+		return "FIFO"
+	case LRU:
+		return "LRU"
+	case LFU:
+		return "LFU"
+	case Random:
+		return "Random"
+	case SLRU:
+		return "SLRU"
+	case Clock:
+		return "Clock"
+	case TwoQueue:
+		return "2Q"
+	}
+
+	return "Policy(" + strconv.Itoa(int(p)) + ")"
+}
+
+//OnAdd appends the key to the back of the queue, unless it's already there
+func (p *fifo[TKey]) OnAdd(key TKey) {
+	if _, exist := p.elements[key]; exist {
+		return
+	}
+
+	p.elements[key] = p.order.PushBack(key)
+}
+
+//OnGet moves the key to the back of the queue if the policy orders keys by recency, as LRU does
+func (p *fifo[TKey]) OnGet(key TKey) {
+	if !p.recency {
+		return
+	}
+
+	if el, exist := p.elements[key]; exist {
+		p.order.MoveToBack(el)
+	}
+}
+
+//IgnoresReads reports that reads don't matter to the policy unless it orders keys by recency
+func (p *fifo[TKey]) IgnoresReads() bool { return !p.recency }
+
+//OnRemove drops the key from the queue
+func (p *fifo[TKey]) OnRemove(key TKey) {
+	if el, exist := p.elements[key]; exist {
+		p.order.Remove(el)
+		delete(p.elements, key)
+	}
+}
+
+//Victim returns the key at the front of the queue
+func (p *fifo[TKey]) Victim() (TKey, bool) {
+	if el := p.order.Front(); el != nil {
+		return el.Value.(TKey), true
+	}
+
+	var nilKey TKey
+	return nilKey, false
+}
+
+//OnAdd puts the key into the bucket of frequency 0, unless it's already tracked
+func (p *lfu[TKey]) OnAdd(key TKey) {
+	if _, exist := p.items[key]; exist {
+		return
+	}
+
+	front := p.buckets.Front()
+	if front == nil || front.Value.(*lfuBucket[TKey]).freq != 0 {
+		front = p.buckets.PushFront(&lfuBucket[TKey]{freq: 0, keys: list.New()})
+	}
+
+	p.items[key] = &lfuItem[TKey]{
+		bucket:  front,
+		element: front.Value.(*lfuBucket[TKey]).keys.PushBack(key),
+	}
+}
+
+//OnGet moves the key into the bucket of the next frequency
+func (p *lfu[TKey]) OnGet(key TKey) {
+	item, exist := p.items[key]
+	if !exist {
+		return
+	}
+
+	current := item.bucket.Value.(*lfuBucket[TKey])
+
+	next := item.bucket.Next()
+	if next == nil || next.Value.(*lfuBucket[TKey]).freq != current.freq+1 {
+		next = p.buckets.InsertAfter(&lfuBucket[TKey]{freq: current.freq + 1, keys: list.New()}, item.bucket)
+	}
+
+	current.keys.Remove(item.element)
+	if current.keys.Len() < 1 {
+		p.buckets.Remove(item.bucket)
+	}
+
+	item.bucket = next
+	item.element = next.Value.(*lfuBucket[TKey]).keys.PushBack(key)
+}
+
+//OnRemove drops the key from its bucket, dropping the bucket too once it's empty
+func (p *lfu[TKey]) OnRemove(key TKey) {
+	item, exist := p.items[key]
+	if !exist {
+		return
+	}
+
+	b := item.bucket.Value.(*lfuBucket[TKey])
+	b.keys.Remove(item.element)
+
+	if b.keys.Len() < 1 {
+		p.buckets.Remove(item.bucket)
+	}
+
+	delete(p.items, key)
+}
+
+//Victim returns the oldest key of the least frequent bucket
+func (p *lfu[TKey]) Victim() (TKey, bool) {
+	if front := p.buckets.Front(); front != nil {
+		return front.Value.(*lfuBucket[TKey]).keys.Front().Value.(TKey), true
+	}
+
+	var nilKey TKey
+	return nilKey, false
+}
+
+//OnAdd appends the key to the slice of keys, recording the order in which it was added
+func (p *random[TKey]) OnAdd(key TKey) {
+	if _, exist := p.indexes[key]; exist {
+		return
+	}
+
+	p.seq++
+	p.indexes[key] = len(p.keys)
+	p.keys = append(p.keys, sampledKey[TKey]{key: key, seq: p.seq})
+	p.victim = nil
+}
+
+//OnGet does nothing, reads need no bookkeeping
+func (p *random[TKey]) OnGet(key TKey) {}
+
+//IgnoresReads reports that reads don't matter to the policy
+func (p *random[TKey]) IgnoresReads() bool { return true }
+
+//OnRemove drops the key from the slice of keys
+func (p *random[TKey]) OnRemove(key TKey) {
+	i, exist := p.indexes[key]
+	if !exist {
+		return
+	}
+
+	//Moving the last key into the place of the removed one keeps the slice dense
+	last := len(p.keys) - 1
+	p.keys[i] = p.keys[last]
+	p.indexes[p.keys[i].key] = i
+	p.keys = p.keys[:last]
+
+	delete(p.indexes, key)
+	p.victim = nil
+}
+
+//Victim returns the oldest of randomSamples keys picked at random. The same key is returned until the keys change
+func (p *random[TKey]) Victim() (TKey, bool) {
+	if len(p.keys) < 1 {
+		var nilKey TKey
+		return nilKey, false
+	}
+
+	if p.victim != nil {
+		return *p.victim, true
+	}
+
+	oldest := p.keys[p.rnd.Intn(len(p.keys))]
+
+	for i := 1; i < randomSamples; i++ {
+		if k := p.keys[p.rnd.Intn(len(p.keys))]; k.seq < oldest.seq {
+			oldest = k
+		}
+	}
+
+	p.victim = &oldest.key
+
+	return oldest.key, true
+}
+
+//OnAdd puts the key at the back of the probationary segment, unless it's already tracked
+func (p *slru[TKey]) OnAdd(key TKey) {
+	if _, exist := p.elements[key]; exist {
+		return
+	}
+
+	p.elements[key] = p.probation.PushBack(&slruItem[TKey]{key: key})
+}
+
+//OnGet moves the key to the back of the protected segment, demoting the oldest protected keys past its share
+func (p *slru[TKey]) OnGet(key TKey) {
+	el, exist := p.elements[key]
+	if !exist {
+		return
+	}
+
+	item := el.Value.(*slruItem[TKey])
+	if item.protected {
+		p.protected.MoveToBack(el)
+		return
+	}
+
+	p.probation.Remove(el)
+	item.protected = true
+	p.elements[key] = p.protected.PushBack(item)
+
+	limit := int(float64(len(p.elements)) * p.ratio)
+	if limit < 1 {
+		limit = 1
+	}
+
+	for p.protected.Len() > limit {
+		demoted := p.protected.Remove(p.protected.Front()).(*slruItem[TKey])
+		demoted.protected = false
+		p.elements[demoted.key] = p.probation.PushBack(demoted)
+	}
+}
+
+//OnRemove drops the key from its segment
+func (p *slru[TKey]) OnRemove(key TKey) {
+	el, exist := p.elements[key]
+	if !exist {
+		return
+	}
+
+	if el.Value.(*slruItem[TKey]).protected {
+		p.protected.Remove(el)
+	} else {
+		p.probation.Remove(el)
+	}
+
+	delete(p.elements, key)
+}
+
+//Victim returns the oldest probationary key, or the oldest protected key if there are no probationary ones
+func (p *slru[TKey]) Victim() (TKey, bool) {
+	if el := p.probation.Front(); el != nil {
+		return el.Value.(*slruItem[TKey]).key, true
+	}
+
+	if el := p.protected.Front(); el != nil {
+		return el.Value.(*slruItem[TKey]).key, true
+	}
+
+	var nilKey TKey
+	return nilKey, false
+}
+
+//OnAdd puts the key into a free slot of the ring, or at its end if there is none
+func (p *clock[TKey]) OnAdd(key TKey) {
+	if _, exist := p.indexes[key]; exist {
+		return
+	}
+
+	slot := clockSlot[TKey]{key: key, used: true}
+
+	if n := len(p.free); n > 0 {
+		i := p.free[n-1]
+		p.free = p.free[:n-1]
+		p.slots[i] = slot
+		p.indexes[key] = i
+		return
+	}
+
+	p.indexes[key] = len(p.slots)
+	p.slots = append(p.slots, slot)
+}
+
+//OnGet sets the reference flag of the key
+func (p *clock[TKey]) OnGet(key TKey) {
+	if i, exist := p.indexes[key]; exist {
+		p.slots[i].ref = true
+	}
+}
+
+//OnRemove frees the slot of the key
+func (p *clock[TKey]) OnRemove(key TKey) {
+	i, exist := p.indexes[key]
+	if !exist {
+		return
+	}
+
+	p.slots[i] = clockSlot[TKey]{}
+	p.free = append(p.free, i)
+	delete(p.indexes, key)
+}
+
+//Victim moves the hand until it reaches a key that hasn't been read since the hand last passed it, clearing the
+//flags of the keys passed. The hand stays at the victim, so it's returned again until it's removed
+func (p *clock[TKey]) Victim() (TKey, bool) {
+	if len(p.indexes) < 1 {
+		var nilKey TKey
+		return nilKey, false
+	}
+
+	for {
+		if p.hand >= len(p.slots) {
+			p.hand = 0
+		}
+
+		slot := &p.slots[p.hand]
+
+		if slot.used && !slot.ref {
+			return slot.key, true
+		}
+
+		slot.ref = false
+		p.hand++
+	}
+}
+
+//OnAdd puts the key into the main queue if it's remembered as a ghost, into the first in first out queue otherwise
+func (p *twoQueue[TKey]) OnAdd(key TKey) {
+	if _, exist := p.elements[key]; exist {
+		return
+	}
+
+	p.victim = nil
+
+	if ghost, exist := p.ghostByKeys[key]; exist {
+		p.ghosts.Remove(ghost)
+		delete(p.ghostByKeys, key)
+		p.elements[key] = p.main.PushBack(&twoQueueItem[TKey]{key: key, main: true})
+		return
+	}
+
+	p.elements[key] = p.in.PushBack(&twoQueueItem[TKey]{key: key})
+}
+
+//OnGet moves the key to the back of the main queue. Keys in the first in first out queue stay where they are
+func (p *twoQueue[TKey]) OnGet(key TKey) {
+	el, exist := p.elements[key]
+	if !exist || !el.Value.(*twoQueueItem[TKey]).main {
+		return
+	}
+
+	p.main.MoveToBack(el)
+	p.victim = nil
+}
+
+//OnRemove drops the key from its queue, remembering it as a ghost if it was evicted from the first in first out
+//queue
+func (p *twoQueue[TKey]) OnRemove(key TKey) {
+	el, exist := p.elements[key]
+	if !exist {
+		return
+	}
+
+	evicted := p.victim != nil && *p.victim == key
+	p.victim = nil
+	delete(p.elements, key)
+
+	if el.Value.(*twoQueueItem[TKey]).main {
+		p.main.Remove(el)
+		return
+	}
+
+	p.in.Remove(el)
+
+	if !evicted {
+		return
+	}
+
+	p.ghostByKeys[key] = p.ghosts.PushBack(key)
+
+	for limit := int(float64(len(p.elements)) * twoQueueGhostRatio); p.ghosts.Len() > limit && p.ghosts.Len() > 1; {
+		delete(p.ghostByKeys, p.ghosts.Remove(p.ghosts.Front()).(TKey))
+	}
+}
+
+//Victim returns the oldest key of the first in first out queue once it holds more than its share of keys or the
+//main queue is empty, otherwise the oldest key of the main queue
+func (p *twoQueue[TKey]) Victim() (TKey, bool) {
+	if p.victim != nil {
+		return *p.victim, true
+	}
+
+	el := p.main.Front()
+	if limit := int(float64(len(p.elements)) * twoQueueInRatio); el == nil || p.in.Len() > limit {
+		el = p.in.Front()
+	}
+
+	if el == nil {
+		var nilKey TKey
+		return nilKey, false
+	}
+
+	key := el.Value.(*twoQueueItem[TKey]).key
+	p.victim = &key
+
+	return key, true
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//NewFIFO creates built-in first in first out eviction policy
+func NewFIFO[TKey Key]() EvictionPolicy[TKey] {
+	return &fifo[TKey]{order: list.New(), elements: make(map[TKey]*list.Element)}
+}
+
+//NewLRU creates built-in least recently used eviction policy
+func NewLRU[TKey Key]() EvictionPolicy[TKey] {
+	return &fifo[TKey]{order: list.New(), elements: make(map[TKey]*list.Element), recency: true}
+}
+
+//NewLFU creates built-in least frequently used eviction policy
+func NewLFU[TKey Key]() EvictionPolicy[TKey] {
+	return &lfu[TKey]{buckets: list.New(), items: make(map[TKey]*lfuItem[TKey])}
+}
+
+//NewRandom creates built-in random eviction policy
+func NewRandom[TKey Key]() EvictionPolicy[TKey] {
+	return &random[TKey]{indexes: make(map[TKey]int), rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+//NewSLRU creates built-in segmented least recently used eviction policy keeping at most the share of the keys
+//specified in the protected segment. Ratio outside of (0, 1] defaults to 0.8
+func NewSLRU[TKey Key](protectedRatio float64) EvictionPolicy[TKey] {
+	if protectedRatio <= 0 || protectedRatio > 1 {
+		protectedRatio = defaultProtectedRatio
+	}
+
+	return &slru[TKey]{probation: list.New(), protected: list.New(), elements: make(map[TKey]*list.Element), ratio: protectedRatio}
+}
+
+//NewClock creates built-in clock eviction policy
+func NewClock[TKey Key]() EvictionPolicy[TKey] {
+	return &clock[TKey]{indexes: make(map[TKey]int)}
+}
+
+//NewTwoQueue creates built-in 2Q eviction policy
+func NewTwoQueue[TKey Key]() EvictionPolicy[TKey] {
+	return &twoQueue[TKey]{
+		in:          list.New(),
+		main:        list.New(),
+		elements:    make(map[TKey]*list.Element),
+		ghosts:      list.New(),
+		ghostByKeys: make(map[TKey]*list.Element),
+	}
+}
+
+//builtinPolicy returns constructor of the built-in eviction policy selected by the requirements
+func builtinPolicy[TKey Key](r *Requirements) func() EvictionPolicy[TKey] {
+	switch r.Policy {
+	case SLRU:
+		ratio := r.ProtectedRatio
+		return func() EvictionPolicy[TKey] { return NewSLRU[TKey](ratio) }
+	case LRU:
+		return NewLRU[TKey]
+	case LFU:
+		return NewLFU[TKey]
+	case Random:
+		return NewRandom[TKey]
+	case Clock:
+		return NewClock[TKey]
+	case TwoQueue:
+		return NewTwoQueue[TKey]
+	}
+
+	return NewFIFO[TKey]
+}
@@ -0,0 +1,326 @@
+package cacheMachine
+
+import (
+	"testing"
+)
+
+//===========[STRUCTS]====================================================================================================
+
+//Eviction policy counting reads it's notified about, optionally declaring that it ignores them
+type readCounter struct {
+	EvictionPolicy[int]
+	ignore bool
+	reads  int
+}
+
+func (p *readCounter) OnGet(key int)      { p.reads++ }
+func (p *readCounter) IgnoresReads() bool { return p.ignore }
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//policyOrder drains the policy and returns keys in the order they would have been evicted
+func policyOrder(p EvictionPolicy[int]) []int {
+	var order []int
+
+	for {
+		key, ok := p.Victim()
+		if !ok {
+			return order
+		}
+
+		p.OnRemove(key)
+		order = append(order, key)
+	}
+}
+
+//equalOrder checks whether both orders contain the same keys in the same order
+func equalOrder(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+//===========[TESTING]====================================================================================================
+
+func TestNewFIFO(t *testing.T) {
+	p := NewFIFO[int]()
+
+	for i := 1; i <= 4; i++ {
+		p.OnAdd(i)
+	}
+
+	p.OnGet(1)
+	p.OnRemove(3)
+
+	if order := policyOrder(p); !equalOrder(order, []int{1, 2, 4}) {
+		t.Errorf("Expected eviction order %v, got %v", []int{1, 2, 4}, order)
+	}
+}
+
+func TestNewLRU(t *testing.T) {
+	p := NewLRU[int]()
+
+	for i := 1; i <= 4; i++ {
+		p.OnAdd(i)
+	}
+
+	p.OnGet(1)
+	p.OnGet(3)
+	p.OnRemove(2)
+
+	if order := policyOrder(p); !equalOrder(order, []int{4, 1, 3}) {
+		t.Errorf("Expected eviction order %v, got %v", []int{4, 1, 3}, order)
+	}
+}
+
+func TestNewLFU(t *testing.T) {
+	p := NewLFU[int]()
+
+	for i := 1; i <= 4; i++ {
+		p.OnAdd(i)
+	}
+
+	p.OnGet(1)
+	p.OnGet(1)
+	p.OnGet(2)
+	p.OnGet(4)
+	p.OnRemove(4)
+	p.OnAdd(5)
+
+	if order := policyOrder(p); !equalOrder(order, []int{3, 5, 2, 1}) {
+		t.Errorf("Expected eviction order %v, got %v", []int{3, 5, 2, 1}, order)
+	}
+}
+
+func TestNewSLRU(t *testing.T) {
+	p := NewSLRU[int](0.5)
+
+	for i := 1; i <= 4; i++ {
+		p.OnAdd(i)
+	}
+
+	p.OnGet(1)
+	p.OnGet(2)
+	p.OnGet(3)
+
+	if order := policyOrder(p); !equalOrder(order, []int{4, 1, 2, 3}) {
+		t.Errorf("Expected eviction order %v, got %v", []int{4, 1, 2, 3}, order)
+	}
+}
+
+func TestNewClock(t *testing.T) {
+	p := NewClock[int]()
+
+	for i := 1; i <= 4; i++ {
+		p.OnAdd(i)
+	}
+
+	p.OnGet(1)
+	p.OnGet(3)
+	p.OnRemove(2)
+	p.OnAdd(5)
+
+	if order := policyOrder(p); !equalOrder(order, []int{5, 4, 1, 3}) {
+		t.Errorf("Expected eviction order %v, got %v", []int{5, 4, 1, 3}, order)
+	}
+}
+
+func TestNewTwoQueue(t *testing.T) {
+	p := NewTwoQueue[int]()
+
+	for i := 1; i <= 4; i++ {
+		p.OnAdd(i)
+	}
+
+	key, _ := p.Victim()
+	p.OnRemove(key)
+	p.OnAdd(key)
+
+	if order := policyOrder(p); !equalOrder(order, []int{2, 3, 4, 1}) {
+		t.Errorf("Expected eviction order %v, got %v", []int{2, 3, 4, 1}, order)
+	}
+}
+
+func TestRequirements_TwoQueue(t *testing.T) {
+	c := initializeFullCache(12, &Requirements{MaxEntries: 8, Policy: TwoQueue})
+
+	//Keys evicted recently and added again are kept in the main queue
+	for i := 0; i < 4; i++ {
+		c.Add(i, i)
+	}
+
+	for i := 100; i < 200; i++ {
+		c.Add(i, i)
+	}
+
+	for i := 0; i < 4; i++ {
+		if !c.Exist(i) {
+			t.Errorf("Expected key %d to survive the scan, but it did not", i)
+		}
+	}
+}
+
+func TestNewRandom(t *testing.T) {
+	p := NewRandom[int]()
+
+	for i := 1; i <= 1000; i++ {
+		p.OnAdd(i)
+	}
+
+	first, _ := p.Victim()
+	if again, _ := p.Victim(); again != first {
+		t.Errorf("Expected Victim to keep returning the same key until the keys change")
+	}
+
+	order := policyOrder(p)
+	seen := make(map[int]bool)
+	sum := 0
+
+	for i, key := range order {
+		seen[key] = true
+
+		if i < 100 {
+			sum += key
+		}
+	}
+
+	if len(order) != 1000 || len(seen) != 1000 {
+		t.Errorf("Expected every key to be evicted exactly once, got %d evictions of %d keys", len(order), len(seen))
+	}
+
+	//The oldest of the samples is chosen, so the first victims are mostly the keys added early
+	if sum/100 > 400 {
+		t.Errorf("Expected the first victims to be skewed towards old keys, got average key %d", sum/100)
+	}
+}
+
+func TestCache_untracked(t *testing.T) {
+	c := initializeFullCache(5, &Requirements{Policy: LRU})
+	c.Get(0)
+
+	if len(c.levels) > 0 {
+		t.Fatalf("Expected keys of a cache without limits not to be handed over to the eviction policy")
+	}
+
+	//The policy learns about the keys in the order they were added, reads before that don't count
+	if n := c.EvictN(1); n != 1 || c.Exist(0) {
+		t.Errorf("Expected key %d added first to be evicted, got %v", 0, c.GetAll())
+	}
+
+	c.Get(1)
+	c.EvictN(1)
+
+	if !c.Exist(1) || c.Exist(2) {
+		t.Errorf("Expected reads to count once the keys are tracked, got %v", c.GetAll())
+	}
+}
+
+func TestRequirements_ReadIgnoringPolicy(t *testing.T) {
+	for _, ignore := range []bool{true, false} {
+		p := &readCounter{EvictionPolicy: NewFIFO[int](), ignore: ignore}
+
+		c := New[int, int](&Requirements{MaxEntries: 10})
+		c.SetEvictionPolicy(func() EvictionPolicy[int] { return p })
+		c.Add(1, 1)
+		c.Get(1)
+		c.GetBulk([]int{1})
+
+		if want := map[bool]int{true: 0, false: 2}[ignore]; p.reads != want {
+			t.Errorf("Expected policy ignoring reads (%t) to be notified about %d reads, got %d", ignore, want, p.reads)
+		}
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkNewLRU(b *testing.B) {
+	p := NewLRU[int]()
+
+	for n := 0; n < b.N; n++ {
+		p.OnAdd(n)
+		p.OnGet(n / 2)
+
+		if n > 1000 {
+			key, _ := p.Victim()
+			p.OnRemove(key)
+		}
+	}
+}
+
+func BenchmarkNewLFU(b *testing.B) {
+	p := NewLFU[int]()
+
+	for n := 0; n < b.N; n++ {
+		p.OnAdd(n)
+		p.OnGet(n / 2)
+
+		if n > 1000 {
+			key, _ := p.Victim()
+			p.OnRemove(key)
+		}
+	}
+}
+
+func BenchmarkNewRandom(b *testing.B) {
+	p := NewRandom[int]()
+
+	for n := 0; n < b.N; n++ {
+		p.OnAdd(n)
+		p.OnGet(n / 2)
+
+		if n > 1000 {
+			key, _ := p.Victim()
+			p.OnRemove(key)
+		}
+	}
+}
+
+func BenchmarkNewSLRU(b *testing.B) {
+	p := NewSLRU[int](0)
+
+	for n := 0; n < b.N; n++ {
+		p.OnAdd(n)
+		p.OnGet(n / 2)
+
+		if n > 1000 {
+			key, _ := p.Victim()
+			p.OnRemove(key)
+		}
+	}
+}
+
+func BenchmarkNewClock(b *testing.B) {
+	p := NewClock[int]()
+
+	for n := 0; n < b.N; n++ {
+		p.OnAdd(n)
+		p.OnGet(n / 2)
+
+		if n > 1000 {
+			key, _ := p.Victim()
+			p.OnRemove(key)
+		}
+	}
+}
+
+func BenchmarkNewTwoQueue(b *testing.B) {
+	p := NewTwoQueue[int]()
+
+	for n := 0; n < b.N; n++ {
+		p.OnAdd(n)
+		p.OnGet(n / 2)
+
+		if n > 1000 {
+			key, _ := p.Victim()
+			p.OnRemove(key)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package cacheMachine
+
+import (
+	"strconv"
+	"time"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//Freshness tells whether the value read from the cache can be used as it is
+type Freshness int
+
+const (
+	//Missing means the key is not present in the cache, or its hard timeout has passed
+	Missing Freshness = iota
+
+	//Fresh means the soft timeout of the entry hasn't passed yet, or the entry has no soft timeout
+	Fresh
+
+	//Stale means the soft timeout of the entry has passed. The value can still be served, but should be refreshed
+	Stale
+)
+
+//------PUBLIC------
+
+//String returns the name of the freshness
+func (f Freshness) String() string {
+	switch f {
+	case Missing:
+		return "missing"
+	case Fresh:
+		return "fresh"
+	case Stale:
+		return "stale"
+	}
+
+	return "Freshness(" + strconv.Itoa(int(f)) + ")"
+}
+
+//AddWithTTLs does the same as method "Add" but sets two timeouts for the entry: once the soft timeout passes, the
+//entry is reported as Stale by GetWithFreshness, and once the hard timeout passes, the entry is removed the same way
+//as with AddWithTimeout. Soft timeout of 0 means the entry never goes stale and hard timeout of 0 means the entry is
+//never removed, unless DefaultTimeout is set
+func (c *Cache[TKey, TValue]) AddWithTTLs(key TKey, val TValue, soft, hard time.Duration) Entry[TValue] {
+	c.mx.Lock()
+	e := c.newEntry(key, val, hard, PriorityNormal)
+	if soft > 0 {
+		e.staleAt = c.now().Add(soft)
+	}
+	c.insert(key, e)
+	c.mx.Unlock()
+
+	c.writeThrough(key, val)
+
+	return e
+}
+
+//GetWithFreshness does the same as method "Get" but also reports whether the soft timeout of the entry has passed
+func (c *Cache[TKey, TValue]) GetWithFreshness(key TKey) (TValue, Freshness) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	e := c.getEntry(key)
+	if e == nil {
+		var nilVal TValue
+		return nilVal, Missing
+	}
+
+	if staleAt := e.(*entry[TValue]).staleAt; !staleAt.IsZero() && !c.now().Before(staleAt) {
+		return e.Value(), Stale
+	}
+
+	return e.Value(), Fresh
+}
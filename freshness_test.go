@@ -0,0 +1,48 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_AddWithTTLs(t *testing.T) {
+	c := New[int, int](nil)
+	c.AddWithTTLs(1, 1, time.Millisecond*20, time.Millisecond*60)
+	c.Add(2, 2)
+
+	if v, f := c.GetWithFreshness(1); v != 1 || f != Fresh {
+		t.Errorf("Expected to get fresh value %d, got %d that is %s", 1, v, f)
+	}
+
+	time.Sleep(time.Millisecond * 30)
+
+	if v, f := c.GetWithFreshness(1); v != 1 || f != Stale {
+		t.Errorf("Expected to get stale value %d, got %d that is %s", 1, v, f)
+	}
+
+	if _, f := c.GetWithFreshness(2); f != Fresh {
+		t.Errorf("Expected entry without soft timeout to be fresh, got %s", f)
+	}
+
+	time.Sleep(time.Millisecond * 60)
+
+	if _, f := c.GetWithFreshness(1); f != Missing {
+		t.Errorf("Expected entry to be removed once the hard timeout passed, got %s", f)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_GetWithFreshness(b *testing.B) {
+	c := New[int, int](nil)
+
+	for i := 0; i < 100; i++ {
+		c.AddWithTTLs(i, i, time.Minute, time.Hour)
+	}
+
+	for n := 0; n < b.N; n++ {
+		c.GetWithFreshness(n % 100)
+	}
+}
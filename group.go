@@ -0,0 +1,127 @@
+package cacheMachine
+
+import (
+	"time"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//Keys of the entries added together by AddGroup
+type group[TKey Key] map[TKey]struct{}
+
+//GroupOptions are the settings shared by all the entries of the group added by AddGroup
+type GroupOptions struct {
+	//Timeout of the group. Once it passes, all the entries are removed together. 0 means DefaultTimeout applies
+	Timeout time.Duration
+
+	Priority Priority
+}
+
+//------PRIVATE------
+
+//ungroup removes the key from the group it belongs to, if any, leaving the rest of the group as it is. This method
+//has no mutex protection
+func (c *Cache[TKey, TValue]) ungroup(key TKey) {
+	if g, exist := c.groups[key]; exist {
+		delete(g, key)
+		delete(c.groups, key)
+	}
+}
+
+//removeGroup removes the rest of the group of the key that has just been removed, emitting the same kind of event for
+//every entry. This method has no mutex protection
+func (c *Cache[TKey, TValue]) removeGroup(kind EventKind, key TKey) {
+	g, exist := c.groups[key]
+	if !exist {
+		return
+	}
+
+	//The group is dissolved first, so that removals of its members don't try to remove it again
+	for member := range g {
+		delete(c.groups, member)
+	}
+
+	for member := range g {
+		if e, exist := c.data[member]; exist {
+			c.remove(member)
+			c.emit(kind, member, e)
+		}
+	}
+}
+
+//------PUBLIC------
+
+//AddGroup adds the entries as a group, e.g. objects referencing each other, so that readers see either all of them
+//or none. The entries are added at once and whenever one of them is removed, expired or evicted, the rest of the
+//group is removed along with it, pinned entries included. Replacing the value of an entry takes it out of the group.
+//Returns false if the group doesn't fit into the cache as a whole, e.g. because MaxEntries is smaller than the
+//group or the admission filter refused some of its keys, in which case none of the entries are kept and the keys
+//that were present before get back their previous values along with their timeouts, priorities and pins
+func (c *Cache[TKey, TValue]) AddGroup(entries map[TKey]TValue, opts GroupOptions) bool {
+	c.mx.Lock()
+
+	//Values the keys held before, restored if the group doesn't fit
+	prev := make(map[TKey]timedValue[TValue])
+	for key := range entries {
+		if e, exist := c.lookup(key); exist {
+			prev[key] = e.timedValue()
+		}
+	}
+
+	for key, val := range entries {
+		c.add(key, val, opts.Timeout, opts.Priority)
+	}
+
+	//Adding later entries may have evicted the earlier ones
+	complete := true
+	for key := range entries {
+		if _, exist := c.data[key]; !exist {
+			complete = false
+			break
+		}
+	}
+
+	if !complete {
+		for key := range entries {
+			if _, existed := prev[key]; existed {
+				continue
+			}
+
+			if e, exist := c.data[key]; exist {
+				c.remove(key)
+				c.emit(EventEvict, key, e)
+			}
+		}
+
+		//Previous values are restored once the new keys are gone, so that they don't evict each other
+		for key, v := range prev {
+			if !c.insertTimed(key, v) {
+				if e, exist := c.data[key]; exist {
+					c.remove(key)
+					c.emit(EventEvict, key, e)
+				}
+			}
+		}
+
+		c.mx.Unlock()
+		return false
+	}
+
+	if c.groups == nil {
+		c.groups = make(map[TKey]group[TKey])
+	}
+
+	g := make(group[TKey], len(entries))
+	for key := range entries {
+		g[key] = struct{}{}
+		c.groups[key] = g
+	}
+
+	c.mx.Unlock()
+
+	for key, val := range entries {
+		c.writeThrough(key, val)
+	}
+
+	return true
+}
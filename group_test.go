@@ -0,0 +1,103 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_AddGroup(t *testing.T) {
+	c := New[int, int](nil)
+
+	if !c.AddGroup(map[int]int{1: 1, 2: 2, 3: 3}, GroupOptions{}) {
+		t.Fatalf("Expected the group to be added")
+	}
+
+	c.Add(4, 4)
+	c.Remove(2)
+
+	if c.Count() != 1 || !c.Exist(4) {
+		t.Errorf("Expected removal of a member to remove the whole group, got %v", c.GetAll())
+	}
+
+	c.AddGroup(map[int]int{1: 1, 2: 2}, GroupOptions{})
+	c.Add(1, 10)
+	c.Remove(2)
+
+	if !c.Exist(1) {
+		t.Errorf("Expected the replaced entry to leave the group")
+	}
+}
+
+func TestCache_AddGroup_expiry(t *testing.T) {
+	c := New[int, int](nil)
+
+	var events []change[int]
+	c.Subscribe(func(e Event[int]) {
+		if e.Kind != EventAdd {
+			events = append(events, change[int]{e.Kind, e.Key})
+		}
+	})
+
+	c.AddGroup(map[int]int{1: 1, 2: 2}, GroupOptions{Timeout: time.Millisecond * 10})
+	time.Sleep(time.Millisecond * 50)
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if len(c.data) != 0 || len(events) != 2 || events[0].kind != EventExpire || events[1].kind != EventExpire {
+		t.Errorf("Expected the whole group to expire, got %d entries left and events %v", len(c.data), events)
+	}
+
+	if len(c.groups) != 0 {
+		t.Errorf("Expected no groups left, got %d", len(c.groups))
+	}
+}
+
+func TestCache_AddGroup_tooBig(t *testing.T) {
+	c := initializeFullCache(2, &Requirements{MaxEntries: 2})
+
+	if c.AddGroup(map[int]int{10: 10, 11: 11, 12: 12}, GroupOptions{}) {
+		t.Errorf("Expected the group bigger than MaxEntries to be refused")
+	}
+
+	for key := 10; key < 13; key++ {
+		if c.Exist(key) {
+			t.Errorf("Expected no entries of the refused group to be kept, found %d", key)
+		}
+	}
+}
+
+func TestCache_AddGroup_rollback(t *testing.T) {
+	c := New[int, int](&Requirements{MaxEntries: 2})
+
+	c.AddWithTimeout(1, 1, time.Hour)
+	c.Add(2, 2)
+
+	if c.AddGroup(map[int]int{1: 10, 3: 30, 4: 40}, GroupOptions{}) {
+		t.Fatalf("Expected the group bigger than MaxEntries to be refused")
+	}
+
+	if v, ok := c.Get(1); !ok || v != 1 {
+		t.Fatalf("Expected the key present before to get its previous value back, got %d and %t", v, ok)
+	}
+
+	if ttl := c.GetEntry(1).TTL(); ttl < time.Minute*59 {
+		t.Errorf("Expected the previous value to keep its timeout, got %s", ttl)
+	}
+
+	if c.Exist(3) || c.Exist(4) {
+		t.Errorf("Expected no new entries of the refused group to be kept, got %v", c.GetAll())
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_AddGroup(b *testing.B) {
+	c := New[int, int](nil)
+
+	for n := 0; n < b.N; n++ {
+		c.AddGroup(map[int]int{n % 100: n, n%100 + 100: n}, GroupOptions{})
+	}
+}
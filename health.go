@@ -0,0 +1,105 @@
+package cacheMachine
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//ErrInconsistentState is reported by Healthy when the bookkeeping of the cache doesn't match its entries
+var ErrInconsistentState = errors.New("cacheMachine: internal state is inconsistent")
+
+//ErrWriteBacklog is reported by Healthy when more than Requirements.MaxPendingWrites writes are pending
+var ErrWriteBacklog = errors.New("cacheMachine: too many writes pending")
+
+//===========[STRUCTS]==================================================================================================
+
+//HealthError is returned by Healthy and names the check that failed
+type HealthError struct {
+	//Check that failed: "state", "loads", "writes" or "backend"
+	Check string
+	Err   error
+}
+
+//------PRIVATE------
+
+//checkState verifies that eviction levels, time buckets and owners account for the entries present. This method
+//has no mutex protection
+func (c *Cache[TKey, TValue]) checkState() error {
+	linked := c.pinned
+	for _, l := range c.levels {
+		linked += l.size
+	}
+	for _, l := range c.drainLevels {
+		linked += l.size
+	}
+
+	if !c.untracked && linked != len(c.data) || c.cost < 0 {
+		return ErrInconsistentState
+	}
+
+	for _, b := range c.buckets {
+		if len(b.keys) < 1 {
+			return ErrInconsistentState
+		}
+	}
+
+	for _, keys := range c.owners {
+		if len(keys) < 1 {
+			return ErrInconsistentState
+		}
+	}
+
+	return nil
+}
+
+//backlog returns the number of writes pending
+func (w *coalescer[TKey, TValue]) backlog() int {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+	return len(w.pending)
+}
+
+//------PUBLIC------
+
+//Error describes the check that failed along with its cause
+func (e *HealthError) Error() string {
+	return "cacheMachine: " + e.Check + " check failed: " + e.Err.Error()
+}
+
+//Unwrap returns the cause of the failure, so that errors.Is can match it
+func (e *HealthError) Unwrap() error {
+	return e.Err
+}
+
+//Healthy returns nil if the cache is fit to serve, e.g. for readiness probes. Otherwise it returns HealthError of
+//the first check failed: consistency of the internal state, whether the queue of loads waiting for
+//MaxConcurrentLoads is full, whether more than MaxPendingWrites writes are pending and Requirements.HealthCheck.
+//Expiry doesn't depend on a background goroutine, so there's nothing else that could stop running
+func (c *Cache[TKey, TValue]) Healthy() error {
+	c.mx.RLock()
+	err := c.checkState()
+	r := c.cache.Requirements
+	c.mx.RUnlock()
+
+	if err != nil {
+		return &HealthError{Check: "state", Err: err}
+	}
+
+	if c.loads != nil && c.loads.queue > 0 && atomic.LoadInt32(&c.loads.waiting) >= c.loads.queue {
+		return &HealthError{Check: "loads", Err: ErrLoadQueueFull}
+	}
+
+	if w, _ := c.writer.Load().(*coalescer[TKey, TValue]); w != nil && r.MaxPendingWrites > 0 && w.backlog() > r.MaxPendingWrites {
+		return &HealthError{Check: "writes", Err: ErrWriteBacklog}
+	}
+
+	if r.HealthCheck != nil {
+		if err := r.HealthCheck(); err != nil {
+			return &HealthError{Check: "backend", Err: err}
+		}
+	}
+
+	return nil
+}
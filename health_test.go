@@ -0,0 +1,56 @@
+package cacheMachine
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_Healthy(t *testing.T) {
+	backendErr := errors.New("backend is down")
+	var backend error
+
+	c := initializeFullCache(10, &Requirements{MaxEntries: 20, MaxPendingWrites: 1, HealthCheck: func() error { return backend }})
+
+	if err := c.Healthy(); err != nil {
+		t.Errorf("Expected the cache to be healthy, got %v", err)
+	}
+
+	c.SetWriteThrough(func(int, int) {}, time.Hour)
+	c.Add(100, 100)
+	c.Add(101, 101)
+
+	var healthErr *HealthError
+	if err := c.Healthy(); !errors.As(err, &healthErr) || healthErr.Check != "writes" || !errors.Is(err, ErrWriteBacklog) {
+		t.Errorf("Expected to get ErrWriteBacklog, got %v", err)
+	}
+
+	c.FlushWrites()
+	backend = backendErr
+
+	if err := c.Healthy(); !errors.Is(err, backendErr) {
+		t.Errorf("Expected to get the error of the backend, got %v", err)
+	}
+
+	backend = nil
+
+	c.mx.Lock()
+	delete(c.data, 5)
+	c.mx.Unlock()
+
+	if err := c.Healthy(); !errors.Is(err, ErrInconsistentState) {
+		t.Errorf("Expected to get ErrInconsistentState, got %v", err)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_Healthy(b *testing.B) {
+	c := initializeFullCache(100, nil)
+
+	for n := 0; n < b.N; n++ {
+		c.Healthy()
+	}
+}
@@ -0,0 +1,138 @@
+package cacheMachine
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//EntryInfo is the metadata of a single entry recorded by History. Values are not recorded
+type EntryInfo[TKey Key] struct {
+	Key      TKey
+	Created  time.Time
+	LastRead time.Time
+
+	//Wall clock time at which the entry expires. Zero if it never does
+	Deadline time.Time
+
+	Hits     uint32
+	Priority Priority
+	Cost     int64
+	Pinned   bool
+}
+
+//DebugSnapshot is the list of the entries the cache held at the time it was taken
+type DebugSnapshot[TKey Key] struct {
+	Taken   time.Time
+	Entries []EntryInfo[TKey]
+}
+
+//History keeps the last snapshots of the keys and metadata of the cache taken on an interval, e.g. to find out what
+//was in the cache when an incident started
+type History[TKey Key] struct {
+	//Ring of the snapshots, next is the index the next snapshot is stored at
+	ring  []DebugSnapshot[TKey]
+	next  int
+	count int
+	mx    sync.Mutex
+
+	done chan struct{}
+	once sync.Once
+}
+
+//------PRIVATE------
+
+//push stores the snapshot in place of the oldest one once the ring is full
+func (h *History[TKey]) push(s DebugSnapshot[TKey]) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	h.ring[h.next] = s
+	h.next = (h.next + 1) % len(h.ring)
+
+	if h.count < len(h.ring) {
+		h.count++
+	}
+}
+
+//------PUBLIC------
+
+//Snapshots returns the snapshots retained, oldest first
+func (h *History[TKey]) Snapshots() []DebugSnapshot[TKey] {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	results := make([]DebugSnapshot[TKey], 0, h.count)
+	for i := 0; i < h.count; i++ {
+		results = append(results, h.ring[(h.next-h.count+i+len(h.ring))%len(h.ring)])
+	}
+
+	return results
+}
+
+//Stop stops taking snapshots. Snapshots already taken are kept
+func (h *History[TKey]) Stop() {
+	h.once.Do(func() { close(h.done) })
+}
+
+//------PRIVATE------
+
+//debugSnapshot lists the metadata of all the entries
+func (c *Cache[TKey, TValue]) debugSnapshot() DebugSnapshot[TKey] {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	s := DebugSnapshot[TKey]{Taken: time.Now(), Entries: make([]EntryInfo[TKey], 0, len(c.data))}
+
+	for key, e := range c.data {
+		e.mx.RLock()
+		deadline := e.deadline
+		e.mx.RUnlock()
+
+		s.Entries = append(s.Entries, EntryInfo[TKey]{
+			Key:      key,
+			Created:  e.created,
+			LastRead: time.Unix(0, atomic.LoadInt64(&e.lastRead)),
+			Deadline: deadline,
+			Hits:     atomic.LoadUint32(&e.reads),
+			Priority: e.priority,
+			Cost:     e.cost,
+			Pinned:   e.pinned,
+		})
+	}
+
+	return s
+}
+
+//------PUBLIC------
+
+//RecordHistory starts a goroutine taking a snapshot of the keys and metadata of all the entries every interval,
+//keeping the last size of them. Every snapshot holds the read lock for time proportional to the size of the cache,
+//so the interval should not be too short for big caches. Call History.Stop to stop taking snapshots
+func (c *Cache[TKey, TValue]) RecordHistory(size int, interval time.Duration) *History[TKey] {
+	if size < 1 {
+		size = 1
+	}
+
+	h := &History[TKey]{ring: make([]DebugSnapshot[TKey], size), done: make(chan struct{})}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-h.done:
+				return
+			case <-c.closed:
+				return
+			case <-ticker.C:
+				h.push(c.debugSnapshot())
+			}
+		}
+	}()
+
+	return h
+}
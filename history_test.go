@@ -0,0 +1,59 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_RecordHistory(t *testing.T) {
+	c := New[int, int](nil)
+	c.Add(1, 1)
+
+	h := c.RecordHistory(2, time.Millisecond*10)
+
+	time.Sleep(time.Millisecond * 15)
+	c.Add(2, 2)
+	c.Get(2)
+	time.Sleep(time.Millisecond * 30)
+	h.Stop()
+	h.Stop()
+
+	s := h.Snapshots()
+
+	if len(s) != 2 {
+		t.Fatalf("Expected 2 snapshots to be retained, got %d", len(s))
+	}
+
+	if !s[0].Taken.Before(s[1].Taken) {
+		t.Errorf("Expected snapshots to be ordered oldest first")
+	}
+
+	last := s[1].Entries
+	if len(last) != 2 {
+		t.Fatalf("Expected the last snapshot to hold 2 entries, got %d", len(last))
+	}
+
+	for _, e := range last {
+		if e.Key == 2 && e.Hits != 1 {
+			t.Errorf("Expected key 2 to have 1 hit, got %d", e.Hits)
+		}
+	}
+
+	time.Sleep(time.Millisecond * 20)
+
+	if n := len(h.Snapshots()); n != 2 || h.Snapshots()[1].Taken != s[1].Taken {
+		t.Errorf("Expected no snapshots to be taken after stopping")
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_RecordHistory(b *testing.B) {
+	c := initializeFullCache(1000, nil)
+
+	for n := 0; n < b.N; n++ {
+		c.debugSnapshot()
+	}
+}
@@ -0,0 +1,46 @@
+package cacheMachine
+
+//===========[STRUCTS]==================================================================================================
+
+//Hooks are called at internal points of the cache, so that tests can deterministically reproduce timing dependent
+//behavior, e.g. block an expiring entry until the test replaces it. They can only be set using SetHooks, which is
+//available when built with the cachemachine_hooks build tag
+type Hooks[TKey Key] struct {
+	//Called when the timer of an entry fires, before the cache is locked to remove the entry
+	BeforeExpire func(key TKey)
+
+	//Called right after the key has been evicted, while the cache is still locked. It must not use the cache
+	AfterEvict func(key TKey)
+
+	//Called by GetOrLoad right before the loader is called
+	BeforeLoad func(key TKey)
+}
+
+//------PRIVATE------
+
+//beforeExpire calls the BeforeExpire hook, if it's set
+func (c *Cache[TKey, TValue]) beforeExpire(key TKey) {
+	if h := c.loadHooks(); h != nil && h.BeforeExpire != nil {
+		h.BeforeExpire(key)
+	}
+}
+
+//afterEvict calls the AfterEvict hook, if it's set
+func (c *Cache[TKey, TValue]) afterEvict(key TKey) {
+	if h := c.loadHooks(); h != nil && h.AfterEvict != nil {
+		h.AfterEvict(key)
+	}
+}
+
+//beforeLoad calls the BeforeLoad hook, if it's set
+func (c *Cache[TKey, TValue]) beforeLoad(key TKey) {
+	if h := c.loadHooks(); h != nil && h.BeforeLoad != nil {
+		h.BeforeLoad(key)
+	}
+}
+
+//loadHooks returns the hooks set, or nil
+func (c *Cache[TKey, TValue]) loadHooks() *Hooks[TKey] {
+	h, _ := c.hooks.Load().(*Hooks[TKey])
+	return h
+}
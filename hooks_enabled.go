@@ -0,0 +1,11 @@
+//go:build cachemachine_hooks
+
+package cacheMachine
+
+//------PUBLIC------
+
+//SetHooks sets the hooks called at internal points of the cache. Nil removes all the hooks. This method is meant for
+//tests only and is available when built with the cachemachine_hooks build tag
+func (c *Cache[TKey, TValue]) SetHooks(h *Hooks[TKey]) {
+	c.hooks.Store(h)
+}
@@ -0,0 +1,46 @@
+//go:build cachemachine_hooks
+
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_SetHooks(t *testing.T) {
+	c := initializeFullCache(0, &Requirements{MaxEntries: 2})
+
+	expiring := make(chan int)
+	release := make(chan struct{})
+	var evicted []int
+
+	c.SetHooks(&Hooks[int]{
+		BeforeExpire: func(key int) {
+			expiring <- key
+			<-release
+		},
+		AfterEvict: func(key int) { evicted = append(evicted, key) },
+	})
+
+	c.AddWithTimeout(1, 1, time.Millisecond)
+
+	//The timer of the entry has fired, but the entry is replaced before the expiry gets to remove it
+	<-expiring
+	c.Add(1, 2)
+	close(release)
+
+	c.Add(2, 2)
+	c.Add(3, 3)
+
+	c.SetHooks(nil)
+
+	if v, ok := c.Get(1); ok || v != 0 {
+		t.Errorf("Expected key 1 to be evicted as the oldest key, got %d and %t", v, ok)
+	}
+
+	if len(evicted) != 1 || evicted[0] != 1 {
+		t.Errorf("Expected AfterEvict to be called for key 1, got %v", evicted)
+	}
+}
@@ -0,0 +1,52 @@
+package cacheMachine
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+//------PRIVATE------
+
+//stopIdle stops the idle timer of the entry, if there is one. This method has no mutex protection
+func (e *entry[TValue]) stopIdle() {
+	if e.idle != nil {
+		e.idle.Stop()
+	}
+}
+
+//startIdle starts the timer removing the entry once it hasn't been read for MaxIdleTime, if it's set. This method
+//has no mutex protection
+func (c *Cache[TKey, TValue]) startIdle(key TKey, e *entry[TValue]) {
+	maxIdle := c.cache.Requirements.MaxIdleTime
+	if maxIdle <= 0 {
+		return
+	}
+
+	e.idle = c.cache.Requirements.TimeSource.AfterFunc(maxIdle, func() { c.expireIdle(key, e) })
+}
+
+//expireIdle removes the entry once its idle timer fires, if it hasn't been read since. Otherwise the timer is
+//restarted for the rest of the idle time counting from the last read. Reads don't touch the timer themselves,
+//so they stay cheap
+func (c *Cache[TKey, TValue]) expireIdle(key TKey, e *entry[TValue]) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if current, exist := c.data[key]; !exist || current != e {
+		return
+	}
+
+	idle := c.now().Sub(time.Unix(0, atomic.LoadInt64(&e.lastRead)))
+
+	if remaining := c.cache.Requirements.MaxIdleTime - idle; remaining > 0 || e.pinned {
+		if remaining <= 0 {
+			remaining = c.cache.Requirements.MaxIdleTime
+		}
+
+		e.idle.Reset(remaining)
+		return
+	}
+
+	c.remove(key)
+	c.emit(EventExpire, key, e)
+}
@@ -0,0 +1,44 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestRequirements_MaxIdleTime(t *testing.T) {
+	c := New[int, int](&Requirements{MaxIdleTime: time.Millisecond * 50, DefaultTimeout: time.Hour})
+
+	c.Add(1, 1)
+	c.Add(2, 2)
+
+	for i := 0; i < 5; i++ {
+		time.Sleep(time.Millisecond * 20)
+		c.Get(1)
+	}
+
+	if !c.Exist(1) {
+		t.Errorf("Expected entry read regularly to stay in the cache")
+	}
+
+	if c.Exist(2) {
+		t.Errorf("Expected idle entry to be removed before its timeout")
+	}
+
+	time.Sleep(time.Millisecond * 100)
+
+	if c.Exist(1) {
+		t.Errorf("Expected entry to be removed once it became idle")
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkRequirements_MaxIdleTime(b *testing.B) {
+	c := initializeFullCache(100, &Requirements{MaxIdleTime: time.Minute})
+
+	for n := 0; n < b.N; n++ {
+		c.Get(n % 100)
+	}
+}
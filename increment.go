@@ -0,0 +1,42 @@
+package cacheMachine
+
+import (
+	"io"
+)
+
+//------PUBLIC------
+
+//SaveIncremental writes a snapshot of the entries added or replaced since the snapshot written at the version
+//supplied, along with records of the keys removed since, and returns the version to supply to the next call. Loading
+//the full snapshot followed by all of its increments in order restores the cache. Version 0 writes all the entries,
+//which starts recording removals, so the chain of increments must start with it. Records of removals are kept until
+//a later increment is written, so increments must be saved regularly once started
+func (c *Cache[TKey, TValue]) SaveIncremental(w io.Writer, since uint64, opts SaveOptions) (uint64, error) {
+	c.mx.Lock()
+
+	if c.tombstones == nil {
+		c.tombstones = make(map[TKey]uint64)
+	}
+
+	version := c.version
+	var records []snapshotRecord[TKey, TValue]
+
+	for key, e := range c.data {
+		if e.version > since && c.live(e) {
+			records = append(records, snapshotRecord[TKey, TValue]{Key: key, Value: e.Val, Deadline: e.ExpiresAt()})
+		}
+	}
+
+	for key, v := range c.tombstones {
+		if v <= since {
+			delete(c.tombstones, key)
+			continue
+		}
+
+		records = append(records, snapshotRecord[TKey, TValue]{Key: key, Removed: true})
+	}
+
+	c.mx.Unlock()
+
+	return version, c.save(w, opts, func(w io.Writer) error { return c.writeRecords(w, records) })
+}
@@ -0,0 +1,68 @@
+package cacheMachine
+
+import (
+	"bytes"
+	"testing"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_SaveIncremental(t *testing.T) {
+	c := initializeFullCache(1000, nil)
+
+	full := bytes.Buffer{}
+	version, err := c.SaveIncremental(&full, 0, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error saving the full snapshot, got %v", err)
+	}
+
+	c.Add(1, 100)
+	c.Add(2000, 2000)
+	c.Remove(3)
+	c.Remove(2000)
+	c.Remove(4)
+	c.Add(4, 4)
+
+	increment := bytes.Buffer{}
+	version, err = c.SaveIncremental(&increment, version, SaveOptions{EncryptionKey: make([]byte, 16)})
+	if err != nil {
+		t.Fatalf("Expected no error saving the increment, got %v", err)
+	}
+
+	if increment.Len()*10 > full.Len() {
+		t.Errorf("Expected the increment to be much smaller than the full snapshot, got %d and %d bytes", increment.Len(), full.Len())
+	}
+
+	restored := New[int, int](nil)
+	restored.Load(&full)
+
+	if _, err := restored.LoadWithOptions(&increment, LoadOptions{EncryptionKey: make([]byte, 16)}); err != nil {
+		t.Fatalf("Expected no error loading the increment, got %v", err)
+	}
+
+	if restored.Count() != c.Count() || restored.GetValue(1) != 100 || restored.Exist(3) || !restored.Exist(4) {
+		t.Errorf("Expected the restored cache to match the original, got %d and %d entries", restored.Count(), c.Count())
+	}
+
+	empty := bytes.Buffer{}
+	c.SaveIncremental(&empty, version, SaveOptions{})
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if n := len(c.tombstones); n != 0 {
+		t.Errorf("Expected records of removals to be forgotten once saved, got %d", n)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_SaveIncremental(b *testing.B) {
+	c := initializeFullCache(10000, nil)
+	version, _ := c.SaveIncremental(&bytes.Buffer{}, 0, SaveOptions{})
+
+	for n := 0; n < b.N; n++ {
+		c.Add(n%10000, n)
+		version, _ = c.SaveIncremental(&bytes.Buffer{}, version, SaveOptions{})
+	}
+}
@@ -0,0 +1,72 @@
+package cacheMachine
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+//------PRIVATE------
+
+//find returns the key and the entry that precedes all the others according to the function supplied. This method
+//has no mutex protection
+func (c *Cache[TKey, TValue]) find(before func(a, b *entry[TValue]) bool) (TKey, Entry[TValue], bool) {
+	var key TKey
+	var found *entry[TValue]
+
+	for k, e := range c.data {
+		if c.live(e) && (found == nil || before(e, found)) {
+			key, found = k, e
+		}
+	}
+
+	if found == nil {
+		return key, nil, false
+	}
+
+	return key, found, true
+}
+
+//------PUBLIC------
+
+//Oldest returns the key and the entry that was stored the earliest, e.g. to inspect what's about to be evicted.
+//Returns false if the cache is empty. It takes time proportional to the size of the cache
+func (c *Cache[TKey, TValue]) Oldest() (TKey, Entry[TValue], bool) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	return c.find(func(a, b *entry[TValue]) bool { return a.created.Before(b.created) })
+}
+
+//LeastRecentlyUsed returns the key and the entry that was read the least recently, counting its addition as a read,
+//regardless of the eviction policy in use. Returns false if the cache is empty. It takes time proportional to the
+//size of the cache
+func (c *Cache[TKey, TValue]) LeastRecentlyUsed() (TKey, Entry[TValue], bool) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	return c.find(func(a, b *entry[TValue]) bool {
+		return atomic.LoadInt64(&a.lastRead) < atomic.LoadInt64(&b.lastRead)
+	})
+}
+
+//ExpiringWithin returns the values of the entries due to expire within the duration specified, whether by their
+//timers or their time buckets, e.g. to refresh them ahead of time. Pinned entries are left out, as they don't expire
+func (c *Cache[TKey, TValue]) ExpiringWithin(d time.Duration) map[TKey]TValue {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	until := c.now().Add(d)
+	results := make(map[TKey]TValue)
+
+	for key, e := range c.data {
+		if e.pinned || !c.live(e) {
+			continue
+		}
+
+		if at := e.ExpiresAt(); !at.IsZero() && !at.After(until) {
+			results[key] = e.Val
+		}
+	}
+
+	return results
+}
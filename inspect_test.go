@@ -0,0 +1,68 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_Oldest(t *testing.T) {
+	c := New[int, int](nil)
+
+	if _, _, ok := c.Oldest(); ok {
+		t.Errorf("Expected empty cache to have no oldest entry")
+	}
+
+	for i := 0; i < 3; i++ {
+		c.Add(i, i)
+		time.Sleep(time.Millisecond)
+	}
+
+	c.Get(0)
+
+	if k, e, ok := c.Oldest(); !ok || k != 0 || e.Value() != 0 {
+		t.Errorf("Expected key 0 to be the oldest, got %d and %t", k, ok)
+	}
+}
+
+func TestCache_LeastRecentlyUsed(t *testing.T) {
+	c := New[int, int](nil)
+
+	for i := 0; i < 3; i++ {
+		c.Add(i, i)
+		time.Sleep(time.Millisecond)
+	}
+
+	c.Get(0)
+
+	if k, e, ok := c.LeastRecentlyUsed(); !ok || k != 1 || e.Value() != 1 {
+		t.Errorf("Expected key 1 to be the least recently used, got %d and %t", k, ok)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_LeastRecentlyUsed(b *testing.B) {
+	c := initializeFullCache(100, nil)
+
+	for n := 0; n < b.N; n++ {
+		c.LeastRecentlyUsed()
+	}
+}
+
+func TestCache_ExpiringWithin(t *testing.T) {
+	c := New[int, int](nil)
+	c.AddWithTimeout(1, 1, time.Second)
+	c.AddWithTimeout(2, 2, time.Hour)
+	c.AddToBucket(time.Now().Add(time.Second*2), 3, 3)
+	c.AddWithTimeout(4, 4, time.Second)
+	c.Pin(4)
+	c.Add(5, 5)
+
+	soon := c.ExpiringWithin(time.Minute)
+
+	if len(soon) != 2 || soon[1] != 1 || soon[3] != 3 {
+		t.Errorf("Expected keys 1 and 3 to be expiring within a minute, got %v", soon)
+	}
+}
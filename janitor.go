@@ -0,0 +1,88 @@
+package cacheMachine
+
+import (
+	"errors"
+	"time"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//ErrClosed is returned when the cache can't be used because it has been closed
+var ErrClosed = errors.New("cacheMachine: cache is closed")
+
+//------PRIVATE------
+
+//runJanitor sweeps out expired entries every interval until the cache is closed
+func (c *Cache[TKey, TValue]) runJanitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.closed:
+			return
+		case <-ticker.C:
+			c.sweep()
+		}
+	}
+}
+
+//sweep removes all the entries past their timeout, unless they are pinned or their adaptive timeout gets
+//lengthened instead. Returns number of entries removed
+func (c *Cache[TKey, TValue]) sweep() int {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	removed := 0
+
+	for key, e := range c.data {
+		if e.pinned || !e.expired() || c.adapt(e) {
+			continue
+		}
+
+		c.remove(key)
+		c.emit(EventExpire, key, e)
+		removed++
+	}
+
+	return removed
+}
+
+//isClosed checks whether Close has been called
+func (c *Cache[TKey, TValue]) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+//------PUBLIC------
+
+//RemoveExpired removes all the entries past their timeout straight away, e.g. the ones left without a timer because
+//Requirements.MaxTimers was reached. Returns number of entries removed
+func (c *Cache[TKey, TValue]) RemoveExpired() int {
+	return c.sweep()
+}
+
+//Close releases everything the cache holds on to: it stops the timers of all the entries and time buckets along
+//with the background goroutines, e.g. the janitor of Requirements.CleanupInterval, WatchMemory and RecordHistory,
+//waits for the expired entries queued to be archived and empties the cache. The function set by SetOnRemove gets
+//every entry removed. It's safe to call Close more than once. The cache is unusable afterwards: nothing is stored
+//anymore and TryAdd, TryAddBulk, GetOrLoad and GetBulkOrLoad fail with ErrClosed, unless Requirements.SoftFail is
+//set
+func (c *Cache[TKey, TValue]) Close() {
+	c.closeOnce.Do(func() { close(c.closed) })
+	c.swapArchiver(nil)
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	for _, e := range c.data {
+		e.StopTimer()
+		e.stopIdle()
+	}
+
+	c.reset()
+}
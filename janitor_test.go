@@ -0,0 +1,79 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestRequirements_CleanupInterval(t *testing.T) {
+	c := New[int, int](&Requirements{CleanupInterval: time.Millisecond * 20})
+	defer c.Close()
+
+	c.AddWithTimeout(1, 1, time.Millisecond*5)
+	c.Add(2, 2)
+
+	if e := c.GetEntry(1); e == nil || !e.TimerExist() {
+		t.Fatalf("Expected the entry to have a timeout")
+	}
+
+	c.mx.RLock()
+	timer := c.data[1].timer
+	c.mx.RUnlock()
+
+	if timer != nil {
+		t.Errorf("Expected no timer of the entry when the janitor is on")
+	}
+
+	time.Sleep(time.Millisecond * 10)
+
+	if c.Exist(1) {
+		t.Errorf("Expected the expired entry to be reported missing before the sweep")
+	}
+
+	time.Sleep(time.Millisecond * 30)
+
+	if c.Count() != 1 {
+		t.Errorf("Expected the janitor to sweep the expired entry out, got %d entries", c.Count())
+	}
+}
+
+func TestCache_Close(t *testing.T) {
+	c := New[int, int](&Requirements{CleanupInterval: time.Millisecond * 10})
+	c.Close()
+	c.Close()
+
+	c.AddWithTimeout(1, 1, time.Millisecond)
+
+	if _, err := c.TryAdd(2, 2); err != ErrClosed || c.Count() != 0 {
+		t.Errorf("Expected nothing to be stored once the cache is closed, got %d entries and error %v", c.Count(), err)
+	}
+}
+
+func TestCache_Close_timers(t *testing.T) {
+	c := New[int, int](&Requirements{MaxIdleTime: time.Hour})
+
+	var removed int
+	c.SetOnRemove(func(int, int, RemovalReason) { removed++ })
+
+	c.AddWithTimeout(1, 1, time.Hour)
+	c.AddToBucket(time.Now().Add(time.Hour), 2, 2)
+
+	c.Close()
+
+	if d := c.Describe(); d.ActiveTimers != 0 || d.Entries != 0 || d.Buckets != 0 || removed != 2 {
+		t.Errorf("Expected all the timers to be stopped and entries removed, got %+v and %d removed", d, removed)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkRequirements_CleanupInterval(b *testing.B) {
+	c := initializeFullCache(1000, &Requirements{DefaultTimeout: time.Hour, CleanupInterval: time.Hour})
+	defer c.Close()
+
+	for n := 0; n < b.N; n++ {
+		c.sweep()
+	}
+}
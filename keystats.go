@@ -0,0 +1,230 @@
+package cacheMachine
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+	"time"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//KeyStats holds statistics of a single key. Hits, Misses and Loads are counted since the key started being tracked. A
+//key that replaced another key in the sketch inherits its activity as Error, which bounds the reads that might have
+//been missed before the key was tracked
+type KeyStats[TKey Key] struct {
+	Key TKey
+
+	//Number of times the key was read while being present in the cache
+	Hits uint64
+
+	//Number of times the key was read while not being present in the cache
+	Misses uint64
+
+	//Maximum number of reads not counted before the key started being tracked
+	Error uint64
+
+	//Number of times the value of the key was loaded by GetOrLoad or GetBulkOrLoad, failed loads included
+	Loads uint64
+
+	//Duration of the last load of the key. Keys loaded by GetBulkOrLoad get the duration of the whole bulk load
+	LoadLatency time.Duration
+
+	//Cost of the value last added under the key, which is its size in bytes if the values implement Sizer or
+	//the function set by SetCostFunc measures bytes
+	Bytes int64
+}
+
+//Counter of a single key tracked by the sketch
+type keyCounter[TKey Key] struct {
+	stats KeyStats[TKey]
+	index int
+}
+
+//Min-heap of key counters ordered by activity
+type keyHeap[TKey Key] []*keyCounter[TKey]
+
+//Space-saving sketch retaining statistics of at most size most active keys. Once full, a key that isn't tracked yet
+//replaces the least active key tracked and inherits its count as the error
+type keySketch[TKey Key] struct {
+	size     int
+	counters map[TKey]*keyCounter[TKey]
+	heap     keyHeap[TKey]
+	mx       sync.Mutex
+}
+
+//------PRIVATE------
+
+//activity returns the estimated number of reads of the key
+func (kc *keyCounter[TKey]) activity() uint64 {
+	return kc.stats.activity()
+}
+
+//activity returns the estimated number of reads of the key
+func (ks KeyStats[TKey]) activity() uint64 {
+	return ks.Hits + ks.Misses + ks.Error
+}
+
+//record counts single read of the key
+func (s *keySketch[TKey]) record(key TKey, hit bool) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	kc, exist := s.counters[key]
+
+	if !exist {
+		if len(s.heap) < s.size {
+			kc = &keyCounter[TKey]{stats: KeyStats[TKey]{Key: key}}
+			heap.Push(&s.heap, kc)
+		} else {
+			kc = s.heap[0]
+			delete(s.counters, kc.stats.Key)
+
+			//Replacing the least active key, the new key inherits its activity as the error
+			kc.stats = KeyStats[TKey]{Key: key, Error: kc.activity()}
+		}
+
+		s.counters[key] = kc
+	}
+
+	if hit {
+		kc.stats.Hits++
+	} else {
+		kc.stats.Misses++
+	}
+
+	heap.Fix(&s.heap, kc.index)
+}
+
+//update changes statistics of the key using the function supplied, if the key is being tracked. Keys are only
+//tracked by their reads, so that the sketch keeps the keys read the most
+func (s *keySketch[TKey]) update(key TKey, f func(*KeyStats[TKey])) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if kc, exist := s.counters[key]; exist {
+		f(&kc.stats)
+	}
+}
+
+//get returns statistics of the key if it's being tracked
+func (s *keySketch[TKey]) get(key TKey) (KeyStats[TKey], bool) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if kc, exist := s.counters[key]; exist {
+		return kc.stats, true
+	}
+
+	return KeyStats[TKey]{}, false
+}
+
+//top returns statistics of at most n most active keys, the most active first
+func (s *keySketch[TKey]) top(n int) []KeyStats[TKey] {
+	s.mx.Lock()
+	results := make([]KeyStats[TKey], 0, len(s.heap))
+	for _, kc := range s.heap {
+		results = append(results, kc.stats)
+	}
+	s.mx.Unlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].activity() > results[j].activity()
+	})
+
+	if n >= 0 && n < len(results) {
+		results = results[:n]
+	}
+
+	return results
+}
+
+//recordKey counts read of the key for per-key statistics and the admission filter, if they are on. It's safe to call this method under the read lock
+func (c *Cache[TKey, TValue]) recordKey(key TKey, hit bool) {
+	if c.keyStats != nil {
+		c.keyStats.record(key, hit)
+	}
+
+	c.recordUse(key)
+}
+
+//recordLoad counts load of the key that took the duration specified for per-key statistics, if they are on
+func (c *Cache[TKey, TValue]) recordLoad(key TKey, d time.Duration) {
+	if c.keyStats == nil {
+		return
+	}
+
+	c.keyStats.update(key, func(s *KeyStats[TKey]) {
+		s.Loads++
+		s.LoadLatency = d
+	})
+}
+
+//recordBytes records cost of the value added under the key for per-key statistics, if they are on
+func (c *Cache[TKey, TValue]) recordBytes(key TKey, cost int64) {
+	if c.keyStats != nil {
+		c.keyStats.update(key, func(s *KeyStats[TKey]) { s.Bytes = cost })
+	}
+}
+
+//------PUBLIC------
+
+//Len returns number of keys in the heap
+func (h keyHeap[TKey]) Len() int { return len(h) }
+
+//Less orders the keys by their activity, the least active first
+func (h keyHeap[TKey]) Less(i, j int) bool { return h[i].activity() < h[j].activity() }
+
+//Swap swaps two keys, keeping their indexes up to date
+func (h keyHeap[TKey]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+//Push appends the key counter to the heap
+func (h *keyHeap[TKey]) Push(x any) {
+	kc := x.(*keyCounter[TKey])
+	kc.index = len(*h)
+	*h = append(*h, kc)
+}
+
+//Pop removes the last key counter of the heap
+func (h *keyHeap[TKey]) Pop() any {
+	old := *h
+	kc := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return kc
+}
+
+//KeyStats returns statistics of the key specified. Statistics are retained only for Requirements.TrackedKeys most
+//read keys, so false is returned if the key isn't one of them or per-key statistics are off. Loads and adds of keys
+//that are not tracked at the time are not recorded
+func (c *Cache[TKey, TValue]) KeyStats(key TKey) (KeyStats[TKey], bool) {
+	if c.keyStats == nil {
+		return KeyStats[TKey]{}, false
+	}
+
+	return c.keyStats.get(key)
+}
+
+//TopKeys returns statistics of at most n most active keys, the most active first. Negative n returns all the keys
+//being tracked
+func (c *Cache[TKey, TValue]) TopKeys(n int) []KeyStats[TKey] {
+	if c.keyStats == nil {
+		return nil
+	}
+
+	return c.keyStats.top(n)
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//newKeySketch creates sketch retaining statistics of at most size keys
+func newKeySketch[TKey Key](size int) *keySketch[TKey] {
+	return &keySketch[TKey]{
+		size:     size,
+		counters: make(map[TKey]*keyCounter[TKey], size),
+		heap:     make(keyHeap[TKey], 0, size),
+	}
+}
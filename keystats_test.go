@@ -0,0 +1,122 @@
+package cacheMachine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_KeyStats(t *testing.T) {
+	c := initializeFullCache(10, &Requirements{TrackedKeys: 2})
+
+	for i := 0; i < 5; i++ {
+		c.Get(1)
+	}
+	c.Get(20)
+	c.Get(20)
+	c.Get(3)
+
+	s, ok := c.KeyStats(1)
+	if !ok || s.Hits != 5 || s.Misses != 0 {
+		t.Errorf("Expected key 1 to have 5 hits and no misses, got %+v, %t", s, ok)
+	}
+
+	//Key 3 replaced key 20 which was the least active key tracked
+	if _, ok := c.KeyStats(20); ok {
+		t.Errorf("Expected key 20 to be dropped from the statistics, but it was not")
+	}
+
+	s, ok = c.KeyStats(3)
+	if !ok || s.Hits != 1 || s.Error != 2 {
+		t.Errorf("Expected key 3 to have 1 hit and error of 2, got %+v, %t", s, ok)
+	}
+
+	c2 := initializeFullCache(10, nil)
+	c2.Get(1)
+
+	if _, ok := c2.KeyStats(1); ok {
+		t.Errorf("Expected no statistics when per-key statistics are off")
+	}
+}
+
+func TestCache_TopKeys(t *testing.T) {
+	c := initializeFullCache(10, &Requirements{TrackedKeys: 10})
+
+	for i := 0; i < 5; i++ {
+		for j := 0; j <= i; j++ {
+			c.Get(i)
+		}
+	}
+	c.Get(50)
+
+	top := c.TopKeys(2)
+
+	if len(top) != 2 || top[0].Key != 4 || top[1].Key != 3 {
+		t.Errorf("Expected keys 4 and 3 to be the most active, got %+v", top)
+	}
+
+	if n := len(c.TopKeys(-1)); n != 6 {
+		t.Errorf("Expected to get all %d keys tracked, got %d", 6, n)
+	}
+}
+
+func TestCache_KeyStats_loads(t *testing.T) {
+	c := New[int, []byte](&Requirements{TrackedKeys: 10})
+	c.SetCostFunc(func(key int, val []byte) int64 { return int64(len(val)) })
+
+	loader := func(ctx context.Context, key int) ([]byte, error) {
+		time.Sleep(time.Millisecond * 5)
+		return make([]byte, 100), nil
+	}
+
+	c.GetOrLoad(context.Background(), 1, loader)
+	c.Remove(1)
+	c.GetOrLoad(context.Background(), 1, loader)
+
+	c.GetBulkOrLoad(context.Background(), []int{2, 3}, func(ctx context.Context, keys []int) (map[int][]byte, error) {
+		return map[int][]byte{2: make([]byte, 10), 3: make([]byte, 20)}, nil
+	})
+
+	s, ok := c.KeyStats(1)
+	if !ok || s.Misses != 2 || s.Loads != 2 || s.Bytes != 100 || s.LoadLatency < time.Millisecond*5 {
+		t.Errorf("Expected key 1 to have 2 misses and loads of 100 bytes taking at least 5ms, got %+v, %t", s, ok)
+	}
+
+	c.Add(3, make([]byte, 30))
+
+	top := c.TopKeys(-1)
+	for _, s := range top {
+		if s.Key == 3 && (s.Loads != 1 || s.Bytes != 30) {
+			t.Errorf("Expected key 3 to be listed with 1 load and the bytes of the value added last, got %+v", s)
+		}
+	}
+
+	if len(top) != 3 {
+		t.Errorf("Expected 3 keys to be tracked, got %+v", top)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_KeyStats(b *testing.B) {
+	c := initializeFullCache(100, &Requirements{TrackedKeys: 10})
+
+	for n := 0; n < b.N; n++ {
+		c.Get(n % 200)
+		c.KeyStats(n % 200)
+	}
+}
+
+func BenchmarkCache_TopKeys(b *testing.B) {
+	c := initializeFullCache(100, &Requirements{TrackedKeys: 100})
+
+	for i := 0; i < 100; i++ {
+		c.Get(i)
+	}
+
+	for n := 0; n < b.N; n++ {
+		c.TopKeys(10)
+	}
+}
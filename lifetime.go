@@ -0,0 +1,126 @@
+package cacheMachine
+
+import (
+	"time"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//MergeOptions defines what CopyWithOptions and MergeWithOptions carry over from the source entries besides their
+//values. By default, entries copied keep the time remaining until they expire, their priority and their pins
+type MergeOptions struct {
+	//If this is set, entries copied get timeouts the same way as values added by AddBulk instead
+	ResetTimeouts bool
+
+	//If this is set, entries copied get PriorityNormal and are not pinned
+	ResetFlags bool
+}
+
+//Value along with the wall clock time at which it expires and the flags of its entry. Zero deadline means the value
+//has no timeout of its own
+type timedValue[TValue any] struct {
+	val      TValue
+	deadline time.Time
+	priority Priority
+	pinned   bool
+}
+
+//------PRIVATE------
+
+//timedValues returns all the values stored in the cache along with their deadlines. This method has no mutex
+//protection
+func (c *Cache[TKey, TValue]) timedValues() map[TKey]timedValue[TValue] {
+	d := make(map[TKey]timedValue[TValue], len(c.data))
+
+	for key, e := range c.data {
+		if c.live(e) {
+			d[key] = e.timedValue()
+		}
+	}
+
+	return d
+}
+
+//timedValue returns the value of the entry along with its deadline and flags
+func (e *entry[TValue]) timedValue() timedValue[TValue] {
+	return timedValue[TValue]{val: e.Val, deadline: e.ExpiresAt(), priority: e.priority, pinned: e.pinned}
+}
+
+//insertTimed adds the value so that it expires at its deadline, with the priority and pin of its entry. Returns false
+//if the deadline has passed already, in which case nothing is added. This method has no mutex protection
+func (c *Cache[TKey, TValue]) insertTimed(key TKey, v timedValue[TValue]) bool {
+	var t time.Duration
+	if !v.deadline.IsZero() {
+		if t = v.deadline.Sub(c.now()); t <= 0 {
+			return false
+		}
+	}
+
+	e := c.newEntry(key, v.val, t, v.priority)
+	e.pinned = v.pinned
+	c.insert(key, e)
+
+	return true
+}
+
+//addTimed adds the values so that they expire at their deadlines, with the priority and pins of their entries, unless
+//the options say otherwise. Values past their deadlines are not added at all, while values without a deadline are
+//added the same way as by AddBulk. Returns keys of the values added
+func (c *Cache[TKey, TValue]) addTimed(d map[TKey]timedValue[TValue], opts MergeOptions) []TKey {
+	chunk := c.cache.Requirements.LockChunkSize
+	added := make([]TKey, 0, len(d))
+
+	c.mx.Lock()
+	for k, v := range d {
+		if opts.ResetTimeouts {
+			v.deadline = time.Time{}
+		}
+
+		if opts.ResetFlags {
+			v.priority, v.pinned = PriorityNormal, false
+		}
+
+		if !c.insertTimed(k, v) {
+			continue
+		}
+
+		if added = append(added, k); chunk > 0 && len(added)%chunk == 0 {
+			c.mx.Unlock()
+			c.mx.Lock()
+		}
+	}
+	c.mx.Unlock()
+
+	return added
+}
+
+//mergeTimed does the same as addTimed, but the values added are written through as well, the same way as by AddBulk
+func (c *Cache[TKey, TValue]) mergeTimed(d map[TKey]timedValue[TValue], opts MergeOptions) {
+	for _, k := range c.addTimed(d, opts) {
+		c.writeThrough(k, d[k].val)
+	}
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//CopyWithOptions does the same as Copy, but the options define what the copy carries over from the entries copied
+func CopyWithOptions[TKey Key, TValue any](c *Cache[TKey, TValue], opts MergeOptions) Cache[TKey, TValue] {
+	req := c.Requirements()
+	nc := New[TKey, TValue](&req)
+
+	c.mx.RLock()
+	d := c.timedValues()
+	c.mx.RUnlock()
+
+	nc.addTimed(d, opts)
+	return nc
+}
+
+//MergeWithOptions copies all data from cache2 into cache1, the options define what the entries copied carry over
+func MergeWithOptions[TKey Key, TValue any](cache1, cache2 *Cache[TKey, TValue], opts MergeOptions) {
+	cache2.mx.RLock()
+	d := cache2.timedValues()
+	cache2.mx.RUnlock()
+
+	cache1.mergeTimed(d, opts)
+}
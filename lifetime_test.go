@@ -0,0 +1,115 @@
+package cacheMachine
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCopy_keepsDeadlines(t *testing.T) {
+	c := New[int, int](&Requirements{DefaultTimeout: time.Hour})
+	c.AddWithTimeout(1, 1, time.Millisecond*30)
+	c.Add(2, 2)
+
+	cpy := Copy(&c)
+
+	if at := cpy.GetEntry(1).ExpiresAt(); time.Until(at) > time.Millisecond*30 {
+		t.Errorf("Expected the copy to expire within 30ms, got %s", at)
+	}
+
+	if at := cpy.GetEntry(2).ExpiresAt(); time.Until(at) < time.Minute {
+		t.Errorf("Expected the copy to keep the default timeout, got %s", at)
+	}
+
+	time.Sleep(time.Millisecond * 100)
+
+	if cpy.Exist(1) || !cpy.Exist(2) {
+		t.Errorf("Expected only the copy of the short lived entry to expire")
+	}
+}
+
+func TestMerge_keepsDeadlines(t *testing.T) {
+	c1 := New[int, int](nil)
+	c2 := New[int, int](nil)
+	c2.AddWithTimeout(1, 1, time.Millisecond*30)
+
+	Merge[int, int](&c1, &c2)
+	MergeAndReset[int, int](&c1, &c2)
+
+	if !c1.Exist(1) || c2.Count() != 0 {
+		t.Fatalf("Expected the entry to be merged and the source to be reset")
+	}
+
+	time.Sleep(time.Millisecond * 100)
+
+	if c1.Exist(1) {
+		t.Errorf("Expected the merged entry to expire at the deadline of the original")
+	}
+}
+
+func TestCache_Load_keepsDeadlines(t *testing.T) {
+	c1 := New[int, int](nil)
+	c1.AddWithTimeout(1, 1, time.Hour)
+	c1.AddWithTimeout(2, 2, time.Millisecond*10)
+	c1.Add(3, 3)
+
+	buf := bytes.Buffer{}
+	if err := c1.Save(&buf); err != nil {
+		t.Fatalf("Expected snapshot to be saved, got error: %s", err)
+	}
+
+	time.Sleep(time.Millisecond * 30)
+
+	c2 := New[int, int](nil)
+
+	rep, err := c2.LoadWithOptions(&buf, LoadOptions{})
+	if err != nil || rep.Loaded != 2 {
+		t.Fatalf("Expected 2 entries to be loaded, got %d and error %v", rep.Loaded, err)
+	}
+
+	expected := c1.GetEntry(1).ExpiresAt()
+	if at := c2.GetEntry(1).ExpiresAt(); at.Sub(expected) > time.Millisecond || expected.Sub(at) > time.Millisecond {
+		t.Errorf("Expected the loaded entry to expire at %s, got %s", expected, at)
+	}
+
+	if c2.Exist(2) || !c2.GetEntry(3).ExpiresAt().IsZero() {
+		t.Errorf("Expected the expired entry to be left out and the entry without timeout to stay without one")
+	}
+}
+
+func TestCopyWithOptions(t *testing.T) {
+	c := New[int, int](nil)
+	c.AddWithPriority(1, 1, PriorityHigh)
+	c.AddWithTimeout(2, 2, time.Hour)
+	c.Pin(2)
+
+	cpy := Copy(&c)
+
+	if e := cpy.data[1]; e.priority != PriorityHigh {
+		t.Errorf("Expected the copy to keep the priority, got %s", e.priority)
+	}
+
+	if d := cpy.Describe(); d.Pinned != 1 || cpy.GetEntry(2).TTL() < time.Minute {
+		t.Errorf("Expected the copy to keep the pin and the timeout, got %d pinned and TTL %s", d.Pinned, cpy.GetEntry(2).TTL())
+	}
+
+	reset := CopyWithOptions(&c, MergeOptions{ResetTimeouts: true, ResetFlags: true})
+
+	if e := reset.data[1]; e.priority != PriorityNormal || reset.Describe().Pinned != 0 || reset.GetEntry(2).TimerExist() {
+		t.Errorf("Expected the copy to reset the priority, pin and timeout")
+	}
+}
+
+func TestMergeWithOptions(t *testing.T) {
+	c1 := New[int, int](&Requirements{DefaultTimeout: time.Hour})
+	c2 := New[int, int](nil)
+	c2.AddWithTimeout(1, 1, time.Second)
+
+	MergeWithOptions(&c1, &c2, MergeOptions{ResetTimeouts: true})
+
+	if ttl := c1.GetEntry(1).TTL(); ttl < time.Minute {
+		t.Errorf("Expected the merged entry to get the default timeout of the destination, got %s", ttl)
+	}
+}
@@ -0,0 +1,442 @@
+package cacheMachine
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//ErrLoadQueueFull is returned when the load can't start because Requirements.MaxConcurrentLoads loads are running
+//and Requirements.MaxQueuedLoads loads are already waiting
+var ErrLoadQueueFull = errors.New("cacheMachine: too many loads waiting")
+
+//ErrLoaderPanicked is returned to the callers waiting for a load shared with the caller whose loader panicked. The
+//caller that started the load gets the panic itself
+var ErrLoaderPanicked = errors.New("cacheMachine: loader panicked")
+
+//===========[STRUCTS]==================================================================================================
+
+//Loader loads the value of the key that is missing from the cache, e.g. from a database
+type Loader[TKey Key, TValue any] func(ctx context.Context, key TKey) (TValue, error)
+
+//LoaderStage is a single stage of the loader chain created by ChainLoaders
+type LoaderStage[TKey Key, TValue any] struct {
+	Load Loader[TKey, TValue]
+
+	//If this is set, the stage is given up on after this duration and the next stage is tried. 0 means the stage
+	//can take as long as the context of the load allows
+	Timeout time.Duration
+}
+
+//BulkLoader loads the values of the keys missing from the cache at once. Keys missing from the map returned are
+//considered not found
+type BulkLoader[TKey Key, TValue any] func(ctx context.Context, keys []TKey) (map[TKey]TValue, error)
+
+//Load of a single key in progress, shared by everyone who needs the key in the meantime
+type loadCall[TValue any] struct {
+	done chan struct{}
+	val  TValue
+	err  error
+
+	//Defines whether the load failed because the context of the caller that started it is done, which is no reason
+	//for the others to fail
+	abandoned bool
+}
+
+//Limits the number of loads running at the same time
+type loadLimiter struct {
+	slots   chan struct{}
+	waiting int32
+	queue   int32
+}
+
+//------PRIVATE------
+
+//acquire waits for a free slot unless the queue is full or the context is done
+func (l *loadLimiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if w := atomic.AddInt32(&l.waiting, 1); l.queue > 0 && w > l.queue {
+		atomic.AddInt32(&l.waiting, -1)
+		return ErrLoadQueueFull
+	}
+	defer atomic.AddInt32(&l.waiting, -1)
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+//release frees the slot acquired
+func (l *loadLimiter) release() {
+	<-l.slots
+}
+
+//wait waits for the load to finish unless the context is done first
+func (call *loadCall[TValue]) wait(ctx context.Context) (TValue, error) {
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		var nilVal TValue
+		return nilVal, ctx.Err()
+	}
+}
+
+//abandonedFor checks whether the error returned by wait only comes from the caller that started the load giving up,
+//while the context supplied is still live, so the caller waiting has to load the key itself
+func (call *loadCall[TValue]) abandonedFor(ctx context.Context, err error) bool {
+	//Live context means wait returned because the load has finished, so the flag can be read
+	return err != nil && ctx.Err() == nil && call.abandoned
+}
+
+//join returns the load of the key in progress, or starts a new one if there is none. Returns true if the caller
+//started the load and has to finish it
+func (c *Cache[TKey, TValue]) join(key TKey) (*loadCall[TValue], bool) {
+	c.flightMx.Lock()
+	defer c.flightMx.Unlock()
+
+	if call, exist := c.flights[key]; exist {
+		return call, false
+	}
+
+	call := &loadCall[TValue]{done: make(chan struct{})}
+	c.flights[key] = call
+
+	return call, true
+}
+
+//finish completes the load of the key started by join, handing the result over to everyone waiting for it
+func (c *Cache[TKey, TValue]) finish(key TKey, call *loadCall[TValue], val TValue, err error) {
+	c.flightMx.Lock()
+	delete(c.flights, key)
+	c.flightMx.Unlock()
+
+	call.val, call.err = val, err
+	close(call.done)
+}
+
+//lead finishes the load of the key started by join with the result of the load made under the context supplied
+func (c *Cache[TKey, TValue]) lead(ctx context.Context, key TKey, call *loadCall[TValue], val TValue, err error) {
+	call.abandoned = err != nil && ctx.Err() != nil
+	c.finish(key, call, val, err)
+}
+
+//abort finishes the loads of the keys started by join with ErrLoaderPanicked if the loader panicked and panics again,
+//so that the callers waiting for the loads don't wait forever and later callers don't join them. It must be deferred
+func (c *Cache[TKey, TValue]) abort(keys []TKey, calls []*loadCall[TValue]) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	var nilVal TValue
+	for i, key := range keys {
+		c.finish(key, calls[i], nilVal, ErrLoaderPanicked)
+	}
+
+	panic(r)
+}
+
+//loadShared loads the key using the loader supplied on behalf of everyone waiting for the load started by join
+func (c *Cache[TKey, TValue]) loadShared(ctx context.Context, key TKey, call *loadCall[TValue], loader Loader[TKey, TValue]) (TValue, error) {
+	defer c.abort([]TKey{key}, []*loadCall[TValue]{call})
+
+	v, err := c.load(ctx, key, loader)
+	c.lead(ctx, key, call, v, err)
+
+	return v, err
+}
+
+//loadBulkShared loads the keys using the bulk loader supplied on behalf of everyone waiting for the loads started by
+//join, calls[i] being the load of keys[i]. Returns values loaded along with the error of the loader, ErrKeyNotFound
+//if some keys are missing from its result
+func (c *Cache[TKey, TValue]) loadBulkShared(ctx context.Context, keys []TKey, calls []*loadCall[TValue], loader BulkLoader[TKey, TValue]) (map[TKey]TValue, error) {
+	defer c.abort(keys, calls)
+
+	loaded, err := c.loadBulk(ctx, keys, loader)
+
+	for i, key := range keys {
+		v, found := loaded[key]
+
+		switch {
+		case found:
+			c.finish(key, calls[i], v, nil)
+		case err != nil:
+			c.lead(ctx, key, calls[i], v, err)
+		default:
+			c.finish(key, calls[i], v, ErrKeyNotFound)
+		}
+	}
+
+	if err == nil && len(loaded) < len(keys) {
+		err = ErrKeyNotFound
+	}
+
+	return loaded, err
+}
+
+//peek returns the value of the key without counting it as read
+func (c *Cache[TKey, TValue]) peek(key TKey) (TValue, bool) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if e, exist := c.lookup(key); exist {
+		return e.Value(), true
+	}
+
+	var nilVal TValue
+	return nilVal, false
+}
+
+//load runs the loader within the limits of concurrent loads and adds the value loaded to the cache
+func (c *Cache[TKey, TValue]) load(ctx context.Context, key TKey, loader Loader[TKey, TValue]) (TValue, error) {
+	if c.loads != nil {
+		if err := c.loads.acquire(ctx); err != nil {
+			var nilVal TValue
+			return nilVal, err
+		}
+		defer c.loads.release()
+	}
+
+	c.beforeLoad(key)
+
+	start := time.Now()
+	v, err := loader(ctx, key)
+	c.recordLoad(key, time.Since(start))
+
+	if c.cache.Requirements.CallbackTimeout > 0 {
+		c.reportSlow("GetOrLoad", start)
+	}
+
+	if err != nil {
+		return v, err
+	}
+
+	v = c.postProcess(key, v)
+	c.addLoaded(key, v, MetadataFromContext(ctx))
+
+	return v, nil
+}
+
+//loadBulk runs the bulk loader within the limits of concurrent loads and adds the values loaded to the cache. The
+//whole bulk load takes a single slot
+func (c *Cache[TKey, TValue]) loadBulk(ctx context.Context, keys []TKey, loader BulkLoader[TKey, TValue]) (map[TKey]TValue, error) {
+	if c.loads != nil {
+		if err := c.loads.acquire(ctx); err != nil {
+			return nil, err
+		}
+		defer c.loads.release()
+	}
+
+	for _, key := range keys {
+		c.beforeLoad(key)
+	}
+
+	start := time.Now()
+	d, err := loader(ctx, keys)
+
+	took := time.Since(start)
+	for _, key := range keys {
+		c.recordLoad(key, took)
+	}
+
+	if c.cache.Requirements.CallbackTimeout > 0 {
+		c.reportSlow("GetBulkOrLoad", start)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	//Only the keys asked for are kept, so that the loader can't overwrite other entries
+	loaded := make(map[TKey]TValue, len(keys))
+	for _, key := range keys {
+		if v, found := d[key]; found {
+			loaded[key] = c.postProcess(key, v)
+		}
+	}
+
+	c.addBulkWithMetadata(loaded, MetadataFromContext(ctx))
+
+	return loaded, nil
+}
+
+//------PUBLIC------
+
+//GetOrLoad returns the value of the key, loading it using the loader supplied and adding it to the cache if it's
+//missing. Errors of the loader are returned as they are and nothing is added to the cache. When
+//Requirements.MaxConcurrentLoads loads are already running, the load waits for one of them to finish until
+//the context is done. Concurrent calls for the same key share a single load. If the context of the caller that
+//started the load is done first, the others load the key under their own contexts instead of failing along with it.
+//If the loader panics, the others get ErrLoaderPanicked. Closed cache fails with ErrClosed unless Requirements.SoftFail is set
+func (c *Cache[TKey, TValue]) GetOrLoad(ctx context.Context, key TKey, loader Loader[TKey, TValue]) (TValue, error) {
+	if c.isClosed() {
+		return c.bypassLoad(ctx, key, loader)
+	}
+
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+
+	for {
+		call, leader := c.join(key)
+		if !leader {
+			v, err := call.wait(ctx)
+			if call.abandonedFor(ctx, err) {
+				continue
+			}
+			return v, err
+		}
+
+		//The key may have been added, or loaded by a load that has just finished, since it was found missing
+		if v, ok := c.peek(key); ok {
+			c.finish(key, call, v, nil)
+			return v, nil
+		}
+
+		return c.loadShared(ctx, key, call, loader)
+	}
+}
+
+//GetBulkOrLoad returns the values of the keys supplied, loading the ones missing from the cache in a single call of
+//the loader supplied. Keys that are already being loaded by GetOrLoad or another GetBulkOrLoad are not loaded again,
+//their loads are waited for instead. Keys that couldn't be found nor loaded are missing from the map returned, along
+//with the first error that occurred, ErrKeyNotFound for keys missing from the result of the loader. If the loader
+//panics, calls waiting for the keys it was loading get ErrLoaderPanicked. Closed cache fails with ErrClosed unless Requirements.SoftFail is set
+func (c *Cache[TKey, TValue]) GetBulkOrLoad(ctx context.Context, keys []TKey, loader BulkLoader[TKey, TValue]) (map[TKey]TValue, error) {
+	if c.isClosed() {
+		return c.bypassLoadBulk(ctx, keys, loader)
+	}
+
+	results := make(map[TKey]TValue, len(keys))
+
+	var missing []TKey
+	var leading []*loadCall[TValue]
+	waiting := make(map[TKey]*loadCall[TValue])
+
+	for _, key := range keys {
+		if v, ok := c.Get(key); ok {
+			results[key] = v
+			continue
+		}
+
+		call, leader := c.join(key)
+		if !leader {
+			waiting[key] = call
+			continue
+		}
+
+		if v, ok := c.peek(key); ok {
+			c.finish(key, call, v, nil)
+			results[key] = v
+			continue
+		}
+
+		missing = append(missing, key)
+		leading = append(leading, call)
+	}
+
+	var firstErr error
+
+	if len(missing) > 0 {
+		loaded, err := c.loadBulkShared(ctx, missing, leading, loader)
+		for key, v := range loaded {
+			results[key] = v
+		}
+
+		firstErr = err
+	}
+
+	var abandoned []TKey
+
+	for key, call := range waiting {
+		v, err := call.wait(ctx)
+		if call.abandonedFor(ctx, err) {
+			abandoned = append(abandoned, key)
+			continue
+		}
+
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		results[key] = v
+	}
+
+	//Keys whose loads were abandoned by the callers that started them are loaded under the context of this call
+	if len(abandoned) > 0 {
+		loaded, err := c.GetBulkOrLoad(ctx, abandoned, loader)
+		for key, v := range loaded {
+			results[key] = v
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return results, firstErr
+}
+
+//InFlightLoads returns number of keys being loaded by GetOrLoad and GetBulkOrLoad at the moment
+func (c *Cache[TKey, TValue]) InFlightLoads() int {
+	c.flightMx.Lock()
+	defer c.flightMx.Unlock()
+	return len(c.flights)
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//ChainLoaders creates loader trying the stages in order until one of them succeeds, e.g. remote cache, then
+//database, then default value. The error of the last stage is returned if all of them fail. Stages are not tried
+//any further once the context of the load is done
+func ChainLoaders[TKey Key, TValue any](stages ...LoaderStage[TKey, TValue]) Loader[TKey, TValue] {
+	return func(ctx context.Context, key TKey) (TValue, error) {
+		var v TValue
+		err := ErrKeyNotFound
+
+		for _, stage := range stages {
+			if ctx.Err() != nil {
+				return v, ctx.Err()
+			}
+
+			v, err = runStage(ctx, key, stage)
+			if err == nil {
+				return v, nil
+			}
+		}
+
+		return v, err
+	}
+}
+
+//runStage runs the stage of the loader chain within its timeout
+func runStage[TKey Key, TValue any](ctx context.Context, key TKey, stage LoaderStage[TKey, TValue]) (TValue, error) {
+	if stage.Timeout < 1 {
+		return stage.Load(ctx, key)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, stage.Timeout)
+	defer cancel()
+
+	return stage.Load(ctx, key)
+}
+
+//newLoadLimiter creates limiter allowing n loads at the same time with at most queue loads waiting
+func newLoadLimiter(n, queue int) *loadLimiter {
+	return &loadLimiter{slots: make(chan struct{}, n), queue: int32(queue)}
+}
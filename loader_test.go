@@ -0,0 +1,426 @@
+package cacheMachine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_GetOrLoad(t *testing.T) {
+	c := initializeFullCache(1, nil)
+
+	loads := 0
+	loader := func(ctx context.Context, k int) (int, error) {
+		loads++
+		return k * 10, nil
+	}
+
+	if v, err := c.GetOrLoad(context.Background(), 0, loader); err != nil || v != 0 || loads != 0 {
+		t.Errorf("Expected to get cached value without loading, got %d, error %v and %d loads", v, err, loads)
+	}
+
+	if v, err := c.GetOrLoad(context.Background(), 5, loader); err != nil || v != 50 || !c.Exist(5) {
+		t.Errorf("Expected missing value to be loaded and cached, got %d and error %v", v, err)
+	}
+
+	failure := errors.New("backend down")
+
+	if _, err := c.GetOrLoad(context.Background(), 6, func(ctx context.Context, k int) (int, error) { return 0, failure }); err != failure || c.Exist(6) {
+		t.Errorf("Expected error of the loader to be returned without caching, got %v", err)
+	}
+}
+
+func TestCache_GetOrLoad_addedMeanwhile(t *testing.T) {
+	c := New[int, int](nil)
+
+	//The value is added right after the read misses, before the load starts
+	c.Use(func(next Operation[int, int]) Operation[int, int] {
+		return func(op *Op[int, int]) (int, bool) {
+			v, ok := next(op)
+			if op.Kind == OpGet && !ok {
+				c.put(op.Key, 1)
+			}
+			return v, ok
+		}
+	})
+
+	loads := 0
+	v, err := c.GetOrLoad(context.Background(), 1, func(ctx context.Context, k int) (int, error) {
+		loads++
+		return 10, nil
+	})
+
+	if err != nil || v != 1 || loads != 0 || c.GetValue(1) != 1 {
+		t.Errorf("Expected the value added meanwhile to be returned without loading, got %d, error %v and %d loads", v, err, loads)
+	}
+}
+
+func TestCache_GetOrLoad_leaderCancelled(t *testing.T) {
+	c := New[int, int](nil)
+
+	var loads int32
+	loader := func(ctx context.Context, k int) (int, error) {
+		if atomic.AddInt32(&loads, 1) == 1 {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+		return k * 10, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := c.GetOrLoad(ctx, 1, loader)
+		leaderErr <- err
+	}()
+
+	for c.InFlightLoads() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	waiter := make(chan int, 1)
+	go func() {
+		v, err := c.GetOrLoad(context.Background(), 1, loader)
+		if err != nil {
+			t.Errorf("Expected the waiter not to get the error of the leader, got %v", err)
+		}
+		waiter <- v
+	}()
+
+	time.Sleep(time.Millisecond * 20)
+	cancel()
+
+	if err := <-leaderErr; err != context.Canceled {
+		t.Errorf("Expected the leader to get its own %v, got %v", context.Canceled, err)
+	}
+
+	if v := <-waiter; v != 10 || atomic.LoadInt32(&loads) != 2 {
+		t.Errorf("Expected the waiter to load the key itself, got %d after %d loads", v, loads)
+	}
+}
+
+func TestCache_GetOrLoad_panic(t *testing.T) {
+	c := New[int, int](nil)
+
+	release := make(chan struct{})
+	leaderPanic := make(chan interface{}, 1)
+	go func() {
+		defer func() { leaderPanic <- recover() }()
+		c.GetOrLoad(context.Background(), 1, func(ctx context.Context, k int) (int, error) {
+			<-release
+			panic("backend exploded")
+		})
+	}()
+
+	for c.InFlightLoads() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	waiterErr := make(chan error, 1)
+	go func() {
+		_, err := c.GetOrLoad(context.Background(), 1, func(ctx context.Context, k int) (int, error) { return k, nil })
+		waiterErr <- err
+	}()
+
+	time.Sleep(time.Millisecond * 20)
+	close(release)
+
+	if r := <-leaderPanic; r != "backend exploded" {
+		t.Errorf("Expected the panic of the loader to reach the leader, got %v", r)
+	}
+
+	if err := <-waiterErr; err != ErrLoaderPanicked {
+		t.Errorf("Expected the waiter to get %v, got %v", ErrLoaderPanicked, err)
+	}
+
+	if n := c.InFlightLoads(); n != 0 {
+		t.Errorf("Expected the load to be forgotten after the panic, got %d keys in flight", n)
+	}
+
+	if v, err := c.GetOrLoad(context.Background(), 1, func(ctx context.Context, k int) (int, error) { return 10, nil }); err != nil || v != 10 {
+		t.Errorf("Expected the key to be loaded again after the panic, got %d and error %v", v, err)
+	}
+}
+
+func TestRequirements_MaxConcurrentLoads(t *testing.T) {
+	c := initializeFullCache(0, &Requirements{MaxConcurrentLoads: 2, MaxQueuedLoads: 1})
+
+	release := make(chan struct{})
+	var running, peak int32
+
+	loader := func(ctx context.Context, k int) (int, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+
+		<-release
+		atomic.AddInt32(&running, -1)
+		return k, nil
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			c.GetOrLoad(context.Background(), k, loader)
+		}(i)
+	}
+
+	time.Sleep(time.Millisecond * 50)
+
+	if _, err := c.GetOrLoad(context.Background(), 10, loader); err != ErrLoadQueueFull {
+		t.Errorf("Expected to get ErrLoadQueueFull with 2 loads running and 1 waiting, got %v", err)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if peak != 2 || c.Count() != 3 {
+		t.Errorf("Expected at most 2 loads at the same time and 3 values loaded, got %d and %d", peak, c.Count())
+	}
+
+	c2 := initializeFullCache(0, &Requirements{MaxConcurrentLoads: 1})
+	block := make(chan struct{})
+	defer close(block)
+
+	go c2.GetOrLoad(context.Background(), 1, func(ctx context.Context, k int) (int, error) {
+		<-block
+		return k, nil
+	})
+	time.Sleep(time.Millisecond * 50)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	if _, err := c2.GetOrLoad(ctx, 2, loader); err != context.DeadlineExceeded {
+		t.Errorf("Expected waiting load to give up once the context is done, got %v", err)
+	}
+}
+
+func TestCache_GetBulkOrLoad(t *testing.T) {
+	c := initializeFullCache(2, nil)
+
+	release := make(chan struct{})
+	var loads int32
+
+	bulk := func(ctx context.Context, keys []int) (map[int]int, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+
+		d := make(map[int]int)
+		for _, k := range keys {
+			if k != 13 {
+				d[k] = k * 10
+			}
+		}
+		d[99] = 99
+
+		return d, nil
+	}
+
+	var results map[int]int
+	var bulkErr error
+
+	done := make(chan struct{})
+	go func() {
+		results, bulkErr = c.GetBulkOrLoad(context.Background(), []int{0, 1, 10, 11, 13}, bulk)
+		close(done)
+	}()
+
+	time.Sleep(time.Millisecond * 50)
+
+	if n := c.InFlightLoads(); n != 3 {
+		t.Errorf("Expected %d keys to be in flight, got %d", 3, n)
+	}
+
+	//Key 10 is being loaded by the bulk call, so the single call waits for it instead of loading it again
+	single := make(chan int)
+	go func() {
+		v, _ := c.GetOrLoad(context.Background(), 10, func(ctx context.Context, k int) (int, error) {
+			atomic.AddInt32(&loads, 1)
+			return -1, nil
+		})
+		single <- v
+	}()
+
+	time.Sleep(time.Millisecond * 50)
+	close(release)
+	<-done
+
+	if v := <-single; v != 100 || loads != 1 {
+		t.Errorf("Expected key 10 to be loaded once by the bulk call, got value %d and %d loads", v, loads)
+	}
+
+	if len(results) != 4 || results[11] != 110 || bulkErr != ErrKeyNotFound || c.Exist(99) {
+		t.Errorf("Expected 4 values and ErrKeyNotFound for key 13 without caching key 99, got %v and error %v", results, bulkErr)
+	}
+
+	if n := c.InFlightLoads(); n != 0 {
+		t.Errorf("Expected no keys in flight once the loads finished, got %d", n)
+	}
+}
+
+func TestCache_GetBulkOrLoad_panic(t *testing.T) {
+	c := New[int, int](nil)
+
+	release := make(chan struct{})
+	leaderPanic := make(chan interface{}, 1)
+	go func() {
+		defer func() { leaderPanic <- recover() }()
+		c.GetBulkOrLoad(context.Background(), []int{1, 2}, func(ctx context.Context, keys []int) (map[int]int, error) {
+			<-release
+			panic("backend exploded")
+		})
+	}()
+
+	for c.InFlightLoads() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	waiterErr := make(chan error, 1)
+	go func() {
+		_, err := c.GetOrLoad(context.Background(), 2, func(ctx context.Context, k int) (int, error) { return k, nil })
+		waiterErr <- err
+	}()
+
+	time.Sleep(time.Millisecond * 20)
+	close(release)
+
+	if r := <-leaderPanic; r != "backend exploded" {
+		t.Errorf("Expected the panic of the loader to reach the leader, got %v", r)
+	}
+
+	if err := <-waiterErr; err != ErrLoaderPanicked {
+		t.Errorf("Expected the waiter to get %v, got %v", ErrLoaderPanicked, err)
+	}
+
+	if n := c.InFlightLoads(); n != 0 {
+		t.Errorf("Expected all the keys of the bulk load to be forgotten after the panic, got %d keys in flight", n)
+	}
+}
+
+func TestCache_InFlightLoads(t *testing.T) {
+	c := initializeFullCache(0, nil)
+
+	release := make(chan struct{})
+	var loads int32
+
+	loader := func(ctx context.Context, k int) (int, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return k, nil
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.GetOrLoad(context.Background(), 1, loader)
+		}()
+	}
+
+	time.Sleep(time.Millisecond * 50)
+
+	if n := c.InFlightLoads(); n != 1 {
+		t.Errorf("Expected %d key to be in flight, got %d", 1, n)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if loads != 1 {
+		t.Errorf("Expected concurrent loads of the same key to share %d load, got %d", 1, loads)
+	}
+}
+
+func TestChainLoaders(t *testing.T) {
+	var tried []string
+
+	slow := func(ctx context.Context, k int) (int, error) {
+		tried = append(tried, "remote")
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+
+	missing := func(ctx context.Context, k int) (int, error) {
+		tried = append(tried, "db")
+		return 0, ErrKeyNotFound
+	}
+
+	synthesize := func(ctx context.Context, k int) (int, error) {
+		tried = append(tried, "default")
+		return -1, nil
+	}
+
+	loader := ChainLoaders(
+		LoaderStage[int, int]{Load: slow, Timeout: time.Millisecond * 20},
+		LoaderStage[int, int]{Load: missing},
+		LoaderStage[int, int]{Load: synthesize},
+	)
+
+	if v, err := loader(context.Background(), 1); err != nil || v != -1 {
+		t.Errorf("Expected the last stage to provide the value, got %d and error %v", v, err)
+	}
+
+	if len(tried) != 3 || tried[0] != "remote" || tried[2] != "default" {
+		t.Errorf("Expected all 3 stages to be tried in order, got %v", tried)
+	}
+
+	if _, err := ChainLoaders(LoaderStage[int, int]{Load: missing})(context.Background(), 1); err != ErrKeyNotFound {
+		t.Errorf("Expected to get the error of the last stage, got %v", err)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_GetBulkOrLoad(b *testing.B) {
+	c := initializeFullCache(0, nil)
+
+	loader := func(ctx context.Context, keys []int) (map[int]int, error) {
+		d := make(map[int]int, len(keys))
+		for _, k := range keys {
+			d[k] = k
+		}
+		return d, nil
+	}
+	ctx := context.Background()
+	keys := []int{1, 2, 3, 4, 5}
+
+	for n := 0; n < b.N; n++ {
+		c.GetBulkOrLoad(ctx, keys, loader)
+	}
+}
+
+func BenchmarkChainLoaders(b *testing.B) {
+	missing := func(ctx context.Context, k int) (int, error) { return 0, ErrKeyNotFound }
+	found := func(ctx context.Context, k int) (int, error) { return k, nil }
+
+	loader := ChainLoaders(LoaderStage[int, int]{Load: missing}, LoaderStage[int, int]{Load: found, Timeout: time.Second})
+	ctx := context.Background()
+
+	for n := 0; n < b.N; n++ {
+		loader(ctx, n)
+	}
+}
+
+func BenchmarkCache_GetOrLoad(b *testing.B) {
+	c := initializeFullCache(0, &Requirements{MaxConcurrentLoads: 4})
+
+	loader := func(ctx context.Context, k int) (int, error) { return k, nil }
+	ctx := context.Background()
+
+	for n := 0; n < b.N; n++ {
+		c.GetOrLoad(ctx, n%100, loader)
+	}
+}
@@ -0,0 +1,58 @@
+package cacheMachine
+
+import (
+	"context"
+	"time"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//Metadata is a bag of values describing where the entry comes from, e.g. trace ID or tenant of the request that added
+//it. It's handed over to subscribers along with every event of the entry. It must not be modified once supplied
+type Metadata map[string]string
+
+//Key of the metadata within the context
+type metadataKey struct{}
+
+//------PUBLIC------
+
+//AddWithMetadata does the same as method "AddWithTimeout" but also attaches the metadata supplied to the entry.
+//Timeout of 0 means the entry gets timeouts the same way as by Add
+func (c *Cache[TKey, TValue]) AddWithMetadata(key TKey, val TValue, timeout time.Duration, meta Metadata) Entry[TValue] {
+	c.mx.Lock()
+	e := c.newEntry(key, val, timeout, PriorityNormal)
+	e.meta = meta
+	c.insert(key, e)
+	c.mx.Unlock()
+
+	c.writeThrough(key, val)
+
+	return e
+}
+
+//GetMetadata returns the metadata attached to the entry of the key. Returns false if the key is not present
+func (c *Cache[TKey, TValue]) GetMetadata(key TKey) (Metadata, bool) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	e, exist := c.lookup(key)
+	if !exist {
+		return nil, false
+	}
+
+	return e.meta, true
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//ContextWithMetadata returns a copy of the context carrying the metadata supplied. Values loaded by GetOrLoad and
+//GetBulkOrLoad using the context get the metadata attached, and loaders can read it using MetadataFromContext
+func ContextWithMetadata(ctx context.Context, meta Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, meta)
+}
+
+//MetadataFromContext returns the metadata carried by the context, or nil if there is none
+func MetadataFromContext(ctx context.Context) Metadata {
+	meta, _ := ctx.Value(metadataKey{}).(Metadata)
+	return meta
+}
@@ -0,0 +1,50 @@
+package cacheMachine
+
+import (
+	"context"
+	"testing"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_AddWithMetadata(t *testing.T) {
+	c := New[int, int](&Requirements{MaxEntries: 1})
+
+	var events []Event[int]
+	c.Subscribe(func(e Event[int]) { events = append(events, e) })
+
+	c.AddWithMetadata(1, 1, 0, Metadata{"trace": "abc"})
+
+	if meta, ok := c.GetMetadata(1); !ok || meta["trace"] != "abc" {
+		t.Errorf("Expected metadata of the key, got %v", meta)
+	}
+
+	c.Add(2, 2)
+
+	if len(events) != 3 || events[0].Metadata["trace"] != "abc" || events[2].Kind != EventEvict || events[2].Metadata["trace"] != "abc" {
+		t.Errorf("Expected events of the key to carry its metadata, got %+v", events)
+	}
+
+	if meta, ok := c.GetMetadata(2); !ok || meta != nil {
+		t.Errorf("Expected no metadata for the key added without it, got %v", meta)
+	}
+}
+
+func TestContextWithMetadata(t *testing.T) {
+	c := New[int, int](nil)
+	ctx := ContextWithMetadata(context.Background(), Metadata{"tenant": "t1"})
+
+	var seen Metadata
+	c.GetOrLoad(ctx, 1, func(ctx context.Context, key int) (int, error) {
+		seen = MetadataFromContext(ctx)
+		return key, nil
+	})
+
+	if meta, _ := c.GetMetadata(1); seen["tenant"] != "t1" || meta["tenant"] != "t1" {
+		t.Errorf("Expected the loader and the entry loaded to get the metadata of the context, got %v and %v", seen, meta)
+	}
+
+	if meta := MetadataFromContext(context.Background()); meta != nil {
+		t.Errorf("Expected no metadata in a plain context, got %v", meta)
+	}
+}
@@ -0,0 +1,100 @@
+package cacheMachine
+
+import (
+	"strconv"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//OpKind is the kind of operation passed through the middleware
+type OpKind int
+
+const (
+	//OpGet is the operation of Get
+	OpGet OpKind = iota
+
+	//OpAdd is the operation of Add
+	OpAdd
+
+	//OpRemove is the operation of Remove
+	OpRemove
+)
+
+//Op is a single operation of the cache passed through the middleware. Middleware may change the key and the value
+//before passing the operation on, e.g. to normalize keys
+type Op[TKey Key, TValue any] struct {
+	Kind OpKind
+	Key  TKey
+
+	//Value to add. Zero for OpGet and OpRemove
+	Value TValue
+
+	//Entry added by OpAdd. Nil if the operation hasn't reached the cache
+	entry Entry[TValue]
+}
+
+//Operation performs the operation. For OpGet, it returns the value found and whether the key is present, for OpAdd
+//and OpRemove, the result is only reported as true once the operation reaches the cache
+type Operation[TKey Key, TValue any] func(op *Op[TKey, TValue]) (TValue, bool)
+
+//Middleware wraps the operation, e.g. to check permissions, record metrics or validate values. It can pass the
+//operation on to next, possibly changed, or stop it by returning without calling next
+type Middleware[TKey Key, TValue any] func(next Operation[TKey, TValue]) Operation[TKey, TValue]
+
+//------PRIVATE------
+
+//chain returns the operation composed of the middleware set by Use, or nil if there is no middleware
+func (c *Cache[TKey, TValue]) chain() Operation[TKey, TValue] {
+	op, _ := c.operation.Load().(Operation[TKey, TValue])
+	return op
+}
+
+//operate performs the operation on the cache itself, at the end of the middleware chain
+func (c *Cache[TKey, TValue]) operate(op *Op[TKey, TValue]) (TValue, bool) {
+	var nilVal TValue
+
+	switch op.Kind {
+	case OpGet:
+		return c.get(op.Key)
+	case OpAdd:
+		op.entry = c.put(op.Key, op.Value)
+		return op.Value, true
+	case OpRemove:
+		c.erase(op.Key)
+		return nilVal, true
+	}
+
+	return nilVal, false
+}
+
+//------PUBLIC------
+
+//String returns the name of the operation kind
+func (k OpKind) String() string {
+	switch k {
+	case OpGet:
+		return "get"
+	case OpAdd:
+		return "add"
+	case OpRemove:
+		return "remove"
+	}
+
+	return "OpKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+//Use appends the middleware supplied to the middleware Get, Add and Remove go through. Middleware runs in the order it
+//was added, the first one seeing the operation first. Other methods bypass the middleware
+func (c *Cache[TKey, TValue]) Use(middleware ...Middleware[TKey, TValue]) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.middleware = append(c.middleware, middleware...)
+
+	op := Operation[TKey, TValue](c.operate)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		op = c.middleware[i](op)
+	}
+
+	c.operation.Store(op)
+}
@@ -0,0 +1,62 @@
+package cacheMachine
+
+import (
+	"strings"
+	"testing"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_Use(t *testing.T) {
+	c := New[string, int](nil)
+
+	var log []string
+	c.Use(func(next Operation[string, int]) Operation[string, int] {
+		return func(op *Op[string, int]) (int, bool) {
+			log = append(log, op.Kind.String()+" "+op.Key)
+			return next(op)
+		}
+	}, func(next Operation[string, int]) Operation[string, int] {
+		return func(op *Op[string, int]) (int, bool) {
+			op.Key = strings.ToLower(op.Key)
+			if op.Kind == OpAdd && op.Value < 0 {
+				return 0, false
+			}
+			return next(op)
+		}
+	})
+
+	if e := c.Add("A", 1); e == nil || e.Value() != 1 {
+		t.Errorf("Expected the entry added, got %v", e)
+	}
+
+	if e := c.Add("B", -1); e != nil {
+		t.Errorf("Expected the negative value to be refused, got %v", e)
+	}
+
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("Expected the key to be normalized, got %d and %t", v, ok)
+	}
+
+	c.Remove("A")
+
+	if c.Count() != 0 {
+		t.Errorf("Expected the normalized key to be removed, got %v", c.GetAll())
+	}
+
+	expected := "add A,add B,get a,remove A"
+	if got := strings.Join(log, ","); got != expected {
+		t.Errorf("Expected operations %q to go through the middleware, got %q", expected, got)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_Use(b *testing.B) {
+	c := initializeFullCache(100, nil)
+	c.Use(func(next Operation[int, int]) Operation[int, int] { return next })
+
+	for n := 0; n < b.N; n++ {
+		c.Get(n % 100)
+	}
+}
@@ -0,0 +1,101 @@
+package cacheMachine
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//Number of keys migrated under a single lock by MigratePolicy if Requirements.LockChunkSize is not set
+const defaultMigrationChunk = 1000
+
+//------PRIVATE------
+
+//startMigration makes the current eviction policy the one being drained and starts using policies created by the
+//constructor supplied. Keys are moved over by migrateChunk. This method has no mutex protection
+func (c *Cache[TKey, TValue]) startMigration(newPolicy func() EvictionPolicy[TKey]) {
+	//A migration still in progress is finished first, so that there is never more than one policy being drained
+	c.migrateChunk(-1)
+
+	c.drainLevels, c.drainPolicy = c.levels, c.newPolicy
+	c.levels, c.newPolicy = make(map[levelID]*level[TKey]), newPolicy
+	c.gen++
+}
+
+//migrateChunk moves at most n keys, or all of them if n is negative, from the policies being drained to the current
+//ones in the order the old policies would have evicted them. Returns true once there is nothing left to migrate.
+//This method has no mutex protection
+func (c *Cache[TKey, TValue]) migrateChunk(n int) bool {
+	moved := 0
+
+	for id, old := range c.drainLevels {
+		for n < 0 || moved < n {
+			key, ok := old.policy.Victim()
+			if !ok {
+				break
+			}
+
+			old.policy.OnRemove(key)
+			old.size--
+			moved++
+
+			if e, exist := c.data[key]; exist && e.gen != c.gen && c.levelOf(e) == id {
+				e.gen = c.gen
+				c.linkInto(c.levels, c.newPolicy, key, e)
+			}
+		}
+
+		if old.size < 1 {
+			delete(c.drainLevels, id)
+		}
+	}
+
+	if len(c.drainLevels) > 0 {
+		return false
+	}
+
+	c.drainLevels, c.drainPolicy = nil, nil
+
+	return true
+}
+
+//------PUBLIC------
+
+//MigratePolicy switches the eviction policy of a live cache to policies created by the constructor supplied and
+//sets MaxEntries to the limit supplied. Unlike SetEvictionPolicy, keys are handed over to the new policies and any
+//entries over the new limit are evicted in chunks of Requirements.LockChunkSize, or 1000 if it's not set, releasing
+//the lock in between, so the cache keeps serving requests during the migration. Until the migration completes,
+//eviction keeps following the order of the old policies. If nil is supplied, the built-in policy selected in
+//Requirements is used. The method returns once the migration completes
+func (c *Cache[TKey, TValue]) MigratePolicy(newPolicy func() EvictionPolicy[TKey], maxEntries int) {
+	c.migrateMx.Lock()
+	defer c.migrateMx.Unlock()
+
+	c.mx.Lock()
+
+	custom := newPolicy != nil
+	if !custom {
+		newPolicy = builtinPolicy[TKey](&c.cache.Requirements)
+	}
+
+	chunk := c.cache.Requirements.LockChunkSize
+	if chunk < 1 {
+		chunk = defaultMigrationChunk
+	}
+
+	if maxEntries > 0 {
+		c.track()
+	}
+
+	c.startMigration(newPolicy)
+	c.customPolicy = custom
+	c.cache.Requirements.MaxEntries = maxEntries
+	c.mx.Unlock()
+
+	for {
+		c.mx.Lock()
+		done := c.migrateChunk(chunk)
+		evicted := c.evictN(chunk)
+		c.mx.Unlock()
+
+		if done && evicted < chunk {
+			return
+		}
+	}
+}
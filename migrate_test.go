@@ -0,0 +1,72 @@
+package cacheMachine
+
+import (
+	"testing"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_MigratePolicy(t *testing.T) {
+	c := initializeFullCache(10, &Requirements{Policy: LRU, LockChunkSize: 3, MaxEntries: 100})
+
+	for i := 9; i >= 5; i-- {
+		c.Get(i)
+	}
+
+	c.MigratePolicy(NewFIFO[int], 5)
+
+	if cLen := c.Count(); cLen != 5 {
+		t.Errorf("Expected to have %d items after the migration, got %d", 5, cLen)
+	}
+
+	for i := 5; i < 10; i++ {
+		if !c.Exist(i) {
+			t.Errorf("Expected recently used key %d to survive the migration, but it was evicted", i)
+		}
+	}
+
+	if c.drainLevels != nil || c.Requirements().MaxEntries != 5 {
+		t.Errorf("Expected the migration to complete with the new limit of %d, got %d", 5, c.Requirements().MaxEntries)
+	}
+
+	//Keys were handed over in LRU order, so FIFO evicts the least recently used key first
+	c.Add(10, 10)
+
+	if c.Exist(9) || !c.Exist(5) {
+		t.Errorf("Expected key 9 to be evicted first after the migration, got 5 - %t, 9 - %t", c.Exist(5), c.Exist(9))
+	}
+}
+
+func TestCache_migrateChunk(t *testing.T) {
+	c := initializeFullCache(6, &Requirements{MaxEntries: 8})
+
+	c.mx.Lock()
+	c.startMigration(NewLRU[int])
+	c.migrateChunk(2)
+	c.mx.Unlock()
+
+	c.Add(6, 6)
+	c.Remove(0)
+	c.Remove(4)
+	c.Add(7, 7)
+	c.Add(8, 8)
+
+	c.mx.Lock()
+	done := c.migrateChunk(-1)
+	order := policyOrder(c.levels[levelID{}].policy)
+	c.mx.Unlock()
+
+	if !done || !equalOrder(order, []int{1, 2, 3, 5, 6, 7, 8}) {
+		t.Errorf("Expected keys to be migrated in their original order, got %v", order)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_MigratePolicy(b *testing.B) {
+	c := initializeFullCache(1000, nil)
+
+	for n := 0; n < b.N; n++ {
+		c.MigratePolicy(nil, 0)
+	}
+}
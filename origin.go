@@ -0,0 +1,43 @@
+package cacheMachine
+
+import (
+	"time"
+)
+
+//------PUBLIC------
+
+//AddWithOrigin does the same as method "Add" but also records the time as of which the value was current at its
+//source, e.g. when it was read from the database, so that MaxStaleness can track freshness of the cache
+func (c *Cache[TKey, TValue]) AddWithOrigin(key TKey, val TValue, origin time.Time) Entry[TValue] {
+	c.mx.Lock()
+	e := c.newEntry(key, val, 0, PriorityNormal)
+	e.origin = origin
+	c.insert(key, e)
+	c.mx.Unlock()
+
+	c.writeThrough(key, val)
+
+	return e
+}
+
+//MaxStaleness returns how long ago the oldest origin recorded by AddWithOrigin was, e.g. to alert when the cache
+//violates data freshness requirements. Entries without origin are not considered. Returns 0 if no entry has an
+//origin. It takes time proportional to the size of the cache
+func (c *Cache[TKey, TValue]) MaxStaleness() time.Duration {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	var oldest time.Time
+
+	for _, e := range c.data {
+		if !e.origin.IsZero() && (oldest.IsZero() || e.origin.Before(oldest)) && c.live(e) {
+			oldest = e.origin
+		}
+	}
+
+	if oldest.IsZero() {
+		return 0
+	}
+
+	return c.now().Sub(oldest)
+}
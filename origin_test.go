@@ -0,0 +1,58 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_AddWithOrigin(t *testing.T) {
+	c := New[int, int](nil)
+
+	if s := c.MaxStaleness(); s != 0 {
+		t.Errorf("Expected staleness 0 without origins, got %s", s)
+	}
+
+	now := time.Now()
+
+	c.Add(1, 1)
+	c.AddWithOrigin(2, 2, now.Add(-time.Minute))
+	c.AddWithOrigin(3, 3, now.Add(-time.Hour))
+
+	if s := c.MaxStaleness(); s < time.Hour || s > time.Hour+time.Minute {
+		t.Errorf("Expected staleness of around an hour, got %s", s)
+	}
+
+	c.Remove(3)
+
+	if s := c.MaxStaleness(); s < time.Minute || s > time.Minute*2 {
+		t.Errorf("Expected staleness of around a minute once the oldest entry is removed, got %s", s)
+	}
+}
+
+func TestCache_MaxStaleness_timeSource(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := New[int, int](&Requirements{TimeSource: clock})
+
+	c.AddWithOrigin(1, 1, clock.Now())
+	clock.Advance(time.Hour)
+
+	if s := c.MaxStaleness(); s != time.Hour {
+		t.Errorf("Expected staleness of an hour by the manual clock, got %s", s)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_MaxStaleness(b *testing.B) {
+	c := New[int, int](nil)
+
+	for i := 0; i < 100; i++ {
+		c.AddWithOrigin(i, i, time.Now())
+	}
+
+	for n := 0; n < b.N; n++ {
+		c.MaxStaleness()
+	}
+}
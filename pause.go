@@ -0,0 +1,78 @@
+package cacheMachine
+
+//------PUBLIC------
+
+//PauseExpiry freezes the timeouts of all the entries, e.g. while the source of the values is unavailable and they
+//can't be reloaded. Remaining durations are kept and the countdowns continue from them on ResumeExpiry. Entries
+//added while the expiry is paused start their countdown on ResumeExpiry as well. Sliding timeouts aren't restarted
+//by reads in the meantime, while timers reset, extended or touched during the pause, e.g. by Touch or
+//Entry.ResetTimer, only change the time remaining. Time buckets and MaxIdleTime are not paused
+func (c *Cache[TKey, TValue]) PauseExpiry() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.expiryPaused {
+		return
+	}
+	c.expiryPaused = true
+
+	now := c.now()
+
+	for _, e := range c.data {
+		e.mx.Lock()
+
+		if !e.deadline.IsZero() {
+			e.paused = e.deadline.Sub(now)
+			if e.paused < 1 {
+				e.paused = 1
+			}
+
+			ttl := e.ttl
+			e.resetTimer(0)
+			e.ttl = ttl
+		}
+		e.frozen = true
+
+		e.mx.Unlock()
+	}
+}
+
+//ResumeExpiry restarts the countdowns frozen by PauseExpiry from the durations that remained when they were paused
+func (c *Cache[TKey, TValue]) ResumeExpiry() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if !c.expiryPaused {
+		return
+	}
+	c.expiryPaused = false
+
+	now := c.now()
+
+	for key, e := range c.data {
+		e.mx.Lock()
+		e.frozen = false
+
+		if d := e.paused; d > 0 {
+			e.paused = 0
+
+			if e.timer != nil {
+				if !e.timer.Reset(d) {
+					e.countTimer(1)
+				}
+				e.deadline = now.Add(d)
+			} else if c.startTimer(key, e, d) {
+				e.deadline = now.Add(d)
+			}
+		}
+
+		e.mx.Unlock()
+	}
+}
+
+//ExpiryPaused returns whether the timeouts of entries are frozen by PauseExpiry
+func (c *Cache[TKey, TValue]) ExpiryPaused() bool {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	return c.expiryPaused
+}
@@ -0,0 +1,122 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_PauseExpiry(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := New[int, int](&Requirements{TimeSource: clock})
+
+	c.AddWithTimeout(1, 1, time.Minute)
+	clock.Advance(time.Second * 20)
+
+	c.PauseExpiry()
+	c.AddWithTimeout(2, 2, time.Minute)
+
+	if !c.ExpiryPaused() {
+		t.Errorf("Expected expiry to be reported as paused")
+	}
+
+	clock.Advance(time.Hour)
+
+	if !c.Exist(1) || !c.Exist(2) {
+		t.Fatalf("Expected no entries to expire while expiry is paused, got %v", c.GetAll())
+	}
+
+	c.ResumeExpiry()
+
+	if ttl := c.GetEntry(1).TTL(); ttl != time.Second*40 {
+		t.Errorf("Expected the countdown to continue from the remaining 40s, got %s", ttl)
+	}
+
+	clock.Advance(time.Second * 40)
+
+	if c.Exist(1) || !c.Exist(2) {
+		t.Errorf("Expected only the entry added before the pause to expire after its remaining time")
+	}
+
+	clock.Advance(time.Second * 20)
+
+	if c.Exist(2) {
+		t.Errorf("Expected the entry added during the pause to expire a minute after resuming")
+	}
+}
+
+func TestCache_PauseExpiry_timerChanges(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := New[int, int](&Requirements{TimeSource: clock})
+
+	for i := 1; i <= 4; i++ {
+		c.AddWithTimeout(i, i, time.Minute)
+	}
+
+	c.PauseExpiry()
+	c.GetEntry(2).ResetTimer(time.Second * 10)
+	c.ExtendTimer(3, time.Minute)
+	c.GetEntry(4).SetDeadline(clock.Now().Add(time.Second * 20))
+
+	clock.Advance(time.Hour)
+
+	if c.Count() != 4 {
+		t.Fatalf("Expected no entries to expire while expiry is paused, got %v", c.GetAll())
+	}
+
+	c.ResumeExpiry()
+
+	for key, ttl := range map[int]time.Duration{1: time.Minute, 2: time.Second * 10, 3: time.Minute * 2, 4: time.Second * 20} {
+		if got := c.GetEntry(key).TTL(); got != ttl {
+			t.Errorf("Expected key %d to resume with %s remaining, got %s", key, ttl, got)
+		}
+	}
+
+	clock.Advance(time.Second * 10)
+
+	if c.Exist(2) || !c.Exist(1) {
+		t.Errorf("Expected only the entry reset to 10s to expire 10s after resuming")
+	}
+}
+
+func TestCache_PauseExpiry_touch(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := New[int, int](&Requirements{TimeSource: clock})
+
+	c.AddWithTimeout(1, 1, time.Minute)
+	c.AddWithTimeout(2, 2, time.Minute)
+	clock.Advance(time.Second * 40)
+
+	c.PauseExpiry()
+	c.Touch(1)
+	c.Touch(2, time.Second*5)
+	clock.Advance(time.Hour)
+	c.ResumeExpiry()
+
+	if ttl := c.GetEntry(1).TTL(); ttl != time.Minute {
+		t.Errorf("Expected the entry touched during the pause to resume with the full %s, got %s", time.Minute, ttl)
+	}
+
+	if ttl := c.GetEntry(2).TTL(); ttl != time.Second*5 {
+		t.Errorf("Expected the entry touched during the pause to resume with %s supplied, got %s", time.Second*5, ttl)
+	}
+}
+
+func TestCache_PauseExpiry_sliding(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	c := New[int, int](&Requirements{TimeSource: clock, SlidingTimeout: true})
+
+	c.AddWithTimeout(1, 1, time.Minute)
+	clock.Advance(time.Second * 30)
+
+	c.PauseExpiry()
+	c.Get(1)
+	c.ResumeExpiry()
+
+	clock.Advance(time.Second * 30)
+
+	if c.Exist(1) {
+		t.Errorf("Expected reads during the pause not to restart the sliding timeout")
+	}
+}
@@ -0,0 +1,65 @@
+package cacheMachine
+
+//------PRIVATE------
+
+//setPinned pins or unpins the entry, if the key still holds it. Entries unpinned after their timeout or time bucket
+//have passed expire straight away
+func (c *Cache[TKey, TValue]) setPinned(key TKey, e *entry[TValue], pinned bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if current, exist := c.data[key]; !exist || current != e || e.pinned == pinned {
+		return
+	}
+
+	if pinned {
+		c.unlink(key, e)
+		e.pinned = true
+		c.pinned++
+		return
+	}
+
+	e.pinned = false
+	c.pinned--
+
+	if e.expired() || !e.bucket.IsZero() && !c.now().Before(e.bucket) {
+		c.remove(key)
+		c.emit(EventExpire, key, e)
+		return
+	}
+
+	c.link(key, e)
+	c.evict()
+}
+
+//------PUBLIC------
+
+//Pin protects the entry of the key from eviction and expiry, e.g. for configuration that must never be lost. Pinned
+//entries can still be removed explicitly and stay pinned when their value is replaced. Pinned entries don't count
+//as candidates for eviction, so the cache can't shrink below their number and cost. Returns false if the key is
+//not present
+func (c *Cache[TKey, TValue]) Pin(key TKey) bool {
+	c.mx.RLock()
+	e, exist := c.data[key]
+	c.mx.RUnlock()
+
+	if exist {
+		c.setPinned(key, e, true)
+	}
+
+	return exist
+}
+
+//Unpin makes the entry of the key subject to eviction and expiry again. The eviction policy treats the entry as if
+//it was just added. Returns false if the key is not present
+func (c *Cache[TKey, TValue]) Unpin(key TKey) bool {
+	c.mx.RLock()
+	e, exist := c.data[key]
+	c.mx.RUnlock()
+
+	if exist {
+		c.setPinned(key, e, false)
+	}
+
+	return exist
+}
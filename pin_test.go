@@ -0,0 +1,76 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_Pin(t *testing.T) {
+	c := New[int, int](&Requirements{MaxEntries: 3})
+
+	c.Add(1, 1)
+
+	if !c.Pin(1) || c.Pin(100) {
+		t.Errorf("Expected only present keys to be pinned")
+	}
+
+	for i := 2; i <= 10; i++ {
+		c.Add(i, i)
+	}
+
+	c.Add(1, 11)
+
+	for i := 11; i <= 20; i++ {
+		c.Add(i, i)
+	}
+
+	if c.GetValue(1) != 11 || c.Count() != 3 {
+		t.Errorf("Expected pinned key to survive eviction and replacement, got value %d and %d entries", c.GetValue(1), c.Count())
+	}
+
+	if err := c.Healthy(); err != nil {
+		t.Errorf("Expected the cache to be healthy, got %v", err)
+	}
+
+	//Unpinned key joins the eviction policy as if it was just added
+	c.Unpin(1)
+	for i := 21; i <= 23; i++ {
+		c.Add(i, i)
+	}
+
+	if c.Exist(1) {
+		t.Errorf("Expected unpinned key to be evicted")
+	}
+}
+
+func TestEntry_Pin(t *testing.T) {
+	c := New[int, int](nil)
+
+	e := c.AddWithTimeout(1, 1, time.Millisecond*10)
+	e.Pin()
+
+	time.Sleep(time.Millisecond * 50)
+
+	if !c.Exist(1) {
+		t.Fatalf("Expected pinned key not to expire")
+	}
+
+	e.Unpin()
+
+	if c.Exist(1) {
+		t.Errorf("Expected key to expire once unpinned, since its timeout has passed")
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_Pin(b *testing.B) {
+	c := initializeFullCache(100, nil)
+
+	for n := 0; n < b.N; n++ {
+		c.Pin(n % 100)
+		c.Unpin(n % 100)
+	}
+}
@@ -0,0 +1,21 @@
+package cacheMachine
+
+//------PRIVATE------
+
+//postProcess applies the function set by SetPostLoad to the value loaded, if there is one
+func (c *Cache[TKey, TValue]) postProcess(key TKey, val TValue) TValue {
+	if f, _ := c.postLoad.Load().(func(TKey, TValue) TValue); f != nil {
+		return f(key, val)
+	}
+
+	return val
+}
+
+//------PUBLIC------
+
+//SetPostLoad sets the function applied to every value loaded by GetOrLoad, GetBulkOrLoad and views before it's
+//stored, e.g. to strip fields that are not needed, so that it doesn't have to be done in every loader. The value
+//returned by the function is both stored and returned to the caller. Nil stops post-processing
+func (c *Cache[TKey, TValue]) SetPostLoad(f func(TKey, TValue) TValue) {
+	c.postLoad.Store(f)
+}
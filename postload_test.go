@@ -0,0 +1,56 @@
+package cacheMachine
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_SetPostLoad(t *testing.T) {
+	c := New[string, string](nil)
+	c.SetPostLoad(func(key, val string) string { return strings.TrimSpace(val) })
+
+	loader := func(ctx context.Context, key string) (string, error) { return "  " + key + "  ", nil }
+
+	if v, err := c.GetOrLoad(context.Background(), "a", loader); err != nil || v != "a" {
+		t.Errorf("Expected to get post-processed value %q, got %q and error %v", "a", v, err)
+	}
+
+	if v := c.GetValue("a"); v != "a" {
+		t.Errorf("Expected post-processed value %q to be stored, got %q", "a", v)
+	}
+
+	bulkLoader := func(ctx context.Context, keys []string) (map[string]string, error) {
+		return map[string]string{"b": " b "}, nil
+	}
+
+	if d, err := c.GetBulkOrLoad(context.Background(), []string{"b"}, bulkLoader); err != nil || d["b"] != "b" || c.GetValue("b") != "b" {
+		t.Errorf("Expected bulk loaded value to be post-processed, got %q and error %v", d["b"], err)
+	}
+
+	c.SetPostLoad(nil)
+	c.Add("c", " c ")
+
+	if v, _ := c.GetOrLoad(context.Background(), "d", loader); v != "  d  " {
+		t.Errorf("Expected no post-processing once it's reset, got %q", v)
+	}
+
+	if v := c.GetValue("c"); v != " c " {
+		t.Errorf("Expected values added directly not to be post-processed, got %q", v)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_SetPostLoad(b *testing.B) {
+	c := New[int, int](nil)
+	c.SetPostLoad(func(key, val int) int { return val * 2 })
+
+	loader := func(ctx context.Context, key int) (int, error) { return key, nil }
+
+	for n := 0; n < b.N; n++ {
+		c.GetOrLoad(context.Background(), n, loader)
+	}
+}
@@ -0,0 +1,164 @@
+package cacheMachine
+
+import (
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//Defaults of MemoryPressure used when its fields are left zero
+const (
+	defaultPressureInterval = time.Second
+	defaultPressureShrink   = 0.1
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//MemoryPressure configures the watcher started by WatchMemory
+type MemoryPressure struct {
+	//Heap size in bytes, as reported by runtime.MemStats.HeapAlloc, above which the cache is shrunk. 0 means the
+	//heap is not checked and the cache is shrunk on Signal only
+	HeapLimit uint64
+
+	//How often the heap is checked. Defaults to 1 second. Reading runtime.MemStats stops the world briefly, so
+	//checking very often is not free
+	Interval time.Duration
+
+	//Fraction of the entries evicted every time the pressure is detected. Defaults to 0.1
+	Shrink float64
+
+	//If this is set, the cache is also shrunk every time a value is received, e.g. from a cgroup memory notifier
+	Signal <-chan struct{}
+}
+
+//------PRIVATE------
+
+//evictUpTo evicts at most n entries chosen by the eviction policy. Returns number of entries evicted. This method has
+//no mutex protection
+func (c *Cache[TKey, TValue]) evictUpTo(n int) int {
+	removed := 0
+
+	for removed < n && c.evictVictim() {
+		removed++
+	}
+
+	return removed
+}
+
+//------PUBLIC------
+
+//EvictN evicts n entries chosen by the eviction policy the same way as when MaxEntries is exceeded, e.g. to trim the
+//cache before taking a heap snapshot. Pinned entries are never evicted. Returns number of entries evicted, which is
+//less than n if the cache runs out of entries that can be evicted
+func (c *Cache[TKey, TValue]) EvictN(n int) int {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.evictUpTo(n)
+}
+
+//Shrink evicts the fraction of the entries specified, chosen by the eviction policy the same way as when MaxEntries
+//is exceeded. Pinned entries are never evicted. Returns number of entries evicted
+func (c *Cache[TKey, TValue]) Shrink(fraction float64) int {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.evictUpTo(int(float64(len(c.data)) * fraction))
+}
+
+//EvictFraction evicts the fraction of the entries specified picked at random within the lowest priority levels,
+//without consulting the eviction policy, e.g. for emergency relief when the memory runs out. Entries with lower priority
+//are still evicted first and pinned entries are never evicted. Returns number of entries evicted
+func (c *Cache[TKey, TValue]) EvictFraction(f float64) int {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	n := int(float64(len(c.data)) * f)
+	if n < 1 {
+		return 0
+	}
+
+	keys := make(map[Priority][]TKey)
+	for key, e := range c.data {
+		if !e.pinned {
+			keys[e.priority] = append(keys[e.priority], key)
+		}
+	}
+
+	priorities := make([]Priority, 0, len(keys))
+	for p := range keys {
+		priorities = append(priorities, p)
+	}
+	sort.Slice(priorities, func(i, j int) bool { return priorities[i] < priorities[j] })
+
+	removed := 0
+
+	for _, p := range priorities {
+		level := keys[p]
+		rand.Shuffle(len(level), func(i, j int) { level[i], level[j] = level[j], level[i] })
+
+		for _, key := range level {
+			if removed == n {
+				return removed
+			}
+
+			//Evicting a key of a group removes the rest of the group along with it
+			if _, exist := c.data[key]; !exist {
+				continue
+			}
+
+			c.evictKey(key)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+//WatchMemory starts a goroutine shrinking the cache whenever the heap grows past MemoryPressure.HeapLimit or
+//MemoryPressure.Signal fires, e.g. in containers with tight memory limits. Call the function returned to stop it,
+//calling it again has no effect
+func (c *Cache[TKey, TValue]) WatchMemory(p MemoryPressure) (stop func()) {
+	if p.Interval <= 0 {
+		p.Interval = defaultPressureInterval
+	}
+
+	if p.Shrink <= 0 {
+		p.Shrink = defaultPressureShrink
+	}
+
+	done := make(chan struct{})
+	once := sync.Once{}
+
+	go func() {
+		ticker := time.NewTicker(p.Interval)
+		defer ticker.Stop()
+
+		stats := runtime.MemStats{}
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-c.closed:
+				return
+			case <-p.Signal:
+				c.Shrink(p.Shrink)
+			case <-ticker.C:
+				if p.HeapLimit < 1 {
+					continue
+				}
+
+				if runtime.ReadMemStats(&stats); stats.HeapAlloc > p.HeapLimit {
+					c.Shrink(p.Shrink)
+				}
+			}
+		}
+	}()
+
+	return func() { once.Do(func() { close(done) }) }
+}
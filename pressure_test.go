@@ -0,0 +1,96 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_EvictN(t *testing.T) {
+	c := initializeFullCache(10, &Requirements{Policy: LRU, MaxEntries: 100})
+	c.Get(0)
+	c.Pin(1)
+
+	if n := c.EvictN(3); n != 3 || c.Count() != 7 || !c.Exist(0) || !c.Exist(1) {
+		t.Errorf("Expected 3 entries evicted, sparing keys 0 and 1, got %d evicted and %d left", n, c.Count())
+	}
+
+	if n := c.EvictN(100); n != 6 || c.Count() != 1 {
+		t.Errorf("Expected all but the pinned entry evicted, got %d evicted and %d left", n, c.Count())
+	}
+}
+
+func TestCache_Shrink(t *testing.T) {
+	c := initializeFullCache(100, nil)
+	c.Pin(0)
+
+	if n := c.Shrink(0.25); n != 25 || c.Count() != 75 {
+		t.Errorf("Expected 25 entries to be evicted, got %d evicted and %d left", n, c.Count())
+	}
+
+	if !c.Exist(0) || c.Exist(1) {
+		t.Errorf("Expected the oldest unpinned entries to be evicted")
+	}
+}
+
+func TestCache_EvictFraction(t *testing.T) {
+	c := initializeFullCache(10, nil)
+	c.Pin(0)
+
+	for i := 10; i < 20; i++ {
+		c.AddWithPriority(i, i, PriorityLow)
+	}
+
+	if n := c.EvictFraction(0.5); n != 10 || c.Count() != 10 {
+		t.Errorf("Expected 10 entries to be evicted, got %d evicted and %d left", n, c.Count())
+	}
+
+	for i := 10; i < 20; i++ {
+		if c.Exist(i) {
+			t.Errorf("Expected entry %d of low priority to be evicted first", i)
+		}
+	}
+
+	if n := c.EvictFraction(1); n != 9 || !c.Exist(0) {
+		t.Errorf("Expected all but the pinned entry to be evicted, got %d evicted and %d left", n, c.Count())
+	}
+}
+
+func TestCache_WatchMemory(t *testing.T) {
+	c := initializeFullCache(100, nil)
+	signal := make(chan struct{})
+
+	stop := c.WatchMemory(MemoryPressure{Signal: signal, Shrink: 0.5})
+	signal <- struct{}{}
+	stop()
+
+	//The signal has been received before the stop, so the shrinking completes regardless
+	time.Sleep(time.Millisecond * 20)
+
+	if c.Count() != 50 {
+		t.Errorf("Expected the cache to shrink to %d entries on signal, got %d", 50, c.Count())
+	}
+
+	c2 := initializeFullCache(100, nil)
+
+	stop = c2.WatchMemory(MemoryPressure{HeapLimit: 1, Interval: time.Millisecond * 10})
+	defer stop()
+	time.Sleep(time.Millisecond * 35)
+	stop()
+
+	if c2.Count() >= 100 {
+		t.Errorf("Expected the cache to shrink once the heap grew past the limit, got %d entries", c2.Count())
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_Shrink(b *testing.B) {
+	c := initializeFullCache(1000, nil)
+
+	for n := 0; n < b.N; n++ {
+		c.Add(n, n)
+		c.Shrink(0.001)
+	}
+}
@@ -0,0 +1,628 @@
+package cacheMachine
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//Types of values within Redis DUMP payloads that can be imported
+const (
+	redisTypeString      = 0
+	redisTypeHash        = 4
+	redisTypeHashZiplist = 13
+	redisTypeHashLPack   = 16
+)
+
+//Limits of RESP commands read by ImportRESP, the same as the defaults of Redis: number of arguments of a single
+//command and length of a single argument, as proto-max-bulk-len
+const (
+	maxRESPArgs    = 1024 * 1024
+	maxRESPBulkLen = 512 * 1024 * 1024
+)
+
+//Longest output of LZF per byte of its input, reached by back references of 3 bytes copying 264 bytes each
+const lzfMaxRatio = 88
+
+//ErrUnsupportedRedisType is returned when the Redis DUMP payload holds a type of value that can not be imported
+var ErrUnsupportedRedisType = errors.New("cacheMachine: unsupported redis value type")
+
+//ErrCorruptRedisPayload is returned when the Redis DUMP payload is malformed or doesn't match its checksum
+var ErrCorruptRedisPayload = errors.New("cacheMachine: corrupt redis payload")
+
+//Lookup table of CRC-64/Jones in reflected form, the checksum used by Redis in DUMP payloads
+var redisCRC64Table = func() [256]uint64 {
+	var t [256]uint64
+
+	for i := range t {
+		crc := uint64(i)
+		for j := 0; j < 8; j++ {
+			if crc&1 == 1 {
+				crc = crc>>1 ^ 0x95AC9329AC4BC9B5
+			} else {
+				crc >>= 1
+			}
+		}
+		t[i] = crc
+	}
+
+	return t
+}()
+
+//===========[STRUCTS]==================================================================================================
+
+//RedisImportOptions defines how Redis values are imported into the cache
+type RedisImportOptions struct {
+	//Builds the cache key for a field of Redis hash. By default, key and field are joined with ":"
+	HashKey func(key, field string) string
+}
+
+//Reader of the values encoded in Redis RDB format
+type rdbReader struct {
+	b   []byte
+	pos int
+}
+
+//------PRIVATE------
+
+//byte reads a single byte
+func (r *rdbReader) byte() (byte, error) {
+	if r.pos >= len(r.b) {
+		return 0, ErrCorruptRedisPayload
+	}
+
+	r.pos++
+	return r.b[r.pos-1], nil
+}
+
+//bytes reads n bytes
+func (r *rdbReader) bytes(n int) ([]byte, error) {
+	if n < 0 || n > len(r.b)-r.pos {
+		return nil, ErrCorruptRedisPayload
+	}
+
+	r.pos += n
+	return r.b[r.pos-n : r.pos], nil
+}
+
+//length reads RDB length encoding. If encoded is true, the length is instead the type of special string encoding.
+//Lengths that don't fit into int are refused
+func (r *rdbReader) length() (n int, encoded bool, err error) {
+	b, err := r.byte()
+	if err != nil {
+		return 0, false, err
+	}
+
+	switch b >> 6 {
+	case 0:
+		return int(b & 0x3f), false, nil
+	case 1:
+		next, err := r.byte()
+		return int(b&0x3f)<<8 | int(next), false, err
+	case 3:
+		return int(b & 0x3f), true, nil
+	}
+
+	switch b {
+	case 0x80:
+		d, err := r.bytes(4)
+		if err != nil {
+			return 0, false, err
+		}
+		if n := int(binary.BigEndian.Uint32(d)); n >= 0 {
+			return n, false, nil
+		}
+	case 0x81:
+		d, err := r.bytes(8)
+		if err != nil {
+			return 0, false, err
+		}
+		if n := int(binary.BigEndian.Uint64(d)); n >= 0 {
+			return n, false, nil
+		}
+	}
+
+	return 0, false, ErrCorruptRedisPayload
+}
+
+//string reads RDB string, decoding integer and LZF compressed encodings
+func (r *rdbReader) string() ([]byte, error) {
+	n, encoded, err := r.length()
+	if err != nil {
+		return nil, err
+	}
+
+	if !encoded {
+		return r.bytes(n)
+	}
+
+	switch n {
+	case 0:
+		d, err := r.bytes(1)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(strconv.Itoa(int(int8(d[0])))), nil
+	case 1:
+		d, err := r.bytes(2)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(strconv.Itoa(int(int16(binary.LittleEndian.Uint16(d))))), nil
+	case 2:
+		d, err := r.bytes(4)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(strconv.Itoa(int(int32(binary.LittleEndian.Uint32(d))))), nil
+	case 3:
+		clen, _, err := r.length()
+		if err != nil {
+			return nil, err
+		}
+
+		ulen, _, err := r.length()
+		if err != nil {
+			return nil, err
+		}
+
+		d, err := r.bytes(clen)
+		if err != nil {
+			return nil, err
+		}
+
+		return lzfDecompress(d, ulen)
+	}
+
+	return nil, ErrCorruptRedisPayload
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//redisCRC64 calculates CRC-64/Jones checksum the same way Redis does, without initial and final inversion
+func redisCRC64(crc uint64, b []byte) uint64 {
+	for _, c := range b {
+		crc = redisCRC64Table[byte(crc)^c] ^ crc>>8
+	}
+
+	return crc
+}
+
+//lzfDecompress decompresses LZF compressed data used by Redis for long strings. Lengths that the input couldn't
+//possibly decompress to are refused before anything is allocated
+func lzfDecompress(in []byte, ulen int) ([]byte, error) {
+	if ulen < 0 || ulen > len(in)*lzfMaxRatio {
+		return nil, ErrCorruptRedisPayload
+	}
+
+	out := make([]byte, 0, ulen)
+
+	for ip := 0; ip < len(in); {
+		ctrl := int(in[ip])
+		ip++
+
+		if ctrl < 32 {
+			ctrl++
+			if ip+ctrl > len(in) || len(out)+ctrl > ulen {
+				return nil, ErrCorruptRedisPayload
+			}
+			out = append(out, in[ip:ip+ctrl]...)
+			ip += ctrl
+			continue
+		}
+
+		l := ctrl >> 5
+		if l == 7 {
+			if ip >= len(in) {
+				return nil, ErrCorruptRedisPayload
+			}
+			l += int(in[ip])
+			ip++
+		}
+
+		if ip >= len(in) {
+			return nil, ErrCorruptRedisPayload
+		}
+
+		ref := len(out) - (ctrl&0x1f)<<8 - 1 - int(in[ip])
+		ip++
+
+		if ref < 0 || len(out)+l+2 > ulen {
+			return nil, ErrCorruptRedisPayload
+		}
+
+		for i := 0; i < l+2; i++ {
+			out = append(out, out[ref+i])
+		}
+	}
+
+	if len(out) != ulen {
+		return nil, ErrCorruptRedisPayload
+	}
+
+	return out, nil
+}
+
+//ziplistEntries decodes all the entries of the ziplist used by older Redis versions to encode small hashes
+func ziplistEntries(b []byte) ([][]byte, error) {
+	if len(b) < 11 {
+		return nil, ErrCorruptRedisPayload
+	}
+
+	var entries [][]byte
+	r := rdbReader{b: b, pos: 10}
+
+	for {
+		prev, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+
+		if prev == 0xff {
+			return entries, nil
+		}
+
+		if prev == 0xfe {
+			if _, err := r.bytes(4); err != nil {
+				return nil, err
+			}
+		}
+
+		enc, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+
+		var v []byte
+
+		switch {
+		case enc>>6 == 0:
+			v, err = r.bytes(int(enc & 0x3f))
+		case enc>>6 == 1:
+			var next byte
+			if next, err = r.byte(); err == nil {
+				v, err = r.bytes(int(enc&0x3f)<<8 | int(next))
+			}
+		case enc == 0x80:
+			var d []byte
+			if d, err = r.bytes(4); err == nil {
+				v, err = r.bytes(int(binary.BigEndian.Uint32(d)))
+			}
+		case enc == 0xc0:
+			v, err = littleEndianInt(&r, 2)
+		case enc == 0xd0:
+			v, err = littleEndianInt(&r, 4)
+		case enc == 0xe0:
+			v, err = littleEndianInt(&r, 8)
+		case enc == 0xf0:
+			v, err = littleEndianInt(&r, 3)
+		case enc == 0xfe:
+			v, err = littleEndianInt(&r, 1)
+		case enc > 0xf0 && enc < 0xfe:
+			v = []byte(strconv.Itoa(int(enc&0x0f) - 1))
+		default:
+			err = ErrCorruptRedisPayload
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, v)
+	}
+}
+
+//listpackEntries decodes all the entries of the listpack used by newer Redis versions to encode small hashes
+func listpackEntries(b []byte) ([][]byte, error) {
+	if len(b) < 7 {
+		return nil, ErrCorruptRedisPayload
+	}
+
+	var entries [][]byte
+	r := rdbReader{b: b, pos: 6}
+
+	for {
+		start := r.pos
+
+		enc, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+
+		if enc == 0xff {
+			return entries, nil
+		}
+
+		var v []byte
+
+		switch {
+		case enc>>7 == 0:
+			v = []byte(strconv.Itoa(int(enc)))
+		case enc>>6 == 2:
+			v, err = r.bytes(int(enc & 0x3f))
+		case enc>>5 == 6:
+			var next byte
+			if next, err = r.byte(); err == nil {
+				n := int(enc&0x1f)<<8 | int(next)
+				if n >= 1<<12 {
+					n -= 1 << 13
+				}
+				v = []byte(strconv.Itoa(n))
+			}
+		case enc>>4 == 14:
+			var next byte
+			if next, err = r.byte(); err == nil {
+				v, err = r.bytes(int(enc&0x0f)<<8 | int(next))
+			}
+		case enc == 0xf0:
+			var d []byte
+			if d, err = r.bytes(4); err == nil {
+				v, err = r.bytes(int(binary.LittleEndian.Uint32(d)))
+			}
+		case enc == 0xf1:
+			v, err = littleEndianInt(&r, 2)
+		case enc == 0xf2:
+			v, err = littleEndianInt(&r, 3)
+		case enc == 0xf3:
+			v, err = littleEndianInt(&r, 4)
+		case enc == 0xf4:
+			v, err = littleEndianInt(&r, 8)
+		default:
+			err = ErrCorruptRedisPayload
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		//Skipping the back-length of the entry
+		l := r.pos - start
+		backlen := 1
+		for l >= 128 {
+			l >>= 7
+			backlen++
+		}
+
+		if _, err := r.bytes(backlen); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, v)
+	}
+}
+
+//littleEndianInt reads signed little endian integer of n bytes and returns it as a decimal string
+func littleEndianInt(r *rdbReader, n int) ([]byte, error) {
+	d, err := r.bytes(n)
+	if err != nil {
+		return nil, err
+	}
+
+	var u uint64
+	for i := n - 1; i >= 0; i-- {
+		u = u<<8 | uint64(d[i])
+	}
+
+	//Sign extension
+	shift := uint(64 - n*8)
+	return []byte(strconv.FormatInt(int64(u<<shift)>>shift, 10)), nil
+}
+
+//decodeRedisDump decodes Redis DUMP payload into the values it holds. Strings are returned under an empty field,
+//hashes are returned as field:value pairs
+func decodeRedisDump(payload []byte) (map[string][]byte, bool, error) {
+	if len(payload) < 11 {
+		return nil, false, ErrCorruptRedisPayload
+	}
+
+	body := payload[:len(payload)-8]
+
+	if redisCRC64(0, body) != binary.LittleEndian.Uint64(payload[len(payload)-8:]) {
+		return nil, false, ErrCorruptRedisPayload
+	}
+
+	r := rdbReader{b: body[:len(body)-2]}
+
+	t, err := r.byte()
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch t {
+	case redisTypeString:
+		v, err := r.string()
+		if err != nil {
+			return nil, false, err
+		}
+		return map[string][]byte{"": v}, false, nil
+	case redisTypeHash:
+		n, _, err := r.length()
+		if err != nil {
+			return nil, true, err
+		}
+
+		//Every field takes at least 2 bytes, the lengths of its name and value
+		if n > (len(r.b)-r.pos)/2 {
+			return nil, true, ErrCorruptRedisPayload
+		}
+
+		fields := make(map[string][]byte, n)
+		for i := 0; i < n; i++ {
+			f, err := r.string()
+			if err != nil {
+				return nil, true, err
+			}
+
+			v, err := r.string()
+			if err != nil {
+				return nil, true, err
+			}
+
+			fields[string(f)] = v
+		}
+		return fields, true, nil
+	case redisTypeHashZiplist, redisTypeHashLPack:
+		b, err := r.string()
+		if err != nil {
+			return nil, true, err
+		}
+
+		var entries [][]byte
+		if t == redisTypeHashZiplist {
+			entries, err = ziplistEntries(b)
+		} else {
+			entries, err = listpackEntries(b)
+		}
+
+		if err != nil {
+			return nil, true, err
+		}
+
+		if len(entries)%2 != 0 {
+			return nil, true, ErrCorruptRedisPayload
+		}
+
+		fields := make(map[string][]byte, len(entries)/2)
+		for i := 0; i < len(entries); i += 2 {
+			fields[string(entries[i])] = entries[i+1]
+		}
+		return fields, true, nil
+	}
+
+	return nil, false, fmt.Errorf("%w: %d", ErrUnsupportedRedisType, t)
+}
+
+//readRESPCommand reads a single command sent as RESP array of bulk strings. Counts and lengths that are negative or
+//past the limits of Redis are refused, and memory for arguments is only allocated as their bytes arrive
+func readRESPCommand(r *bufio.Reader) ([][]byte, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	line = strings.TrimRight(line, "\r\n")
+	if len(line) < 1 || line[0] != '*' {
+		return nil, ErrCorruptRedisPayload
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 0 || n > maxRESPArgs {
+		return nil, ErrCorruptRedisPayload
+	}
+
+	var args [][]byte
+
+	for i := 0; i < n; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if len(line) < 1 || line[0] != '$' {
+			return nil, ErrCorruptRedisPayload
+		}
+
+		l, err := strconv.Atoi(line[1:])
+		if err != nil || l < 0 || l > maxRESPBulkLen {
+			return nil, ErrCorruptRedisPayload
+		}
+
+		arg, err := io.ReadAll(io.LimitReader(r, int64(l)+2))
+		if err != nil || len(arg) < l+2 {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		args = append(args, arg[:l])
+	}
+
+	return args, nil
+}
+
+//ImportRedisDump decodes the payload produced by Redis DUMP command and adds it to the cache under the key provided.
+//Strings are added as they are, while every field of a hash is added as a separate entry with the key built by
+//RedisImportOptions.HashKey. If ttl is not 0, entries are added with this timeout
+func ImportRedisDump(c *Cache[string, []byte], key string, payload []byte, ttl time.Duration, opts *RedisImportOptions) error {
+	values, hash, err := decodeRedisDump(payload)
+	if err != nil {
+		return err
+	}
+
+	hashKey := func(key, field string) string { return key + ":" + field }
+	if opts != nil && opts.HashKey != nil {
+		hashKey = opts.HashKey
+	}
+
+	for field, v := range values {
+		k := key
+		if hash {
+			k = hashKey(key, field)
+		}
+
+		if ttl > 0 {
+			c.AddWithTimeout(k, v, ttl)
+		} else {
+			c.Add(k, v)
+		}
+	}
+
+	return nil
+}
+
+//ImportRESP reads RESP-encoded commands, such as the stream of RESTORE commands sent by Redis MIGRATE, and imports
+//the payloads of all RESTORE commands into the cache. Other commands are ignored. Returns number of keys imported
+func ImportRESP(c *Cache[string, []byte], r io.Reader, opts *RedisImportOptions) (int, error) {
+	br := bufio.NewReader(r)
+	imported := 0
+
+	for {
+		args, err := readRESPCommand(br)
+		if err == io.EOF {
+			return imported, nil
+		}
+
+		if err != nil {
+			return imported, err
+		}
+
+		if len(args) < 4 {
+			continue
+		}
+
+		cmd := strings.ToUpper(string(args[0]))
+		if cmd != "RESTORE" && cmd != "RESTORE-ASKING" {
+			continue
+		}
+
+		ms, err := strconv.ParseInt(string(args[2]), 10, 64)
+		if err != nil {
+			return imported, ErrCorruptRedisPayload
+		}
+
+		ttl := time.Duration(ms) * time.Millisecond
+
+		for _, opt := range args[4:] {
+			if strings.EqualFold(string(opt), "ABSTTL") && ms > 0 {
+				ttl = time.Until(time.UnixMilli(ms))
+			}
+		}
+
+		//Key has already expired
+		if ms > 0 && ttl <= 0 {
+			continue
+		}
+
+		if err := ImportRedisDump(c, string(args[1]), args[3], ttl, opts); err != nil {
+			return imported, err
+		}
+
+		imported++
+	}
+}
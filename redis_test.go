@@ -0,0 +1,186 @@
+package cacheMachine
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//redisDump builds DUMP payload of the type specified the same way Redis does
+func redisDump(t byte, value []byte) []byte {
+	payload := append([]byte{t}, value...)
+	payload = append(payload, 10, 0)
+
+	crc := make([]byte, 8)
+	binary.LittleEndian.PutUint64(crc, redisCRC64(0, payload))
+
+	return append(payload, crc...)
+}
+
+//respCommand encodes the arguments as RESP array of bulk strings
+func respCommand(args ...[]byte) []byte {
+	buf := bytes.Buffer{}
+	fmt.Fprintf(&buf, "*%d\r\n", len(args))
+
+	for _, arg := range args {
+		fmt.Fprintf(&buf, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	return buf.Bytes()
+}
+
+//===========[TESTING]====================================================================================================
+
+func TestRedisCRC64(t *testing.T) {
+	if crc := redisCRC64(0, []byte("123456789")); crc != 0xe9c6d914c4b8d9ca {
+		t.Errorf("Expected checksum %x, got %x", uint64(0xe9c6d914c4b8d9ca), crc)
+	}
+}
+
+func TestImportRedisDump(t *testing.T) {
+	c := New[string, []byte](nil)
+
+	if err := ImportRedisDump(&c, "plain", redisDump(redisTypeString, []byte("\x03bar")), 0, nil); err != nil {
+		t.Errorf("Expected plain string to be imported, got error: %s", err)
+	}
+
+	if err := ImportRedisDump(&c, "int", redisDump(redisTypeString, []byte{0xc1, 0x39, 0x30}), 0, nil); err != nil {
+		t.Errorf("Expected integer encoded string to be imported, got error: %s", err)
+	}
+
+	if err := ImportRedisDump(&c, "lzf", redisDump(redisTypeString, []byte{0xc3, 0x05, 0x0a, 0x00, 'a', 0xe0, 0x00, 0x00}), 0, nil); err != nil {
+		t.Errorf("Expected LZF compressed string to be imported, got error: %s", err)
+	}
+
+	hash := []byte{0x02, 0x01, 'a', 0x01, '1', 0x01, 'b', 0x01, '2'}
+	if err := ImportRedisDump(&c, "hash", redisDump(redisTypeHash, hash), 0, nil); err != nil {
+		t.Errorf("Expected hash to be imported, got error: %s", err)
+	}
+
+	//Listpack holding field "f" with string value "v" and field "n" with 7-bit integer value 5
+	lp := []byte{0, 0, 0, 0, 4, 0, 0x81, 'f', 2, 0x81, 'v', 2, 0x81, 'n', 2, 0x05, 1, 0xff}
+	binary.LittleEndian.PutUint32(lp, uint32(len(lp)))
+	if err := ImportRedisDump(&c, "lp", redisDump(redisTypeHashLPack, append([]byte{byte(len(lp))}, lp...)), 0, &RedisImportOptions{
+		HashKey: func(key, field string) string { return key + "." + field },
+	}); err != nil {
+		t.Errorf("Expected listpack hash to be imported, got error: %s", err)
+	}
+
+	expected := map[string]string{"plain": "bar", "int": "12345", "lzf": "aaaaaaaaaa", "hash:a": "1", "hash:b": "2", "lp.f": "v", "lp.n": "5"}
+
+	for k, v := range expected {
+		if got := string(c.GetValue(k)); got != v {
+			t.Errorf("Expected key %q to have value %q, got %q", k, v, got)
+		}
+	}
+
+	corrupt := redisDump(redisTypeString, []byte("\x03bar"))
+	corrupt[2] ^= 0xff
+
+	if err := ImportRedisDump(&c, "corrupt", corrupt, 0, nil); err != ErrCorruptRedisPayload {
+		t.Errorf("Expected to get ErrCorruptRedisPayload, got %v", err)
+	}
+}
+
+func TestImportRESP(t *testing.T) {
+	c := New[string, []byte](nil)
+
+	stream := bytes.Buffer{}
+	stream.Write(respCommand([]byte("SELECT"), []byte("0")))
+	stream.Write(respCommand([]byte("RESTORE"), []byte("k1"), []byte("0"), redisDump(redisTypeString, []byte("\x02v1"))))
+	stream.Write(respCommand([]byte("RESTORE-ASKING"), []byte("k2"), []byte("250"), redisDump(redisTypeString, []byte("\x02v2")), []byte("REPLACE")))
+
+	n, err := ImportRESP(&c, &stream, nil)
+
+	if err != nil || n != 2 {
+		t.Errorf("Expected to import 2 keys without errors, got %d and error %v", n, err)
+	}
+
+	if string(c.GetValue("k1")) != "v1" || string(c.GetValue("k2")) != "v2" {
+		t.Errorf("Expected keys k1 and k2 to be imported, got %q and %q", c.GetValue("k1"), c.GetValue("k2"))
+	}
+
+	if !c.GetEntry("k2").TimerExist() || c.GetEntry("k1").TimerExist() {
+		t.Errorf("Expected only k2 to be imported with a timeout")
+	}
+
+	time.Sleep(time.Millisecond * 500)
+
+	if c.Exist("k2") {
+		t.Errorf("Key k2 was supposed to expire, but it did not")
+	}
+}
+
+func TestImportRedisDump_corruptLengths(t *testing.T) {
+	c := New[string, []byte](nil)
+
+	ff := bytes.Repeat([]byte{0xff}, 8)
+	payloads := map[string][]byte{
+		//LZF string with 64-bit uncompressed length overflowing int
+		"negative ulen": redisDump(redisTypeString, append(append([]byte{0xc3, 0x01, 0x81}, ff...), 0x00)),
+		"negative clen": redisDump(redisTypeString, append(append([]byte{0xc3, 0x81}, ff...), 0x01, 0x00)),
+		"huge ulen":     redisDump(redisTypeString, []byte{0xc3, 0x01, 0x80, 0x7f, 0xff, 0xff, 0xff, 0x00}),
+		"huge string":   redisDump(redisTypeString, []byte{0x80, 0x7f, 0xff, 0xff, 0xff, 'a'}),
+		"huge hash":     redisDump(redisTypeHash, append([]byte{0x81}, ff...)),
+		"long hash":     redisDump(redisTypeHash, []byte{0x80, 0x00, 0xff, 0xff, 0xff, 0x01, 'a'}),
+	}
+
+	for name, payload := range payloads {
+		if err := ImportRedisDump(&c, name, payload, 0, nil); err != ErrCorruptRedisPayload {
+			t.Errorf("Expected %s to give ErrCorruptRedisPayload, got %v", name, err)
+		}
+	}
+}
+
+func TestImportRESP_corruptLengths(t *testing.T) {
+	c := New[string, []byte](nil)
+
+	streams := []string{
+		"*-1\r\n",
+		"*1\r\n$-5\r\n",
+		fmt.Sprintf("*%d\r\n", maxRESPArgs+1),
+		fmt.Sprintf("*1\r\n$%d\r\n", maxRESPBulkLen+1),
+	}
+
+	for _, stream := range streams {
+		if _, err := ImportRESP(&c, strings.NewReader(stream), nil); err != ErrCorruptRedisPayload {
+			t.Errorf("Expected %q to give ErrCorruptRedisPayload, got %v", stream, err)
+		}
+	}
+
+	//Length within the limit is only read as far as the bytes sent
+	if _, err := ImportRESP(&c, strings.NewReader(fmt.Sprintf("*1\r\n$%d\r\nabc", maxRESPBulkLen)), nil); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected %v, got %v", io.ErrUnexpectedEOF, err)
+	}
+}
+
+func FuzzDecodeRedisDump(f *testing.F) {
+	f.Add(redisDump(redisTypeString, []byte("\x03bar")))
+	f.Add(redisDump(redisTypeString, []byte{0xc3, 0x05, 0x0a, 0x00, 'a', 0xe0, 0x00, 0x00}))
+	f.Add(redisDump(redisTypeHash, []byte{0x02, 0x01, 'a', 0x01, '1', 0x01, 'b', 0x01, '2'}))
+
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		//Checksum is recalculated, so that the fuzzer gets past it
+		if len(payload) >= 10 {
+			binary.LittleEndian.PutUint64(payload[len(payload)-8:], redisCRC64(0, payload[:len(payload)-8]))
+		}
+
+		decodeRedisDump(payload)
+	})
+}
+
+func FuzzReadRESPCommand(f *testing.F) {
+	f.Add(respCommand([]byte("SET"), []byte("k"), []byte("v")))
+	f.Add([]byte("*-1\r\n"))
+
+	f.Fuzz(func(t *testing.T, stream []byte) {
+		readRESPCommand(bufio.NewReader(bytes.NewReader(stream)))
+	})
+}
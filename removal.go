@@ -0,0 +1,85 @@
+package cacheMachine
+
+import (
+	"strconv"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//RemovalReason tells why an entry left the cache
+type RemovalReason int
+
+const (
+	//RemovalExplicit means the entry was removed on request, e.g. by Remove, RemoveWhere or Reset
+	RemovalExplicit RemovalReason = iota
+
+	//RemovalExpired means the timeout, time bucket or idle time of the entry has passed
+	RemovalExpired
+
+	//RemovalEvicted means the entry was evicted to keep the cache within its limits
+	RemovalEvicted
+
+	//RemovalReplaced means a new value was added under the key of the entry
+	RemovalReplaced
+)
+
+//------PRIVATE------
+
+//removed hands the entry that has left the cache over to the function set by SetOnRemove, if there is one, and
+//expired entries to the archiver. This method has no mutex protection
+func (c *Cache[TKey, TValue]) removed(key TKey, e *entry[TValue], reason RemovalReason) {
+	if c.onRemove != nil {
+		c.onRemove(key, e.Val, reason)
+	}
+
+	if reason != RemovalReplaced && c.transitions != nil && c.transitions.OnEmpty != nil {
+		c.transitions.OnEmpty(key, reason)
+	}
+
+	if reason == RemovalExplicit {
+		c.dropStale(key)
+	}
+
+	if reason != RemovalExpired {
+		return
+	}
+
+	if c.cache.Requirements.StaleFor > 0 {
+		c.keepStale(key, e.Val)
+	}
+
+	if c.archiver != nil {
+		c.archiver.enqueue(key, e.Val)
+	}
+
+	for _, f := range c.expiryListeners {
+		f(key, e.Val)
+	}
+}
+
+//------PUBLIC------
+
+//String returns the name of the removal reason
+func (r RemovalReason) String() string {
+	switch r {
+	case RemovalExplicit:
+		return "explicit"
+	case RemovalExpired:
+		return "expired"
+	case RemovalEvicted:
+		return "evicted"
+	case RemovalReplaced:
+		return "replaced"
+	}
+
+	return "RemovalReason(" + strconv.Itoa(int(r)) + ")"
+}
+
+//SetOnRemove sets the function called for every entry that leaves the cache, including the entries emptied by
+//Reset and GetAllAndRemove, e.g. to release resources held by the values exactly once. It's called while the cache
+//is locked, right as the entry leaves, so it must not use the cache. Nil stops the calls
+func (c *Cache[TKey, TValue]) SetOnRemove(f func(key TKey, val TValue, reason RemovalReason)) {
+	c.mx.Lock()
+	c.onRemove = f
+	c.mx.Unlock()
+}
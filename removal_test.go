@@ -0,0 +1,54 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_SetOnRemove(t *testing.T) {
+	c := New[int, int](&Requirements{MaxEntries: 3})
+
+	reasons := make(map[int]RemovalReason)
+	c.SetOnRemove(func(key, val int, reason RemovalReason) { reasons[val] = reason })
+
+	c.Add(1, 1)
+	c.Add(2, 2)
+	c.Add(2, 20)
+	c.Remove(1)
+	c.AddWithTimeout(3, 3, time.Millisecond*10)
+
+	time.Sleep(time.Millisecond * 50)
+
+	c.Add(4, 4)
+	c.Add(5, 5)
+	c.Add(6, 6)
+	c.Reset()
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	expected := map[int]RemovalReason{1: RemovalExplicit, 2: RemovalReplaced, 3: RemovalExpired, 20: RemovalEvicted, 4: RemovalExplicit, 5: RemovalExplicit, 6: RemovalExplicit}
+
+	if len(reasons) != len(expected) {
+		t.Errorf("Expected %d removals, got %v", len(expected), reasons)
+	}
+
+	for val, reason := range expected {
+		if reasons[val] != reason {
+			t.Errorf("Expected value %d to be removed as %s, got %s", val, reason, reasons[val])
+		}
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_SetOnRemove(b *testing.B) {
+	c := New[int, int](&Requirements{MaxEntries: 100})
+	c.SetOnRemove(func(key, val int, reason RemovalReason) {})
+
+	for n := 0; n < b.N; n++ {
+		c.Add(n, n)
+	}
+}
@@ -0,0 +1,146 @@
+//Package reqcache provides a cache scoped to a single request. The cache is stored in the request context.Context,
+//disposed of automatically once the request ends, and can promote selected entries into a long-lived shared cache
+package reqcache
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/emillis/cacheMachine"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//Key under which the request cache is stored in the context. Every key/value type combination has its own key,
+//so caches of different types can be stored in the same context
+type ctxKey[TKey cacheMachine.Key, TValue any] struct{}
+
+//Cache is a cache scoped to a single request. Reads fall back to the shared cache, if there is one
+type Cache[TKey cacheMachine.Key, TValue any] struct {
+	local  cacheMachine.Cache[TKey, TValue]
+	shared *cacheMachine.Cache[TKey, TValue]
+
+	disposed bool
+	mx       sync.RWMutex
+}
+
+//------PUBLIC------
+
+//Get returns the value from the request cache, or from the shared cache if the request cache doesn't have it
+func (c *Cache[TKey, TValue]) Get(key TKey) (TValue, bool) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if !c.disposed {
+		if v, ok := c.local.Get(key); ok {
+			return v, true
+		}
+	}
+
+	if c.shared != nil {
+		return c.shared.Get(key)
+	}
+
+	var nilVal TValue
+	return nilVal, false
+}
+
+//Add inserts new key:value pair into the request cache. Nothing is added once the cache is disposed of
+func (c *Cache[TKey, TValue]) Add(key TKey, val TValue) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if !c.disposed {
+		c.local.Add(key, val)
+	}
+}
+
+//Remove removes the key from the request cache. Shared cache is not affected
+func (c *Cache[TKey, TValue]) Remove(key TKey) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	c.local.Remove(key)
+}
+
+//Promote copies the entries of the keys specified from the request cache into the shared cache, so that they outlive
+//the request. Returns number of entries promoted
+func (c *Cache[TKey, TValue]) Promote(keys ...TKey) int {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if c.disposed || c.shared == nil {
+		return 0
+	}
+
+	d := c.local.GetBulk(c.present(keys))
+	c.shared.AddBulk(d)
+
+	return len(d)
+}
+
+//Dispose empties the request cache. It is called automatically once the request ends
+func (c *Cache[TKey, TValue]) Dispose() {
+	c.mx.Lock()
+	c.disposed = true
+	c.local.Reset()
+	c.mx.Unlock()
+}
+
+//------PRIVATE------
+
+//present returns only those keys that are present in the request cache
+func (c *Cache[TKey, TValue]) present(keys []TKey) []TKey {
+	var present []TKey
+
+	for _, key := range keys {
+		if c.local.Exist(key) {
+			present = append(present, key)
+		}
+	}
+
+	return present
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//newCache creates new request cache backed by the shared cache supplied, which can be nil
+func newCache[TKey cacheMachine.Key, TValue any](shared *cacheMachine.Cache[TKey, TValue]) *Cache[TKey, TValue] {
+	return &Cache[TKey, TValue]{
+		local:  cacheMachine.New[TKey, TValue](nil),
+		shared: shared,
+	}
+}
+
+//WithCache returns a copy of ctx carrying new request cache backed by the shared cache supplied, which can be nil.
+//The request cache is disposed of once ctx is done
+func WithCache[TKey cacheMachine.Key, TValue any](ctx context.Context, shared *cacheMachine.Cache[TKey, TValue]) context.Context {
+	c := newCache(shared)
+
+	if done := ctx.Done(); done != nil {
+		go func() {
+			<-done
+			c.Dispose()
+		}()
+	}
+
+	return context.WithValue(ctx, ctxKey[TKey, TValue]{}, c)
+}
+
+//FromContext returns the request cache stored in ctx by WithCache or Middleware, or nil if there is none
+func FromContext[TKey cacheMachine.Key, TValue any](ctx context.Context) *Cache[TKey, TValue] {
+	c, _ := ctx.Value(ctxKey[TKey, TValue]{}).(*Cache[TKey, TValue])
+	return c
+}
+
+//Middleware wraps the handler so that every request carries its own request cache backed by the shared cache
+//supplied. The request cache is disposed of as soon as the handler returns
+func Middleware[TKey cacheMachine.Key, TValue any](shared *cacheMachine.Cache[TKey, TValue], next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := newCache(shared)
+		defer c.Dispose()
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey[TKey, TValue]{}, c)))
+	})
+}
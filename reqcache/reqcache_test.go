@@ -0,0 +1,82 @@
+package reqcache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emillis/cacheMachine"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestWithCache(t *testing.T) {
+	shared := cacheMachine.New[string, int](nil)
+	shared.Add("shared", 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = WithCache[string, int](ctx, &shared)
+
+	c := FromContext[string, int](ctx)
+	if c == nil {
+		t.Fatalf("Expected to find request cache in the context, got <nil>")
+	}
+
+	if FromContext[string, string](ctx) != nil {
+		t.Errorf("Expected request cache of different types not to be found in the context")
+	}
+
+	c.Add("local", 2)
+
+	if v, ok := c.Get("shared"); !ok || v != 1 {
+		t.Errorf("Expected to read value %d from the shared cache, got %d and %t", 1, v, ok)
+	}
+
+	cancel()
+	time.Sleep(time.Millisecond * 50)
+
+	if _, ok := c.Get("local"); ok {
+		t.Errorf("Expected request cache to be disposed of once the context is done, but it was not")
+	}
+}
+
+func TestCache_Promote(t *testing.T) {
+	shared := cacheMachine.New[string, int](nil)
+
+	ctx := WithCache[string, int](context.Background(), &shared)
+	c := FromContext[string, int](ctx)
+
+	c.Add("a", 1)
+	c.Add("b", 2)
+
+	if n := c.Promote("a", "missing"); n != 1 {
+		t.Errorf("Expected to promote %d entry, got %d", 1, n)
+	}
+
+	if !shared.Exist("a") || shared.Exist("b") {
+		t.Errorf("Expected only key a to be promoted to the shared cache, got a - %t, b - %t", shared.Exist("a"), shared.Exist("b"))
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	shared := cacheMachine.New[string, int](nil)
+
+	var c *Cache[string, int]
+
+	h := Middleware(&shared, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c = FromContext[string, int](r.Context())
+		c.Add("key", 1)
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if c == nil {
+		t.Fatalf("Expected handler to find request cache in the context, got <nil>")
+	}
+
+	if _, ok := c.Get("key"); ok {
+		t.Errorf("Expected request cache to be disposed of after the handler returned, but it was not")
+	}
+}
@@ -0,0 +1,74 @@
+package cacheMachine
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//Key along with its position in the scan order
+type scanKey[TKey Key] struct {
+	hash uint64
+	key  TKey
+}
+
+//------PRIVATE------
+
+//scanHash returns position of the key in the scan order. It depends on the key only, so it's stable across mutations
+func scanHash[TKey Key](key TKey) uint64 {
+	h := fnv.New64a()
+	fmt.Fprint(h, key)
+	return h.Sum64()
+}
+
+//------PUBLIC------
+
+//Scan returns around count keys starting at the cursor along with the cursor to continue from. Start with cursor 0
+//and call Scan with the returned cursor until it returns 0. Every key present for the whole scan is returned at least
+//once regardless of the keys added or removed in the meantime, while keys added or removed during the scan may or
+//may not be returned. More than count keys are returned when keys share the same position in the scan order.
+//Every call takes time proportional to the size of the cache
+func (c *Cache[TKey, TValue]) Scan(cursor uint64, count int) ([]TKey, uint64) {
+	if count < 1 {
+		count = 10
+	}
+
+	c.mx.RLock()
+	candidates := make([]scanKey[TKey], 0, len(c.data))
+	for key, e := range c.data {
+		if !c.live(e) {
+			continue
+		}
+
+		if h := scanHash(key); h >= cursor {
+			candidates = append(candidates, scanKey[TKey]{hash: h, key: key})
+		}
+	}
+	c.mx.RUnlock()
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].hash < candidates[j].hash })
+
+	//Keys sharing the position of the last key are all returned, so that the cursor never splits them
+	n := count
+	for n < len(candidates) && candidates[n].hash == candidates[n-1].hash {
+		n++
+	}
+
+	if n >= len(candidates) {
+		n = len(candidates)
+	}
+
+	keys := make([]TKey, n)
+	for i := range keys {
+		keys[i] = candidates[i].key
+	}
+
+	if n == len(candidates) || candidates[n-1].hash == math.MaxUint64 {
+		return keys, 0
+	}
+
+	return keys, candidates[n-1].hash + 1
+}
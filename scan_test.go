@@ -0,0 +1,54 @@
+package cacheMachine
+
+import (
+	"testing"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_Scan(t *testing.T) {
+	c := initializeFullCache(100, nil)
+
+	seen := make(map[int]int)
+	cursor := uint64(0)
+	calls := 0
+
+	for {
+		keys, next := c.Scan(cursor, 7)
+		calls++
+
+		for _, k := range keys {
+			seen[k]++
+		}
+
+		//Mutating the cache during the scan
+		c.Remove(calls)
+		c.Add(1000+calls, 0)
+
+		if next == 0 || calls > 100 {
+			break
+		}
+
+		cursor = next
+	}
+
+	for i := 20; i < 100; i++ {
+		if seen[i] != 1 {
+			t.Errorf("Expected key %d present for the whole scan to be returned once, got %d times", i, seen[i])
+		}
+	}
+
+	if calls > 100 {
+		t.Errorf("Expected the scan to finish, but it was still running after %d calls", calls)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_Scan(b *testing.B) {
+	c := initializeFullCache(1000, nil)
+
+	for n := 0; n < b.N; n++ {
+		c.Scan(0, 10)
+	}
+}
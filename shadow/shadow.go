@@ -0,0 +1,127 @@
+//Package shadow mirrors the traffic of a cache to a second cache configured differently, e.g. with another eviction
+//policy or size, and reports how their results diverge. Responses always come from the primary cache, so a new
+//configuration can be tried out in production before switching over to it
+package shadow
+
+import (
+	"sync/atomic"
+
+	"github.com/emillis/cacheMachine"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//Stats are the divergence metrics collected since the shadowing started
+type Stats struct {
+	//Number of reads mirrored
+	Reads uint64
+
+	//Number of reads that hit the primary and the shadow cache respectively
+	PrimaryHits uint64
+	ShadowHits  uint64
+
+	//Number of reads that hit only one of the caches
+	Divergent uint64
+
+	//Number of reads that hit both caches, but returned different values. Only counted if Equal is set
+	Mismatched uint64
+
+	//Number of operations that panicked in the shadow cache
+	Failures uint64
+}
+
+//Cache sends every operation to both the primary and the shadow cache, returning results of the primary one
+type Cache[TKey cacheMachine.Key, TValue any] struct {
+	//Counters are kept first to be 64-bit aligned for the atomic operations
+	reads       uint64
+	primaryHits uint64
+	shadowHits  uint64
+	divergent   uint64
+	mismatched  uint64
+	failures    uint64
+
+	primary *cacheMachine.Cache[TKey, TValue]
+	shadow  *cacheMachine.Cache[TKey, TValue]
+	equal   func(a, b TValue) bool
+}
+
+//------PRIVATE------
+
+//mirror runs the operation on the shadow cache. A panic in the shadow cache is counted instead of being propagated,
+//so that it can never affect the caller
+func (c *Cache[TKey, TValue]) mirror(f func(shadow *cacheMachine.Cache[TKey, TValue])) {
+	defer func() {
+		if recover() != nil {
+			atomic.AddUint64(&c.failures, 1)
+		}
+	}()
+
+	f(c.shadow)
+}
+
+//------PUBLIC------
+
+//Get returns the value from the primary cache and compares it with the value in the shadow cache
+func (c *Cache[TKey, TValue]) Get(key TKey) (TValue, bool) {
+	val, ok := c.primary.Get(key)
+
+	c.mirror(func(shadow *cacheMachine.Cache[TKey, TValue]) {
+		shadowVal, shadowOk := shadow.Get(key)
+
+		atomic.AddUint64(&c.reads, 1)
+
+		if ok {
+			atomic.AddUint64(&c.primaryHits, 1)
+		}
+
+		if shadowOk {
+			atomic.AddUint64(&c.shadowHits, 1)
+		}
+
+		if ok != shadowOk {
+			atomic.AddUint64(&c.divergent, 1)
+		} else if ok && c.equal != nil && !c.equal(val, shadowVal) {
+			atomic.AddUint64(&c.mismatched, 1)
+		}
+	})
+
+	return val, ok
+}
+
+//Add inserts new key:value pair into both caches
+func (c *Cache[TKey, TValue]) Add(key TKey, val TValue) {
+	c.primary.Add(key, val)
+	c.mirror(func(shadow *cacheMachine.Cache[TKey, TValue]) { shadow.Add(key, val) })
+}
+
+//AddBulk adds items to both caches in bulk
+func (c *Cache[TKey, TValue]) AddBulk(d map[TKey]TValue) {
+	c.primary.AddBulk(d)
+	c.mirror(func(shadow *cacheMachine.Cache[TKey, TValue]) { shadow.AddBulk(d) })
+}
+
+//Remove removes the key from both caches
+func (c *Cache[TKey, TValue]) Remove(key TKey) {
+	c.primary.Remove(key)
+	c.mirror(func(shadow *cacheMachine.Cache[TKey, TValue]) { shadow.Remove(key) })
+}
+
+//Stats returns the divergence metrics collected so far
+func (c *Cache[TKey, TValue]) Stats() Stats {
+	return Stats{
+		Reads:       atomic.LoadUint64(&c.reads),
+		PrimaryHits: atomic.LoadUint64(&c.primaryHits),
+		ShadowHits:  atomic.LoadUint64(&c.shadowHits),
+		Divergent:   atomic.LoadUint64(&c.divergent),
+		Mismatched:  atomic.LoadUint64(&c.mismatched),
+		Failures:    atomic.LoadUint64(&c.failures),
+	}
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//New starts shadowing the primary cache with the shadow cache. If equal is supplied, values read from both caches
+//are compared using it, otherwise only whether the key was found is compared
+func New[TKey cacheMachine.Key, TValue any](primary, shadow *cacheMachine.Cache[TKey, TValue], equal func(a, b TValue) bool) *Cache[TKey, TValue] {
+	return &Cache[TKey, TValue]{primary: primary, shadow: shadow, equal: equal}
+}
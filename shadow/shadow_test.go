@@ -0,0 +1,71 @@
+package shadow
+
+import (
+	"testing"
+
+	"github.com/emillis/cacheMachine"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestNew(t *testing.T) {
+	primary := cacheMachine.New[int, int](&cacheMachine.Requirements{MaxEntries: 10})
+	secondary := cacheMachine.New[int, int](&cacheMachine.Requirements{MaxEntries: 5})
+
+	c := New(&primary, &secondary, func(a, b int) bool { return a == b })
+
+	for i := 0; i < 10; i++ {
+		c.Add(i, i)
+	}
+
+	secondary.Add(9, 100)
+
+	for i := 0; i < 10; i++ {
+		if v, ok := c.Get(i); !ok || v != i {
+			t.Errorf("Expected to get value %d from the primary cache, got %d and %t", i, v, ok)
+		}
+	}
+
+	expected := Stats{Reads: 10, PrimaryHits: 10, ShadowHits: 5, Divergent: 5, Mismatched: 1}
+	if s := c.Stats(); s != expected {
+		t.Errorf("Expected stats %+v, got %+v", expected, s)
+	}
+
+	c.Remove(9)
+
+	if primary.Exist(9) || secondary.Exist(9) {
+		t.Errorf("Expected the key to be removed from both caches")
+	}
+}
+
+func TestCache_Stats(t *testing.T) {
+	primary := cacheMachine.New[int, int](nil)
+	var broken cacheMachine.Cache[int, int]
+
+	c := New(&primary, &broken, nil)
+	c.Add(1, 1)
+
+	if v, ok := c.Get(1); !ok || v != 1 {
+		t.Errorf("Expected broken shadow cache not to affect the result, got %d and %t", v, ok)
+	}
+
+	if s := c.Stats(); s.Failures != 2 {
+		t.Errorf("Expected %d failures to be counted, got %d", 2, s.Failures)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_Get(b *testing.B) {
+	primary := cacheMachine.New[int, int](nil)
+	secondary := cacheMachine.New[int, int](nil)
+	c := New(&primary, &secondary, nil)
+
+	for i := 0; i < 100; i++ {
+		c.Add(i, i)
+	}
+
+	for n := 0; n < b.N; n++ {
+		c.Get(n % 200)
+	}
+}
@@ -0,0 +1,113 @@
+package cacheMachine
+
+import (
+	"sort"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//SimulationResult is the outcome of replaying an access trace against a single eviction policy and cache size
+type SimulationResult struct {
+	//Name of the eviction policy simulated
+	Policy string
+
+	//Maximum number of entries of the simulated cache
+	Size int
+
+	//Number of accesses that found the key in the simulated cache
+	Hits int
+
+	//Number of accesses that didn't find the key in the simulated cache
+	Misses int
+}
+
+//------PUBLIC------
+
+//HitRate returns the ratio of hits to all the accesses simulated
+func (r SimulationResult) HitRate() float64 {
+	if r.Hits+r.Misses < 1 {
+		return 0
+	}
+
+	return float64(r.Hits) / float64(r.Hits+r.Misses)
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//simulate replays the trace against a single policy limited to the size specified
+func simulate[TKey Key](trace []TKey, size int, policy EvictionPolicy[TKey]) (hits, misses int) {
+	present := make(map[TKey]struct{}, size)
+
+	for _, key := range trace {
+		if _, exist := present[key]; exist {
+			policy.OnGet(key)
+			hits++
+			continue
+		}
+
+		misses++
+		present[key] = struct{}{}
+		policy.OnAdd(key)
+
+		if len(present) <= size {
+			continue
+		}
+
+		if victim, ok := policy.Victim(); ok {
+			policy.OnRemove(victim)
+			delete(present, victim)
+		}
+	}
+
+	return hits, misses
+}
+
+//Simulate replays the trace of accessed keys against every combination of the eviction policies and cache sizes
+//supplied and reports how many of the accesses would have been hits, assuming every miss loads the key into the cache.
+//This allows choosing MaxEntries and Policy before rolling them out. If no policies are supplied, all the built-in
+//policies are simulated. Results are sorted by policy name and cache size
+func Simulate[TKey Key](trace []TKey, sizes []int, policies map[string]func() EvictionPolicy[TKey]) []SimulationResult {
+	if policies == nil {
+		policies = make(map[string]func() EvictionPolicy[TKey])
+		for _, p := range builtinPolicies {
+			policies[p.String()] = builtinPolicy[TKey](&Requirements{Policy: p})
+		}
+	}
+
+	results := make([]SimulationResult, 0, len(sizes)*len(policies))
+
+	for name, newPolicy := range policies {
+		for _, size := range sizes {
+			if size < 1 {
+				continue
+			}
+
+			hits, misses := simulate(trace, size, newPolicy())
+			results = append(results, SimulationResult{Policy: name, Size: size, Hits: hits, Misses: misses})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Policy != results[j].Policy {
+			return results[i].Policy < results[j].Policy
+		}
+		return results[i].Size < results[j].Size
+	})
+
+	return results
+}
+
+//SimulationTrace turns the operations of the trace recorded by RecordTrace into the keys accessed, as expected by
+//Simulate. Only reads are accesses, since Simulate assumes that every miss loads the key, while adds and removals
+//are left out. Key hashes are used as int64 keys, the same way as by ReplayTrace
+func SimulationTrace(ops []TraceOp) []int64 {
+	keys := make([]int64, 0, len(ops))
+
+	for _, op := range ops {
+		if op.Kind == TraceGet {
+			keys = append(keys, int64(op.Key))
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,79 @@
+package cacheMachine
+
+import (
+	"bytes"
+	"testing"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestSimulate(t *testing.T) {
+	//Small hot set of keys 0-2 that is already warm, interleaved with a scan over keys 100-199
+	trace := []int{0, 1, 2, 0, 1, 2}
+	for i := 0; i < 100; i++ {
+		trace = append(trace, i%3, 100+i)
+	}
+
+	results := Simulate(trace, []int{2, 4}, nil)
+
+	if len(results) != len(builtinPolicies)*2 {
+		t.Fatalf("Expected to get %d results, got %d", len(builtinPolicies)*2, len(results))
+	}
+
+	if results[0].Policy != "2Q" || results[0].Size != 2 || results[13].Policy != "SLRU" || results[13].Size != 4 {
+		t.Errorf("Expected results to be sorted by policy and size, got %+v", results)
+	}
+
+	for _, r := range results {
+		if r.Hits+r.Misses != len(trace) {
+			t.Errorf("Expected %d accesses to be simulated for %s/%d, got %d", len(trace), r.Policy, r.Size, r.Hits+r.Misses)
+		}
+	}
+
+	lfu, lru := results[7], results[9]
+
+	if lfu.Policy != "LFU" || lru.Policy != "LRU" || lfu.HitRate() <= lru.HitRate() {
+		t.Errorf("Expected LFU to outperform LRU on scan workload with cache size 4, got %+v and %+v", lfu, lru)
+	}
+}
+
+func TestSimulationTrace(t *testing.T) {
+	c := New[int, int](nil)
+
+	buf := bytes.Buffer{}
+	c.RecordTrace(&buf)
+	c.Get(1)
+	c.Add(1, 1)
+	c.Get(1)
+	c.Get(2)
+	c.Remove(1)
+	c.RecordTrace(nil)
+
+	ops, err := ReadTrace(&buf)
+	if err != nil {
+		t.Fatalf("Expected trace to be read, got error: %s", err)
+	}
+
+	keys := SimulationTrace(ops)
+
+	if len(keys) != 3 || keys[0] != keys[1] || keys[1] == keys[2] {
+		t.Fatalf("Expected the reads of the trace to become the accesses, got %v", keys)
+	}
+
+	if r := Simulate(keys, []int{1}, nil)[0]; r.Hits != 1 || r.Misses != 2 {
+		t.Errorf("Expected the trace to be simulated with %d hit and %d misses, got %+v", 1, 2, r)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkSimulate(b *testing.B) {
+	trace := make([]int, 1000)
+	for i := range trace {
+		trace[i] = i * 7 % 97
+	}
+
+	for n := 0; n < b.N; n++ {
+		Simulate(trace, []int{10, 50}, nil)
+	}
+}
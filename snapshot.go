@@ -0,0 +1,850 @@
+package cacheMachine
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"reflect"
+	"time"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//SnapshotVersion is the version of the snapshot format written by Save. Snapshots with a version newer than this,
+//or older than the oldest version still supported, are refused
+const SnapshotVersion uint16 = 2
+
+//Oldest snapshot version Load is still able to read
+const minSnapshotVersion uint16 = 1
+
+//Magic bytes every snapshot starts with
+var snapshotMagic = [4]byte{'C', 'M', 'S', 'N'}
+
+//Magic bytes every segment of the snapshot starts with
+var segmentMagic = [4]byte{'C', 'M', 'S', 'G'}
+
+//Size of the segment header in bytes: magic, first record, record count, data length and header checksum
+const segmentHeaderSize = 4 + 8 + 4 + 4 + 4
+
+//Maximum number of records written in a single segment
+const snapshotSegmentSize = 1024
+
+//Magic bytes of the envelope wrapping encrypted and/or signed snapshots
+var envelopeMagic = [4]byte{'C', 'M', 'S', 'E'}
+
+//Flags of the envelope describing how the snapshot within it is protected
+const (
+	envelopeEncrypted byte = 1 << iota
+	envelopeSigned
+)
+
+//Codec used to encode the entries of the snapshot
+const snapshotCodec = "gob"
+
+//Codec used to encode the entries of the snapshot when the cache has EntryCodec set. Values are encoded by the
+//EntryCodec and written as byte slices using gob
+const snapshotEntryCodec = "gob+entry"
+
+//ErrCorruptSnapshot is returned when the snapshot data does not match the checksums recorded in it
+var ErrCorruptSnapshot = errors.New("cacheMachine: snapshot is corrupt")
+
+//ErrSnapshotSignature is returned when the snapshot signature is missing or doesn't match LoadOptions.SigningKey
+var ErrSnapshotSignature = errors.New("cacheMachine: snapshot signature is invalid")
+
+//ErrSnapshotDecryption is returned when the snapshot can not be decrypted with LoadOptions.EncryptionKey, or when
+//the snapshot is expected to be encrypted but it is not
+var ErrSnapshotDecryption = errors.New("cacheMachine: snapshot could not be decrypted")
+
+//===========[STRUCTS]==================================================================================================
+
+//IncompatibleSnapshotError is returned when the snapshot can not be loaded into the cache because it was written
+//by an incompatible version of the package or by a cache of different key/value types
+type IncompatibleSnapshotError struct {
+	//Header field that didn't match, e.g. "version" or "value type"
+	Field string
+
+	//Value expected by this cache
+	Expected string
+
+	//Value found in the snapshot
+	Got string
+}
+
+//Error describes the setting the snapshot doesn't match
+func (e *IncompatibleSnapshotError) Error() string {
+	return fmt.Sprintf("cacheMachine: incompatible snapshot %s: expected %q, got %q", e.Field, e.Expected, e.Got)
+}
+
+//SaveOptions defines how the snapshot should be saved
+type SaveOptions struct {
+	//If this is set, the snapshot is encrypted with AES-GCM using this key. Key must be 16, 24 or 32 bytes long
+	EncryptionKey []byte
+
+	//If this is set, the snapshot is signed with HMAC-SHA256 using this key
+	SigningKey []byte
+}
+
+//LoadOptions defines how the snapshot should be loaded
+type LoadOptions struct {
+	//If this is set, segments of the snapshot that are corrupt are skipped instead of failing the whole Load.
+	//Skipped records are reported in LoadReport.Dropped
+	SkipCorrupt bool
+
+	//Key used to decrypt the snapshot. If this is set, snapshots that are not encrypted are refused
+	EncryptionKey []byte
+
+	//Key used to verify the signature of the snapshot. If this is set, snapshots that are not signed are refused
+	SigningKey []byte
+}
+
+//LoadReport describes the outcome of loading a snapshot
+type LoadReport struct {
+	//Number of entries loaded into the cache
+	Loaded int
+
+	//Ranges of records that were dropped because they were corrupt. Only populated when LoadOptions.SkipCorrupt is set
+	Dropped []DroppedRange
+}
+
+//DroppedRange is a range of records that could not be loaded from the snapshot
+type DroppedRange struct {
+	//Index of the first record dropped
+	First uint64
+
+	//Number of records dropped
+	Count uint64
+
+	//Reason why the records were dropped
+	Err error
+}
+
+//EntryCodec encodes values of the cache in place of encoding/gob, e.g. for values with unexported fields or which need
+//custom encoding. It's set using SetEntryCodec. Snapshots saved with and without EntryCodec are incompatible
+type EntryCodec[TValue any] interface {
+	MarshalEntry(TValue) ([]byte, error)
+	UnmarshalEntry([]byte) (TValue, error)
+}
+
+//Header written at the beginning of every snapshot describing its contents
+type snapshotHeader struct {
+	Version   uint16
+	Codec     string
+	KeyType   string
+	ValueType string
+	Count     uint64
+
+	//Checksum of the header itself. In version 1 snapshots, this was the checksum of all the data
+	Checksum uint32
+}
+
+//Header written at the beginning of every segment of the snapshot
+type segmentHeader struct {
+	First  uint64
+	Count  uint32
+	Length uint32
+}
+
+//Single key:value pair within the snapshot. Incremental snapshots also hold records of the keys removed, which
+//have no value
+type snapshotRecord[TKey Key, TValue any] struct {
+	Key     TKey
+	Value   TValue
+	Removed bool
+
+	//Wall clock time at which the entry expires. Zero if the entry has no timeout of its own
+	Deadline time.Time
+}
+
+//------PRIVATE------
+
+//write writes the header to the writer supplied along with its checksum
+func (h *snapshotHeader) write(w io.Writer) error {
+	hash := crc32.NewIEEE()
+	hw := io.MultiWriter(w, hash)
+
+	if _, err := hw.Write(snapshotMagic[:]); err != nil {
+		return err
+	}
+
+	if err := binary.Write(hw, binary.BigEndian, h.Version); err != nil {
+		return err
+	}
+
+	for _, s := range []string{h.Codec, h.KeyType, h.ValueType} {
+		if err := writeShortString(hw, s); err != nil {
+			return err
+		}
+	}
+
+	if err := binary.Write(hw, binary.BigEndian, h.Count); err != nil {
+		return err
+	}
+
+	h.Checksum = hash.Sum32()
+
+	return binary.Write(w, binary.BigEndian, h.Checksum)
+}
+
+//read reads the header from the reader supplied. Reading stops at the first incompatible field
+func (h *snapshotHeader) read(r io.Reader) error {
+	hash := crc32.NewIEEE()
+	hr := io.TeeReader(r, hash)
+
+	var magic [4]byte
+	if _, err := io.ReadFull(hr, magic[:]); err != nil {
+		return err
+	}
+
+	if magic != snapshotMagic {
+		return &IncompatibleSnapshotError{Field: "magic", Expected: string(snapshotMagic[:]), Got: string(magic[:])}
+	}
+
+	if err := binary.Read(hr, binary.BigEndian, &h.Version); err != nil {
+		return err
+	}
+
+	if h.Version < minSnapshotVersion || h.Version > SnapshotVersion {
+		return &IncompatibleSnapshotError{Field: "version", Expected: fmt.Sprint(SnapshotVersion), Got: fmt.Sprint(h.Version)}
+	}
+
+	for _, s := range []*string{&h.Codec, &h.KeyType, &h.ValueType} {
+		var err error
+		if *s, err = readShortString(hr); err != nil {
+			return err
+		}
+	}
+
+	if err := binary.Read(hr, binary.BigEndian, &h.Count); err != nil {
+		return err
+	}
+
+	if err := binary.Read(r, binary.BigEndian, &h.Checksum); err != nil {
+		return err
+	}
+
+	if h.Version > 1 && h.Checksum != hash.Sum32() {
+		return ErrCorruptSnapshot
+	}
+
+	return nil
+}
+
+//compatible checks whether the header describes a snapshot the cache can load
+func (h *snapshotHeader) compatible(expected *snapshotHeader) error {
+	fields := []struct{ name, expected, got string }{
+		{"codec", expected.Codec, h.Codec},
+		{"key type", expected.KeyType, h.KeyType},
+		{"value type", expected.ValueType, h.ValueType},
+	}
+
+	for _, f := range fields {
+		if f.expected != f.got {
+			return &IncompatibleSnapshotError{Field: f.name, Expected: f.expected, Got: f.got}
+		}
+	}
+
+	return nil
+}
+
+//bytes encodes the segment header along with its checksum
+func (h *segmentHeader) bytes() []byte {
+	b := make([]byte, segmentHeaderSize)
+
+	copy(b, segmentMagic[:])
+	binary.BigEndian.PutUint64(b[4:], h.First)
+	binary.BigEndian.PutUint32(b[12:], h.Count)
+	binary.BigEndian.PutUint32(b[16:], h.Length)
+	binary.BigEndian.PutUint32(b[20:], crc32.ChecksumIEEE(b[:20]))
+
+	return b
+}
+
+//parse decodes the segment header, returning false if the bytes are not a valid segment header
+func (h *segmentHeader) parse(b []byte) bool {
+	if len(b) < segmentHeaderSize || !bytes.Equal(b[:4], segmentMagic[:]) {
+		return false
+	}
+
+	if binary.BigEndian.Uint32(b[20:]) != crc32.ChecksumIEEE(b[:20]) {
+		return false
+	}
+
+	h.First = binary.BigEndian.Uint64(b[4:])
+	h.Count = binary.BigEndian.Uint32(b[12:])
+	h.Length = binary.BigEndian.Uint32(b[16:])
+
+	return true
+}
+
+//snapshotHeader returns the header describing snapshots of this cache, without count and checksum
+func (c *Cache[TKey, TValue]) snapshotHeader() snapshotHeader {
+	codec := snapshotCodec
+	if c.entryCodec() != nil {
+		codec = snapshotEntryCodec
+	}
+
+	return snapshotHeader{
+		Version:   SnapshotVersion,
+		Codec:     codec,
+		KeyType:   typeName[TKey](),
+		ValueType: typeName[TValue](),
+	}
+}
+
+//entryCodec returns the EntryCodec set, or nil
+func (c *Cache[TKey, TValue]) entryCodec() EntryCodec[TValue] {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	return c.codec
+}
+
+//writeSegment encodes the records into a single segment and writes it to the writer supplied
+func (c *Cache[TKey, TValue]) writeSegment(w io.Writer, first uint64, records []snapshotRecord[TKey, TValue]) error {
+	data := bytes.Buffer{}
+	enc := gob.NewEncoder(&data)
+	codec := c.entryCodec()
+
+	for _, rec := range records {
+		if err := encodeRecord(enc, codec, rec); err != nil {
+			return err
+		}
+	}
+
+	h := segmentHeader{First: first, Count: uint32(len(records)), Length: uint32(data.Len())}
+	checksum := crc32.ChecksumIEEE(data.Bytes())
+
+	if _, err := w.Write(h.bytes()); err != nil {
+		return err
+	}
+
+	if _, err := data.WriteTo(w); err != nil {
+		return err
+	}
+
+	return binary.Write(w, binary.BigEndian, checksum)
+}
+
+//decodeRecords decodes count records from the data supplied into the map, and keys of the records of removals into
+//the set of removed keys
+func (c *Cache[TKey, TValue]) decodeRecords(data []byte, count uint64, d map[TKey]timedValue[TValue], removed map[TKey]struct{}) error {
+	dec := gob.NewDecoder(bytes.NewReader(data))
+	codec := c.entryCodec()
+
+	for i := uint64(0); i < count; i++ {
+		rec, err := decodeRecord[TKey](dec, codec)
+		if err != nil {
+			return err
+		}
+
+		if rec.Removed {
+			removed[rec.Key] = struct{}{}
+			continue
+		}
+
+		d[rec.Key] = timedValue[TValue]{val: rec.Value, deadline: rec.Deadline}
+	}
+
+	return nil
+}
+
+//readV1 reads the data of version 1 snapshot, which is a single block of records protected by the header checksum
+func (c *Cache[TKey, TValue]) readV1(r *bufio.Reader, h *snapshotHeader, d map[TKey]timedValue[TValue], removed map[TKey]struct{}) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if crc32.ChecksumIEEE(body) != h.Checksum {
+		return ErrCorruptSnapshot
+	}
+
+	return c.decodeRecords(body, h.Count, d, removed)
+}
+
+//readSegments reads the segments of the snapshot. Corrupt segments either fail the read or, if skipCorrupt is set,
+//get recorded in the report while the reader resynchronizes on the next valid segment header
+func (c *Cache[TKey, TValue]) readSegments(r *bufio.Reader, h *snapshotHeader, d map[TKey]timedValue[TValue], removed map[TKey]struct{}, skipCorrupt bool, rep *LoadReport) error {
+	var next uint64
+
+	drop := func(first, count uint64, err error) error {
+		if !skipCorrupt {
+			return err
+		}
+
+		if count > 0 {
+			rep.Dropped = append(rep.Dropped, DroppedRange{First: first, Count: count, Err: err})
+		}
+
+		return nil
+	}
+
+	for {
+		b, err := r.Peek(segmentHeaderSize)
+		if err == io.EOF && len(b) == 0 {
+			break
+		}
+
+		sh := segmentHeader{}
+		if !sh.parse(b) {
+			if !skipCorrupt {
+				return ErrCorruptSnapshot
+			}
+
+			if err != nil {
+				break
+			}
+
+			//Resynchronizing on the next valid segment header
+			r.Discard(1)
+			continue
+		}
+		r.Discard(segmentHeaderSize)
+
+		if sh.First > next {
+			if err := drop(next, sh.First-next, ErrCorruptSnapshot); err != nil {
+				return err
+			}
+			next = sh.First
+		}
+
+		//Sizes come from the stream, so the segment is read as it arrives rather than allocated upfront
+		data, err := io.ReadAll(io.LimitReader(r, int64(sh.Length)+4))
+		if err != nil || len(data) < int(sh.Length)+4 {
+			break
+		}
+
+		if binary.BigEndian.Uint32(data[sh.Length:]) != crc32.ChecksumIEEE(data[:sh.Length]) {
+			if err := drop(sh.First, uint64(sh.Count), ErrCorruptSnapshot); err != nil {
+				return err
+			}
+		} else {
+			//Segments written by Save hold at most snapshotSegmentSize records, larger counts are not trusted upfront
+			size := int(sh.Count)
+			if size > snapshotSegmentSize {
+				size = snapshotSegmentSize
+			}
+
+			segment := make(map[TKey]timedValue[TValue], size)
+			segmentRemoved := make(map[TKey]struct{})
+
+			//Segment is merged only once it's decoded as a whole, so that the records reported as dropped are not loaded
+			if err := c.decodeRecords(data[:sh.Length], uint64(sh.Count), segment, segmentRemoved); err != nil {
+				if err := drop(sh.First, uint64(sh.Count), err); err != nil {
+					return err
+				}
+			} else {
+				for k, v := range segment {
+					d[k] = v
+				}
+
+				for k := range segmentRemoved {
+					removed[k] = struct{}{}
+				}
+			}
+		}
+
+		next = sh.First + uint64(sh.Count)
+	}
+
+	if next < h.Count {
+		return drop(next, h.Count-next, ErrCorruptSnapshot)
+	}
+
+	return nil
+}
+
+//writeSnapshot writes the header and all the segments of the snapshot to the writer supplied
+func (c *Cache[TKey, TValue]) writeSnapshot(w io.Writer) error {
+	c.mx.RLock()
+	d := c.timedValues()
+	c.mx.RUnlock()
+
+	records := make([]snapshotRecord[TKey, TValue], 0, len(d))
+	for k, v := range d {
+		records = append(records, snapshotRecord[TKey, TValue]{Key: k, Value: v.val, Deadline: v.deadline})
+	}
+
+	return c.writeRecords(w, records)
+}
+
+//writeRecords writes the header and the segments holding the records supplied to the writer
+func (c *Cache[TKey, TValue]) writeRecords(w io.Writer, all []snapshotRecord[TKey, TValue]) error {
+	h := c.snapshotHeader()
+	h.Count = uint64(len(all))
+
+	bw := bufio.NewWriter(w)
+
+	if err := h.write(bw); err != nil {
+		return err
+	}
+
+	var first uint64
+	records := make([]snapshotRecord[TKey, TValue], 0, snapshotSegmentSize)
+
+	for _, rec := range all {
+		records = append(records, rec)
+
+		if len(records) < snapshotSegmentSize {
+			continue
+		}
+
+		if err := c.writeSegment(bw, first, records); err != nil {
+			return err
+		}
+
+		first += uint64(len(records))
+		records = records[:0]
+	}
+
+	if len(records) > 0 {
+		if err := c.writeSegment(bw, first, records); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+//------PUBLIC------
+
+//SetEntryCodec sets the codec used to encode values in snapshots in place of encoding/gob. Nil restores the default
+func (c *Cache[TKey, TValue]) SetEntryCodec(codec EntryCodec[TValue]) {
+	c.mx.Lock()
+	c.codec = codec
+	c.mx.Unlock()
+}
+
+//Save writes a snapshot of all the values stored in the cache to the writer supplied. Snapshot starts with a header
+//describing the format version, codec, key/value types and number of entries, followed by segments of entries,
+//each protected by its own checksum, so that Load can refuse snapshots it is not able to read and detect corruption.
+//Values are encoded using encoding/gob. Deadlines of the entries are recorded along with their values, so that Load
+//restores the time remaining until they expire and leaves out the entries that have expired in the meantime
+func (c *Cache[TKey, TValue]) Save(w io.Writer) error {
+	return c.SaveWithOptions(w, SaveOptions{})
+}
+
+//SaveWithOptions does the same as method "Save" but allows to encrypt and/or sign the snapshot, so that snapshots
+//persisted on shared disks can't be read or tampered with. Protected snapshots are assembled in memory before
+//being written
+func (c *Cache[TKey, TValue]) SaveWithOptions(w io.Writer, opts SaveOptions) error {
+	return c.save(w, opts, c.writeSnapshot)
+}
+
+//save writes the snapshot using the function supplied, sealing it if the options say so
+func (c *Cache[TKey, TValue]) save(w io.Writer, opts SaveOptions, write func(io.Writer) error) error {
+	if opts.EncryptionKey == nil && opts.SigningKey == nil {
+		return write(w)
+	}
+
+	plain := bytes.Buffer{}
+	if err := write(&plain); err != nil {
+		return err
+	}
+
+	sealed, err := sealSnapshot(plain.Bytes(), opts)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(sealed)
+	return err
+}
+
+//Load reads the snapshot written by Save and adds all of its values to the cache. Incremental snapshots written by
+//SaveIncremental also remove the keys removed since the previous snapshot. If the snapshot is not compatible
+//with this cache, *IncompatibleSnapshotError is returned. If the data doesn't match the checksums, ErrCorruptSnapshot
+//is returned. In case of error, nothing is added to the cache
+func (c *Cache[TKey, TValue]) Load(r io.Reader) error {
+	_, err := c.LoadWithOptions(r, LoadOptions{})
+	return err
+}
+
+//LoadWithOptions does the same as method "Load" but allows to configure how the snapshot is loaded. With
+//LoadOptions.SkipCorrupt set, corrupt segments are skipped and reported in LoadReport instead of failing the Load.
+//Encrypted and signed snapshots require the keys they were saved with
+func (c *Cache[TKey, TValue]) LoadWithOptions(r io.Reader, opts LoadOptions) (LoadReport, error) {
+	rep := LoadReport{}
+
+	br, err := openSnapshot(bufio.NewReader(r), opts)
+	if err != nil {
+		return rep, err
+	}
+
+	h := snapshotHeader{}
+	if err := h.read(br); err != nil {
+		return rep, err
+	}
+
+	expected := c.snapshotHeader()
+	if err := h.compatible(&expected); err != nil {
+		return rep, err
+	}
+
+	d := make(map[TKey]timedValue[TValue])
+	removed := make(map[TKey]struct{})
+
+	if h.Version == 1 {
+		err = c.readV1(br, &h, d, removed)
+	} else {
+		err = c.readSegments(br, &h, d, removed, opts.SkipCorrupt, &rep)
+	}
+
+	if err != nil {
+		return rep, err
+	}
+
+	if len(removed) > 0 {
+		c.mx.Lock()
+		for key := range removed {
+			c.discard(key)
+		}
+		c.mx.Unlock()
+	}
+
+	rep.Loaded = len(c.addTimed(d, MergeOptions{}))
+
+	return rep, nil
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//encodeRecord encodes the record using the EntryCodec supplied, or gob if it's nil
+func encodeRecord[TKey Key, TValue any](enc *gob.Encoder, codec EntryCodec[TValue], rec snapshotRecord[TKey, TValue]) error {
+	if codec == nil {
+		return enc.Encode(rec)
+	}
+
+	if rec.Removed {
+		return enc.Encode(snapshotRecord[TKey, []byte]{Key: rec.Key, Removed: true})
+	}
+
+	b, err := codec.MarshalEntry(rec.Value)
+	if err != nil {
+		return err
+	}
+
+	return enc.Encode(snapshotRecord[TKey, []byte]{Key: rec.Key, Value: b, Deadline: rec.Deadline})
+}
+
+//decodeRecord decodes the record using the EntryCodec supplied, or gob if it's nil
+func decodeRecord[TKey Key, TValue any](dec *gob.Decoder, codec EntryCodec[TValue]) (snapshotRecord[TKey, TValue], error) {
+	rec := snapshotRecord[TKey, TValue]{}
+
+	if codec == nil {
+		err := dec.Decode(&rec)
+		return rec, err
+	}
+
+	raw := snapshotRecord[TKey, []byte]{}
+	if err := dec.Decode(&raw); err != nil {
+		return rec, err
+	}
+
+	if raw.Removed {
+		rec.Key, rec.Removed = raw.Key, true
+		return rec, nil
+	}
+
+	v, err := codec.UnmarshalEntry(raw.Value)
+	if err != nil {
+		return rec, err
+	}
+
+	rec.Key, rec.Value, rec.Deadline = raw.Key, v, raw.Deadline
+
+	return rec, nil
+}
+
+//typeName returns the name of the type used as a type hint in snapshot headers
+func typeName[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
+
+//writeShortString writes string prefixed by its length as a single byte
+func writeShortString(w io.Writer, s string) error {
+	if len(s) > 255 {
+		s = s[:255]
+	}
+
+	if _, err := w.Write([]byte{byte(len(s))}); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, s)
+	return err
+}
+
+//readShortString reads string written by writeShortString
+func readShortString(r io.Reader) (string, error) {
+	var l [1]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return "", err
+	}
+
+	s := make([]byte, l[0])
+	if _, err := io.ReadFull(r, s); err != nil {
+		return "", err
+	}
+
+	return string(s), nil
+}
+
+//sealSnapshot wraps the snapshot into an envelope, encrypting and/or signing it according to the options supplied
+func sealSnapshot(plain []byte, opts SaveOptions) ([]byte, error) {
+	var flags byte
+	payload := plain
+	var nonce []byte
+
+	if opts.EncryptionKey != nil {
+		gcm, err := newGCM(opts.EncryptionKey)
+		if err != nil {
+			return nil, err
+		}
+
+		nonce = make([]byte, gcm.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return nil, err
+		}
+
+		flags |= envelopeEncrypted
+		payload = gcm.Seal(nil, nonce, plain, envelopeMagic[:])
+	}
+
+	if opts.SigningKey != nil {
+		flags |= envelopeSigned
+	}
+
+	out := bytes.Buffer{}
+	out.Write(envelopeMagic[:])
+	out.WriteByte(flags)
+	out.Write(nonce)
+	binary.Write(&out, binary.BigEndian, uint64(len(payload)))
+	out.Write(payload)
+
+	if opts.SigningKey != nil {
+		mac := hmac.New(sha256.New, opts.SigningKey)
+		mac.Write(out.Bytes())
+		out.Write(mac.Sum(nil))
+	}
+
+	return out.Bytes(), nil
+}
+
+//openSnapshot unwraps the envelope of the snapshot if there is one, verifying its signature and decrypting it.
+//Snapshots without an envelope are returned as they are, unless the options require them to be protected
+func openSnapshot(r *bufio.Reader, opts LoadOptions) (*bufio.Reader, error) {
+	magic, err := r.Peek(len(envelopeMagic))
+	if err != nil || !bytes.Equal(magic, envelopeMagic[:]) {
+		if opts.SigningKey != nil {
+			return nil, ErrSnapshotSignature
+		}
+
+		if opts.EncryptionKey != nil {
+			return nil, ErrSnapshotDecryption
+		}
+
+		return r, nil
+	}
+
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) < len(envelopeMagic)+1 {
+		return nil, ErrCorruptSnapshot
+	}
+
+	flags := raw[len(envelopeMagic)]
+	body := raw
+
+	if flags&envelopeSigned != 0 {
+		if opts.SigningKey == nil || len(raw) < sha256.Size {
+			return nil, ErrSnapshotSignature
+		}
+
+		body = raw[:len(raw)-sha256.Size]
+
+		mac := hmac.New(sha256.New, opts.SigningKey)
+		mac.Write(body)
+
+		if !hmac.Equal(mac.Sum(nil), raw[len(body):]) {
+			return nil, ErrSnapshotSignature
+		}
+	} else if opts.SigningKey != nil {
+		return nil, ErrSnapshotSignature
+	}
+
+	if flags&envelopeEncrypted == 0 {
+		if opts.EncryptionKey != nil {
+			return nil, ErrSnapshotDecryption
+		}
+
+		payload, err := envelopePayload(body, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return bufio.NewReader(bytes.NewReader(payload)), nil
+	}
+
+	if opts.EncryptionKey == nil {
+		return nil, ErrSnapshotDecryption
+	}
+
+	gcm, err := newGCM(opts.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := envelopePayload(body, gcm.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := body[len(envelopeMagic)+1 : len(envelopeMagic)+1+gcm.NonceSize()]
+
+	plain, err := gcm.Open(nil, nonce, payload, envelopeMagic[:])
+	if err != nil {
+		return nil, ErrSnapshotDecryption
+	}
+
+	return bufio.NewReader(bytes.NewReader(plain)), nil
+}
+
+//envelopePayload returns the payload of the envelope, verifying that its length matches the one recorded
+func envelopePayload(body []byte, nonceSize int) ([]byte, error) {
+	pos := len(envelopeMagic) + 1 + nonceSize
+
+	if len(body) < pos+8 {
+		return nil, ErrCorruptSnapshot
+	}
+
+	payload := body[pos+8:]
+
+	if binary.BigEndian.Uint64(body[pos:]) != uint64(len(payload)) {
+		return nil, ErrCorruptSnapshot
+	}
+
+	return payload, nil
+}
+
+//newGCM creates AES-GCM cipher using the key supplied
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
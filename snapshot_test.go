@@ -0,0 +1,303 @@
+package cacheMachine
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"runtime"
+	"testing"
+)
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//Value with unexported fields that gob can't encode
+type secretValue struct {
+	secret string
+}
+
+//Encodes secretValue as its secret
+type secretCodec struct{}
+
+func (secretCodec) MarshalEntry(v secretValue) ([]byte, error) { return []byte(v.secret), nil }
+
+func (secretCodec) UnmarshalEntry(b []byte) (secretValue, error) {
+	return secretValue{secret: string(b)}, nil
+}
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_Save(t *testing.T) {
+	c := initializeFullCache(10, nil)
+
+	buf := bytes.Buffer{}
+
+	if err := c.Save(&buf); err != nil {
+		t.Errorf("Expected snapshot to be saved, got error: %s", err)
+	}
+
+	h := snapshotHeader{}
+
+	if err := h.read(&buf); err != nil {
+		t.Errorf("Expected to read snapshot header, got error: %s", err)
+	}
+
+	if h.Count != 10 || h.KeyType != "int" || h.ValueType != "int" || h.Version != SnapshotVersion {
+		t.Errorf("Unexpected snapshot header: %+v", h)
+	}
+}
+
+func TestCache_Load(t *testing.T) {
+	c1 := initializeFullCache(10, nil)
+
+	buf := bytes.Buffer{}
+	if err := c1.Save(&buf); err != nil {
+		t.Fatalf("Expected snapshot to be saved, got error: %s", err)
+	}
+	snapshot := buf.Bytes()
+
+	c2 := initializeFullCache(0, nil)
+
+	if err := c2.Load(bytes.NewReader(snapshot)); err != nil {
+		t.Errorf("Expected snapshot to be loaded, got error: %s", err)
+	}
+
+	if c2.Count() != 10 || c2.GetValue(7) != 7 {
+		t.Errorf("Expected to have 10 items in the cache including key 7, got %d items", c2.Count())
+	}
+
+	c3 := New[int, string](nil)
+
+	var incompatible *IncompatibleSnapshotError
+	if err := c3.Load(bytes.NewReader(snapshot)); !errors.As(err, &incompatible) || incompatible.Field != "value type" {
+		t.Errorf("Expected to get IncompatibleSnapshotError for the value type, got %v", err)
+	}
+
+	corrupt := append([]byte{}, snapshot...)
+	corrupt[len(corrupt)-1] ^= 0xff
+
+	c4 := initializeFullCache(0, nil)
+
+	if err := c4.Load(bytes.NewReader(corrupt)); err != ErrCorruptSnapshot {
+		t.Errorf("Expected to get ErrCorruptSnapshot, got %v", err)
+	}
+
+	if c4.Count() != 0 {
+		t.Errorf("Expected nothing to be loaded from corrupt snapshot, got %d items", c4.Count())
+	}
+}
+
+func TestCache_LoadWithOptions(t *testing.T) {
+	c1 := initializeFullCache(snapshotSegmentSize*3, nil)
+
+	buf := bytes.Buffer{}
+	if err := c1.Save(&buf); err != nil {
+		t.Fatalf("Expected snapshot to be saved, got error: %s", err)
+	}
+	snapshot := buf.Bytes()
+
+	//Locating the second segment right after the snapshot header and the first segment
+	h := snapshotHeader{}
+	r := bytes.NewReader(snapshot)
+	h.read(r)
+	firstSegment := len(snapshot) - r.Len()
+	firstLength := binary.BigEndian.Uint32(snapshot[firstSegment+16:])
+	secondSegment := firstSegment + segmentHeaderSize + int(firstLength) + 4
+
+	snapshot[secondSegment+segmentHeaderSize+10] ^= 0xff
+
+	c2 := initializeFullCache(0, nil)
+
+	if err := c2.Load(bytes.NewReader(snapshot)); err != ErrCorruptSnapshot {
+		t.Errorf("Expected to get ErrCorruptSnapshot without SkipCorrupt, got %v", err)
+	}
+
+	rep, err := c2.LoadWithOptions(bytes.NewReader(snapshot), LoadOptions{SkipCorrupt: true})
+
+	if err != nil {
+		t.Errorf("Expected corrupt segment to be skipped, got error: %s", err)
+	}
+
+	if rep.Loaded != snapshotSegmentSize*2 || c2.Count() != snapshotSegmentSize*2 {
+		t.Errorf("Expected to load %d entries, got %d reported and %d in the cache", snapshotSegmentSize*2, rep.Loaded, c2.Count())
+	}
+
+	if len(rep.Dropped) != 1 || rep.Dropped[0].First != snapshotSegmentSize || rep.Dropped[0].Count != snapshotSegmentSize {
+		t.Errorf("Expected records %d to %d to be reported as dropped, got %+v", snapshotSegmentSize, snapshotSegmentSize*2, rep.Dropped)
+	}
+
+	//Corrupting the header of the second segment forces the reader to resynchronize on the third one
+	snapshot[secondSegment+5] ^= 0xff
+
+	c3 := initializeFullCache(0, nil)
+	rep, err = c3.LoadWithOptions(bytes.NewReader(snapshot), LoadOptions{SkipCorrupt: true})
+
+	if err != nil || rep.Loaded != snapshotSegmentSize*2 || len(rep.Dropped) != 1 {
+		t.Errorf("Expected to load %d entries and drop one range, got %d loaded, %+v dropped and error %v", snapshotSegmentSize*2, rep.Loaded, rep.Dropped, err)
+	}
+}
+
+func TestCache_LoadWithOptions_partialSegment(t *testing.T) {
+	c1 := initializeFullCache(snapshotSegmentSize*3, nil)
+
+	buf := bytes.Buffer{}
+	if err := c1.Save(&buf); err != nil {
+		t.Fatalf("Expected snapshot to be saved, got error: %s", err)
+	}
+	snapshot := buf.Bytes()
+
+	h := snapshotHeader{}
+	r := bytes.NewReader(snapshot)
+	h.read(r)
+	firstSegment := len(snapshot) - r.Len()
+	firstLength := binary.BigEndian.Uint32(snapshot[firstSegment+16:])
+	secondSegment := firstSegment + segmentHeaderSize + int(firstLength) + 4
+
+	//Second segment claims one record more than it holds, so decoding fails after all of its records are read
+	sh := segmentHeader{}
+	sh.parse(snapshot[secondSegment:])
+	sh.Count++
+	copy(snapshot[secondSegment:], sh.bytes())
+
+	c2 := initializeFullCache(0, nil)
+	rep, err := c2.LoadWithOptions(bytes.NewReader(snapshot), LoadOptions{SkipCorrupt: true})
+
+	if err != nil || len(rep.Dropped) != 1 || rep.Dropped[0].First != snapshotSegmentSize {
+		t.Fatalf("Expected the second segment to be dropped, got %+v dropped and error %v", rep.Dropped, err)
+	}
+
+	if rep.Loaded != snapshotSegmentSize*2 || c2.Count() != snapshotSegmentSize*2 {
+		t.Errorf("Expected none of the records of the dropped segment to be loaded, got %d reported and %d in the cache", rep.Loaded, c2.Count())
+	}
+}
+
+func TestCache_LoadWithOptions_hugeSegment(t *testing.T) {
+	c1 := initializeFullCache(snapshotSegmentSize, nil)
+
+	buf := bytes.Buffer{}
+	if err := c1.Save(&buf); err != nil {
+		t.Fatalf("Expected snapshot to be saved, got error: %s", err)
+	}
+	snapshot := buf.Bytes()
+
+	h := snapshotHeader{}
+	r := bytes.NewReader(snapshot)
+	h.read(r)
+	segment := len(snapshot) - r.Len()
+
+	//Segment claims about 4GB of records that the stream doesn't hold
+	sh := segmentHeader{}
+	sh.parse(snapshot[segment:])
+	sh.Count, sh.Length = 1<<32-1, 1<<32-16
+	copy(snapshot[segment:], sh.bytes())
+
+	before := runtime.MemStats{}
+	runtime.ReadMemStats(&before)
+
+	c2 := initializeFullCache(0, nil)
+	rep, err := c2.LoadWithOptions(bytes.NewReader(snapshot), LoadOptions{SkipCorrupt: true})
+
+	after := runtime.MemStats{}
+	runtime.ReadMemStats(&after)
+
+	if err != nil || len(rep.Dropped) != 1 || c2.Count() != 0 {
+		t.Errorf("Expected the truncated segment to be dropped, got %+v dropped and error %v", rep.Dropped, err)
+	}
+
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64<<20 {
+		t.Errorf("Expected sizes claimed by the segment not to be allocated upfront, got %d bytes allocated", allocated)
+	}
+}
+
+func TestCache_SaveWithOptions(t *testing.T) {
+	c1 := New[string, string](nil)
+	c1.Add("secret", "top secret value")
+
+	encryptionKey := []byte("0123456789abcdef0123456789abcdef")
+	signingKey := []byte("signing key")
+
+	buf := bytes.Buffer{}
+	if err := c1.SaveWithOptions(&buf, SaveOptions{EncryptionKey: encryptionKey, SigningKey: signingKey}); err != nil {
+		t.Fatalf("Expected snapshot to be saved, got error: %s", err)
+	}
+	snapshot := buf.Bytes()
+
+	if bytes.Contains(snapshot, []byte("top secret value")) {
+		t.Errorf("Expected snapshot to be encrypted, but the value is readable")
+	}
+
+	c2 := New[string, string](nil)
+
+	if err := c2.Load(bytes.NewReader(snapshot)); err != ErrSnapshotSignature {
+		t.Errorf("Expected to get ErrSnapshotSignature without the signing key, got %v", err)
+	}
+
+	if _, err := c2.LoadWithOptions(bytes.NewReader(snapshot), LoadOptions{SigningKey: signingKey}); err != ErrSnapshotDecryption {
+		t.Errorf("Expected to get ErrSnapshotDecryption without the encryption key, got %v", err)
+	}
+
+	opts := LoadOptions{EncryptionKey: encryptionKey, SigningKey: signingKey}
+
+	if _, err := c2.LoadWithOptions(bytes.NewReader(snapshot), opts); err != nil {
+		t.Errorf("Expected snapshot to be loaded, got error: %s", err)
+	}
+
+	if v := c2.GetValue("secret"); v != "top secret value" {
+		t.Errorf("Expected to get value %q, got %q", "top secret value", v)
+	}
+
+	tampered := append([]byte{}, snapshot...)
+	tampered[len(tampered)/2] ^= 0xff
+
+	if _, err := c2.LoadWithOptions(bytes.NewReader(tampered), opts); err != ErrSnapshotSignature {
+		t.Errorf("Expected to get ErrSnapshotSignature for tampered snapshot, got %v", err)
+	}
+
+	plain := bytes.Buffer{}
+	c1.Save(&plain)
+
+	if _, err := c2.LoadWithOptions(&plain, opts); err != ErrSnapshotSignature {
+		t.Errorf("Expected unsigned snapshot to be refused, got %v", err)
+	}
+}
+
+func TestCache_SetEntryCodec(t *testing.T) {
+	c1 := New[string, secretValue](nil)
+	c1.SetEntryCodec(secretCodec{})
+	c1.Add("key", secretValue{secret: "value"})
+
+	buf := bytes.Buffer{}
+	if err := c1.Save(&buf); err != nil {
+		t.Fatalf("Expected snapshot to be saved using the codec, got error: %s", err)
+	}
+	snapshot := buf.Bytes()
+
+	c2 := New[string, secretValue](nil)
+
+	var incompatible *IncompatibleSnapshotError
+	if err := c2.Load(bytes.NewReader(snapshot)); !errors.As(err, &incompatible) || incompatible.Field != "codec" {
+		t.Errorf("Expected to get IncompatibleSnapshotError for the codec, got %v", err)
+	}
+
+	c2.SetEntryCodec(secretCodec{})
+
+	if err := c2.Load(bytes.NewReader(snapshot)); err != nil {
+		t.Errorf("Expected snapshot to be loaded using the codec, got error: %s", err)
+	}
+
+	if v := c2.GetValue("key"); v.secret != "value" {
+		t.Errorf("Expected to get value %q, got %q", "value", v.secret)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_Save(b *testing.B) {
+	c := initializeFullCache(100, nil)
+
+	buf := bytes.Buffer{}
+
+	for n := 0; n < b.N; n++ {
+		buf.Reset()
+		c.Save(&buf)
+	}
+}
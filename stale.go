@@ -0,0 +1,75 @@
+package cacheMachine
+
+//===========[STRUCTS]==================================================================================================
+
+//Expired entry kept by Requirements.StaleFor
+type staleEntry[TValue any] struct {
+	val TValue
+
+	//Drops the entry once StaleFor passes
+	timer Timer
+}
+
+//------PRIVATE------
+
+//keepStale keeps the value of the expired key for Requirements.StaleFor. This method has no mutex protection
+func (c *Cache[TKey, TValue]) keepStale(key TKey, val TValue) {
+	c.dropStale(key)
+
+	if c.stale == nil {
+		c.stale = make(map[TKey]*staleEntry[TValue])
+	}
+
+	s := &staleEntry[TValue]{val: val}
+	s.timer = c.cache.Requirements.TimeSource.AfterFunc(c.cache.Requirements.StaleFor, func() {
+		c.mx.Lock()
+		defer c.mx.Unlock()
+
+		if c.stale[key] == s {
+			delete(c.stale, key)
+		}
+	})
+
+	c.stale[key] = s
+}
+
+//dropStale forgets the stale value of the key, if there is one. This method has no mutex protection
+func (c *Cache[TKey, TValue]) dropStale(key TKey) {
+	if s, exist := c.stale[key]; exist {
+		s.timer.Stop()
+		delete(c.stale, key)
+	}
+}
+
+//dropAllStale forgets all the stale values. This method has no mutex protection
+func (c *Cache[TKey, TValue]) dropAllStale() {
+	for _, s := range c.stale {
+		s.timer.Stop()
+	}
+
+	c.stale = nil
+}
+
+//------PUBLIC------
+
+//GetStale returns the value of the key the same way as Get, but falls back on the value the key had before it
+//expired, if it's still kept by Requirements.StaleFor. Stale reports whether the value returned has expired already
+func (c *Cache[TKey, TValue]) GetStale(key TKey) (val TValue, stale bool, ok bool) {
+	if v, found := c.Get(key); found {
+		return v, false, true
+	}
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	//Entries found past their timeout before they are removed are stale just the same
+	if e, exist := c.data[key]; exist && c.cache.Requirements.StaleFor > 0 && !c.live(e) {
+		return e.Val, true, true
+	}
+
+	if s, exist := c.stale[key]; exist {
+		return s.val, true, true
+	}
+
+	return val, false, false
+}
@@ -0,0 +1,57 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestRequirements_StaleFor(t *testing.T) {
+	c := New[string, int](&Requirements{StaleFor: time.Millisecond * 100})
+
+	c.AddWithTimeout("a", 1, time.Millisecond*10)
+	c.AddWithTimeout("b", 2, time.Millisecond*10)
+
+	if v, stale, ok := c.GetStale("a"); !ok || stale || v != 1 {
+		t.Errorf("Expected fresh value 1, got %d, stale %t, found %t", v, stale, ok)
+	}
+
+	time.Sleep(time.Millisecond * 50)
+
+	if c.Exist("a") || c.Count() != 0 {
+		t.Errorf("Expected stale entries to be missing from the cache")
+	}
+
+	if v, stale, ok := c.GetStale("a"); !ok || !stale || v != 1 {
+		t.Errorf("Expected stale value 1, got %d, stale %t, found %t", v, stale, ok)
+	}
+
+	c.Add("b", 3)
+	if v, stale, _ := c.GetStale("b"); stale || v != 3 {
+		t.Errorf("Expected the stale value to be dropped once the key was added again, got %d, stale %t", v, stale)
+	}
+
+	time.Sleep(time.Millisecond * 150)
+
+	if _, _, ok := c.GetStale("a"); ok {
+		t.Errorf("Expected the stale value to be dropped after StaleFor")
+	}
+
+	if d := c.Describe(); d.Stale != 0 {
+		t.Errorf("Expected no stale entries left, got %d", d.Stale)
+	}
+}
+
+func TestCache_GetStale(t *testing.T) {
+	c := New[string, int](&Requirements{StaleFor: time.Minute})
+
+	c.AddWithTimeout("a", 1, time.Millisecond)
+	time.Sleep(time.Millisecond * 20)
+
+	c.Remove("a")
+
+	if _, _, ok := c.GetStale("a"); ok {
+		t.Errorf("Expected the stale value to be dropped once the key was removed")
+	}
+}
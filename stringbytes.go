@@ -0,0 +1,186 @@
+package cacheMachine
+
+import (
+	"sync"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//Number of shards of StringByteCache. Must be a power of 2
+const stringByteShards = 32
+
+//Parameters of the FNV-1a hash picking the shard of the key
+const (
+	fnvOffset32 = 2166136261
+	fnvPrime32  = 16777619
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//StringByteCache is a cache of byte slices by string keys, the most common shape of caches in services, specialized
+//to avoid the overhead of the generic Cache. Values are stored inline in maps split into shards by a hash of the
+//key, so that writes of different keys rarely wait for each other, and View reads values without copying them. Same
+//as Compact, it supports none of the features that need per-entry state: timeouts, eviction, priorities, pins and
+//events
+type StringByteCache struct {
+	shards [stringByteShards]stringByteShard
+}
+
+//Single shard of StringByteCache
+type stringByteShard struct {
+	data map[string][]byte
+	mx   sync.RWMutex
+}
+
+//------PRIVATE------
+
+//shard returns the shard the key belongs to
+func (c *StringByteCache) shard(key string) *stringByteShard {
+	h := uint32(fnvOffset32)
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= fnvPrime32
+	}
+
+	return &c.shards[h&(stringByteShards-1)]
+}
+
+//------PUBLIC------
+
+//Add inserts a copy of the value under the key, replacing the value already present, so that the caller can reuse
+//the slice supplied
+func (c *StringByteCache) Add(key string, val []byte) {
+	cpy := append([]byte(nil), val...)
+
+	s := c.shard(key)
+	s.mx.Lock()
+	s.data[key] = cpy
+	s.mx.Unlock()
+}
+
+//AddBulk inserts copies of all the key:value pairs supplied
+func (c *StringByteCache) AddBulk(d map[string][]byte) {
+	for k, v := range d {
+		c.Add(k, v)
+	}
+}
+
+//Get returns a copy of the value of the key and whether the key is present
+func (c *StringByteCache) Get(key string) ([]byte, bool) {
+	s := c.shard(key)
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	v, exist := s.data[key]
+	if !exist {
+		return nil, false
+	}
+
+	return append([]byte(nil), v...), true
+}
+
+//View calls the function supplied with the value of the key as it's stored, without copying it. The slice must not
+//be modified nor kept once the function returns, and the function must not use the cache. Returns false, without
+//calling the function, if the key is not present
+func (c *StringByteCache) View(key string, f func([]byte)) bool {
+	s := c.shard(key)
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	v, exist := s.data[key]
+	if exist {
+		f(v)
+	}
+
+	return exist
+}
+
+//Exist checks whether the key is present
+func (c *StringByteCache) Exist(key string) bool {
+	s := c.shard(key)
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	_, exist := s.data[key]
+	return exist
+}
+
+//Remove removes the key
+func (c *StringByteCache) Remove(key string) {
+	s := c.shard(key)
+	s.mx.Lock()
+	delete(s.data, key)
+	s.mx.Unlock()
+}
+
+//Count returns number of keys present
+func (c *StringByteCache) Count() int {
+	n := 0
+
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mx.RLock()
+		n += len(s.data)
+		s.mx.RUnlock()
+	}
+
+	return n
+}
+
+//GetAll returns copies of all the key:value pairs. Shards are copied one at a time, so the result is not a
+//consistent snapshot under concurrent writes
+func (c *StringByteCache) GetAll() map[string][]byte {
+	cpy := make(map[string][]byte)
+
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mx.RLock()
+		for k, v := range s.data {
+			cpy[k] = append([]byte(nil), v...)
+		}
+		s.mx.RUnlock()
+	}
+
+	return cpy
+}
+
+//GetAllAndRemove returns all the key:value pairs and empties the cache
+func (c *StringByteCache) GetAllAndRemove() map[string][]byte {
+	d := make(map[string][]byte)
+
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mx.Lock()
+		for k, v := range s.data {
+			d[k] = v
+		}
+		s.data = make(map[string][]byte)
+		s.mx.Unlock()
+	}
+
+	return d
+}
+
+//Reset empties the cache
+func (c *StringByteCache) Reset() {
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mx.Lock()
+		s.data = make(map[string][]byte)
+		s.mx.Unlock()
+	}
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//NewStringByteCache creates a cache specialized for byte slices by string keys. It can be merged with
+//Cache[string, []byte] both ways using Merge and MergeAndReset
+func NewStringByteCache() *StringByteCache {
+	c := &StringByteCache{}
+
+	for i := range c.shards {
+		c.shards[i].data = make(map[string][]byte)
+	}
+
+	return c
+}
@@ -0,0 +1,83 @@
+package cacheMachine
+
+import (
+	"strconv"
+	"testing"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestStringByteCache_Add(t *testing.T) {
+	c := NewStringByteCache()
+
+	buf := []byte("value")
+	c.Add("a", buf)
+	buf[0] = 'V'
+
+	if v, ok := c.Get("a"); !ok || string(v) != "value" {
+		t.Errorf("Expected the cache to keep its own copy of the value, got %q and %t", v, ok)
+	}
+
+	for i := 0; i < 100; i++ {
+		c.Add(strconv.Itoa(i), []byte{byte(i)})
+	}
+
+	if c.Count() != 101 {
+		t.Errorf("Expected 101 keys, got %d", c.Count())
+	}
+
+	c.Remove("a")
+
+	if c.Exist("a") {
+		t.Errorf("Expected the key to be removed")
+	}
+}
+
+func TestStringByteCache_View(t *testing.T) {
+	c := NewStringByteCache()
+	c.Add("a", []byte("value"))
+
+	var n int
+	if !c.View("a", func(b []byte) { n = len(b) }) || n != 5 {
+		t.Errorf("Expected View to see the value of 5 bytes, got %d", n)
+	}
+
+	if c.View("b", func(b []byte) { t.Errorf("Expected View not to call the function of a missing key") }) {
+		t.Errorf("Expected View to report the key missing")
+	}
+}
+
+func TestStringByteCache_Merge(t *testing.T) {
+	c := NewStringByteCache()
+	c.Add("a", []byte("1"))
+
+	main := New[string, []byte](nil)
+	MergeAndReset[string, []byte](&main, c)
+
+	if main.Count() != 1 || c.Count() != 0 {
+		t.Errorf("Expected the cache to be moved into the main cache, got %d and %d entries", main.Count(), c.Count())
+	}
+
+	Merge[string, []byte](c, &main)
+
+	if all := c.GetAll(); len(all) != 1 || string(all["a"]) != "1" {
+		t.Errorf("Expected the main cache to be merged back, got %v", all)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkStringByteCache_View(b *testing.B) {
+	c := NewStringByteCache()
+	keys := make([]string, 100)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		c.Add(keys[i], []byte("value"))
+	}
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		c.View(keys[n%100], func([]byte) {})
+	}
+}
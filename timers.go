@@ -0,0 +1,87 @@
+package cacheMachine
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+const (
+	//TimerOverflowLazy stores entries past Requirements.MaxTimers without a timer. They are no longer returned once
+	//their timeout passes and get removed by RemoveExpired or the janitor of CleanupInterval
+	TimerOverflowLazy TimerOverflow = iota
+
+	//TimerOverflowReject refuses to store new entries with a timeout past Requirements.MaxTimers
+	TimerOverflowReject
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//TimerOverflow defines what happens to entries with a timeout once Requirements.MaxTimers timers are scheduled
+type TimerOverflow int
+
+//------PRIVATE------
+
+//countTimer adds n to the number of timers scheduled by the cache
+func (e *entry[TValue]) countTimer(n int64) {
+	if e.timers != nil {
+		atomic.AddInt64(e.timers, n)
+	}
+}
+
+//startTimer schedules the timer removing the entry once the duration supplied passes, unless the janitor of
+//CleanupInterval removes entries instead. If MaxTimers timers are scheduled already, the entry is either left
+//without a timer or refused, in which case false is returned
+func (c *Cache[TKey, TValue]) startTimer(key TKey, e *entry[TValue], t time.Duration) bool {
+	r := &c.cache.Requirements
+	if r.CleanupInterval > 0 {
+		return true
+	}
+
+	if r.MaxTimers < 1 {
+		atomic.AddInt64(&c.timers, 1)
+	} else if !reserveTimer(&c.timers, int64(r.MaxTimers)) {
+		e.lazy = true
+		return r.TimerOverflow != TimerOverflowReject
+	}
+
+	e.lazy = false
+	e.timers = &c.timers
+	e.timer = r.TimeSource.AfterFunc(t, func() {
+		e.countTimer(-1)
+		c.expire(key, e)
+	})
+
+	return true
+}
+
+//------PUBLIC------
+
+//String returns name of the policy
+func (o TimerOverflow) String() string {
+	switch o {
+	case TimerOverflowLazy:
+		return "lazy"
+	case TimerOverflowReject:
+		return "reject"
+	default:
+		return "unknown"
+	}
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//reserveTimer increments the number of timers scheduled unless it's reached the limit. Returns false if it has
+func reserveTimer(timers *int64, limit int64) bool {
+	for {
+		n := atomic.LoadInt64(timers)
+		if n >= limit {
+			return false
+		}
+
+		if atomic.CompareAndSwapInt64(timers, n, n+1) {
+			return true
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestRequirements_MaxTimers(t *testing.T) {
+	c := New[int, int](&Requirements{MaxTimers: 2})
+
+	for i := 0; i < 4; i++ {
+		c.AddWithTimeout(i, i, time.Millisecond*20)
+	}
+
+	if d := c.Describe(); d.ActiveTimers != 2 || d.TimerOverflow != "lazy" {
+		t.Errorf("Expected 2 active timers with lazy overflow, got %d with %q", d.ActiveTimers, d.TimerOverflow)
+	}
+
+	if c.Count() != 4 {
+		t.Errorf("Expected all 4 entries to be stored, got %d", c.Count())
+	}
+
+	time.Sleep(time.Millisecond * 100)
+
+	for i := 0; i < 4; i++ {
+		if c.Exist(i) {
+			t.Errorf("Expected key %d to be expired", i)
+		}
+	}
+
+	if n := c.RemoveExpired(); n != 2 {
+		t.Errorf("Expected RemoveExpired to remove the 2 entries without a timer, got %d", n)
+	}
+
+	if d := c.Describe(); d.ActiveTimers != 0 || d.Entries != 0 {
+		t.Errorf("Expected no active timers nor entries, got %d timers and %d entries", d.ActiveTimers, d.Entries)
+	}
+}
+
+func TestRequirements_TimerOverflowReject(t *testing.T) {
+	c := New[int, int](&Requirements{MaxTimers: 1, TimerOverflow: TimerOverflowReject})
+
+	c.AddWithTimeout(1, 1, time.Minute)
+	c.AddWithTimeout(2, 2, time.Minute)
+	c.Add(3, 3)
+
+	if !c.Exist(1) || c.Exist(2) || !c.Exist(3) {
+		t.Errorf("Expected only the entry past the timer limit to be refused, got %v", c.GetAll())
+	}
+
+	c.Remove(1)
+	c.AddWithTimeout(2, 2, time.Minute)
+
+	if !c.Exist(2) {
+		t.Errorf("Expected the entry to be stored once the timer was released")
+	}
+
+	if d := c.Describe(); d.ActiveTimers != 1 {
+		t.Errorf("Expected 1 active timer, got %d", d.ActiveTimers)
+	}
+}
+
+func TestTimerOverflow_String(t *testing.T) {
+	if s := TimerOverflowReject.String(); s != "reject" {
+		t.Errorf("Expected %q, got %q", "reject", s)
+	}
+}
@@ -0,0 +1,148 @@
+package cacheMachine
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sync"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//TraceOpKind is the kind of operation recorded in a trace
+type TraceOpKind byte
+
+//Kinds of operations recorded in a trace
+const (
+	//Value added or replaced under the key
+	TraceAdd TraceOpKind = 'A'
+
+	//Key read, whether it was found or not
+	TraceGet TraceOpKind = 'G'
+
+	//Key removed on request of the user
+	TraceRemove TraceOpKind = 'R'
+)
+
+//Size of a single operation in the trace: kind followed by the key hash
+const traceOpSize = 1 + 8
+
+//TraceOp is a single operation recorded by RecordTrace. Keys are anonymized by hashing them with a salt that is
+//unique to every recording, so the same key always has the same hash within one trace only
+type TraceOp struct {
+	Kind TraceOpKind
+	Key  uint64
+}
+
+//Writes operations performed on the cache into the writer
+type tracer struct {
+	w    *bufio.Writer
+	salt [8]byte
+	mx   sync.Mutex
+}
+
+//------PRIVATE------
+
+//record writes the operation on the key to the trace. Writing stops silently at the first error
+func (t *tracer) record(kind TraceOpKind, key any) {
+	h := fnv.New64a()
+	h.Write(t.salt[:])
+	fmt.Fprint(h, key)
+
+	op := [traceOpSize]byte{byte(kind)}
+	binary.BigEndian.PutUint64(op[1:], h.Sum64())
+
+	t.mx.Lock()
+	if t.w != nil {
+		if _, err := t.w.Write(op[:]); err != nil {
+			t.w = nil
+		}
+	}
+	t.mx.Unlock()
+}
+
+//stop flushes all the operations recorded and stops recording
+func (t *tracer) stop() {
+	t.mx.Lock()
+	if t.w != nil {
+		t.w.Flush()
+		t.w = nil
+	}
+	t.mx.Unlock()
+}
+
+//trace records the operation if trace recording is on. It's safe to call this method under the read lock
+func (c *Cache[TKey, TValue]) trace(kind TraceOpKind, key TKey) {
+	if c.tracer != nil {
+		c.tracer.record(kind, key)
+	}
+}
+
+//------PUBLIC------
+
+//RecordTrace starts recording anonymized trace of the operations performed on the cache into the writer supplied.
+//Adding, reading and explicitly removing keys are recorded, while evictions and expirations are not, as they are
+//consequences of those operations. Supplying nil stops the recording and flushes the remaining operations
+func (c *Cache[TKey, TValue]) RecordTrace(w io.Writer) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.tracer != nil {
+		c.tracer.stop()
+		c.tracer = nil
+	}
+
+	if w == nil {
+		return
+	}
+
+	t := &tracer{w: bufio.NewWriter(w)}
+	rand.Read(t.salt[:])
+
+	c.tracer = t
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//ReadTrace reads all the operations of the trace recorded by RecordTrace
+func ReadTrace(r io.Reader) ([]TraceOp, error) {
+	var ops []TraceOp
+	br := bufio.NewReader(r)
+
+	for {
+		var op [traceOpSize]byte
+		if _, err := io.ReadFull(br, op[:]); err == io.EOF {
+			return ops, nil
+		} else if err != nil {
+			return ops, err
+		}
+
+		ops = append(ops, TraceOp{Kind: TraceOpKind(op[0]), Key: binary.BigEndian.Uint64(op[1:])})
+	}
+}
+
+//ReplayTrace performs the operations of the trace on the cache supplied, adding val for every recorded add, and
+//returns how many of the recorded reads found the key in the cache. Key hashes are used as int64 keys
+func ReplayTrace[TValue any](c *Cache[int64, TValue], ops []TraceOp, val TValue) (hits, misses int) {
+	for _, op := range ops {
+		key := int64(op.Key)
+
+		switch op.Kind {
+		case TraceAdd:
+			c.Add(key, val)
+		case TraceGet:
+			if _, ok := c.Get(key); ok {
+				hits++
+			} else {
+				misses++
+			}
+		case TraceRemove:
+			c.Remove(key)
+		}
+	}
+
+	return hits, misses
+}
@@ -0,0 +1,55 @@
+package cacheMachine
+
+import (
+	"bytes"
+	"testing"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_RecordTrace(t *testing.T) {
+	c := initializeFullCache(0, nil)
+
+	buf := bytes.Buffer{}
+	c.RecordTrace(&buf)
+
+	c.Add(1, 1)
+	c.Get(1)
+	c.Get(2)
+	c.Remove(1)
+
+	c.RecordTrace(nil)
+	c.Add(3, 3)
+
+	ops, err := ReadTrace(&buf)
+	if err != nil {
+		t.Fatalf("Expected to read the trace, got error: %s", err)
+	}
+
+	kinds := []TraceOpKind{TraceAdd, TraceGet, TraceGet, TraceRemove}
+
+	if len(ops) != len(kinds) {
+		t.Fatalf("Expected to have %d operations in the trace, got %d", len(kinds), len(ops))
+	}
+
+	for i, kind := range kinds {
+		if ops[i].Kind != kind {
+			t.Errorf("Expected operation %d to be %c, got %c", i, kind, ops[i].Kind)
+		}
+	}
+
+	if ops[0].Key != ops[1].Key || ops[0].Key == ops[2].Key || ops[0].Key == 1 {
+		t.Errorf("Expected the same keys to have the same anonymized hashes, got %+v", ops)
+	}
+}
+
+func TestReplayTrace(t *testing.T) {
+	ops := []TraceOp{{TraceAdd, 1}, {TraceGet, 1}, {TraceGet, 2}, {TraceRemove, 1}, {TraceGet, 1}}
+
+	c := New[int64, bool](nil)
+	hits, misses := ReplayTrace(&c, ops, true)
+
+	if hits != 1 || misses != 2 {
+		t.Errorf("Expected to have 1 hit and 2 misses, got %d and %d", hits, misses)
+	}
+}
@@ -0,0 +1,26 @@
+package cacheMachine
+
+//===========[STRUCTS]==================================================================================================
+
+//KeyTransitions are called as keys appear in and disappear from the cache, e.g. to maintain a search index or
+//counters derived from the keys without polling. Every transition is delivered exactly once, in the order the
+//transitions happen: a key that appears is always reported present before it's reported empty again, and replacing
+//the value of a key present is not a transition. Both are called while the cache is locked, so they must not use
+//the cache
+type KeyTransitions[TKey Key, TValue any] struct {
+	//Called once the key that was missing is added, along with the value added
+	OnPresent func(key TKey, val TValue)
+
+	//Called once the key that was present leaves the cache for whatever reason, including Reset and GetAllAndRemove
+	OnEmpty func(key TKey, reason RemovalReason)
+}
+
+//------PUBLIC------
+
+//SetKeyTransitions sets the functions called as keys appear in and disappear from the cache. Keys already present
+//are not reported. Nil stops the calls
+func (c *Cache[TKey, TValue]) SetKeyTransitions(t *KeyTransitions[TKey, TValue]) {
+	c.mx.Lock()
+	c.transitions = t
+	c.mx.Unlock()
+}
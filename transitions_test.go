@@ -0,0 +1,39 @@
+package cacheMachine
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_SetKeyTransitions(t *testing.T) {
+	c := New[int, int](&Requirements{MaxEntries: 2})
+
+	var transitions []string
+	c.SetKeyTransitions(&KeyTransitions[int, int]{
+		OnPresent: func(key, val int) { transitions = append(transitions, fmt.Sprintf("+%d=%d", key, val)) },
+		OnEmpty:   func(key int, r RemovalReason) { transitions = append(transitions, fmt.Sprintf("-%d %s", key, r)) },
+	})
+
+	c.Add(1, 1)
+	c.Add(1, 2)
+	c.Add(2, 2)
+	c.Add(3, 3)
+	c.Remove(2)
+	c.Remove(2)
+	c.AddWithTimeout(4, 4, time.Millisecond*10)
+
+	time.Sleep(time.Millisecond * 50)
+
+	c.Reset()
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	expected := fmt.Sprint([]string{"+1=1", "+2=2", "+3=3", "-1 evicted", "-2 explicit", "+4=4", "-4 expired", "-3 explicit"})
+	if got := fmt.Sprint(transitions); got != expected {
+		t.Errorf("Expected transitions %s, got %s", expected, got)
+	}
+}
@@ -0,0 +1,17 @@
+package cacheMachine
+
+import (
+	"time"
+)
+
+//------PUBLIC------
+
+//SetTTLFunc sets the function computing the timeout of every entry added without a timeout of its own, e.g. longer
+//for bigger values or premium tenants. If the function returns 0, DefaultTimeout applies, and if it returns a
+//negative duration, the entry has no timeout at all. Entries already present keep their timeouts. The function is
+//called under the write lock, so it must be fast and must not use the cache. Nil removes the function
+func (c *Cache[TKey, TValue]) SetTTLFunc(f func(TKey, TValue) time.Duration) {
+	c.mx.Lock()
+	c.ttlFunc = f
+	c.mx.Unlock()
+}
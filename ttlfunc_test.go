@@ -0,0 +1,48 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_SetTTLFunc(t *testing.T) {
+	c := New[string, int](&Requirements{DefaultTimeout: time.Minute})
+
+	c.SetTTLFunc(func(key string, val int) time.Duration {
+		switch key {
+		case "premium":
+			return time.Hour
+		case "forever":
+			return -1
+		}
+
+		return 0
+	})
+
+	c.Add("premium", 1)
+	c.Add("forever", 1)
+	c.Add("regular", 1)
+	c.AddWithTimeout("explicit", 1, time.Second)
+
+	expected := map[string]time.Duration{"premium": time.Hour, "forever": 0, "regular": time.Minute, "explicit": time.Second}
+
+	for key, ttl := range expected {
+		e := c.GetEntry(key)
+		if got := e.TTL(); got > ttl || got < ttl-time.Second {
+			t.Errorf("Expected %s to have TTL of %s, got %s", key, ttl, got)
+		}
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_SetTTLFunc(b *testing.B) {
+	c := New[int, int](nil)
+	c.SetTTLFunc(func(key, val int) time.Duration { return time.Minute })
+
+	for n := 0; n < b.N; n++ {
+		c.Add(n%100, n)
+	}
+}
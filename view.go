@@ -0,0 +1,96 @@
+package cacheMachine
+
+import (
+	"errors"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//ErrKeyNotFound is returned when the key is missing from the cache and there is no way to load it
+var ErrKeyNotFound = errors.New("cacheMachine: key not found")
+
+//===========[STRUCTS]==================================================================================================
+
+//View joins two caches by key, e.g. profiles and permissions of the same user, and returns combined values on Get.
+//Sides missing from their caches are loaded using the loaders of the view, if set, and added to their caches
+type View[TKey Key, TLeft, TRight, TResult any] struct {
+	Left  *Cache[TKey, TLeft]
+	Right *Cache[TKey, TRight]
+
+	//Load the value of a side that is missing from its cache. If nil, missing side makes Get return ErrKeyNotFound
+	LoadLeft  func(TKey) (TLeft, error)
+	LoadRight func(TKey) (TRight, error)
+
+	//Combines both sides into the result
+	Join func(TKey, TLeft, TRight) TResult
+}
+
+//------PUBLIC------
+
+//Get returns both sides of the key joined together. Missing sides are loaded and added to their caches. If a side
+//can't be found nor loaded, the error of the loader or ErrKeyNotFound is returned
+func (v *View[TKey, TLeft, TRight, TResult]) Get(key TKey) (TResult, error) {
+	var nilResult TResult
+
+	left, err := viewSide(v.Left, v.LoadLeft, key)
+	if err != nil {
+		return nilResult, err
+	}
+
+	right, err := viewSide(v.Right, v.LoadRight, key)
+	if err != nil {
+		return nilResult, err
+	}
+
+	return v.Join(key, left, right), nil
+}
+
+//GetBulk returns joined values of all the keys that could be found or loaded, along with the first error that
+//occurred, if any
+func (v *View[TKey, TLeft, TRight, TResult]) GetBulk(keys []TKey) (map[TKey]TResult, error) {
+	results := make(map[TKey]TResult, len(keys))
+	var firstErr error
+
+	for _, key := range keys {
+		r, err := v.Get(key)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		results[key] = r
+	}
+
+	return results, firstErr
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//viewSide returns the value of the key from the cache, loading and adding it to the cache if it's missing
+func viewSide[TKey Key, TValue any](c *Cache[TKey, TValue], load func(TKey) (TValue, error), key TKey) (TValue, error) {
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+
+	if load == nil {
+		var nilVal TValue
+		return nilVal, ErrKeyNotFound
+	}
+
+	v, err := load(key)
+	if err != nil {
+		return v, err
+	}
+
+	v = c.postProcess(key, v)
+	c.addLoaded(key, v, nil)
+
+	return v, nil
+}
+
+//NewView creates view joining the caches supplied using the join function
+func NewView[TKey Key, TLeft, TRight, TResult any](left *Cache[TKey, TLeft], right *Cache[TKey, TRight], join func(TKey, TLeft, TRight) TResult) *View[TKey, TLeft, TRight, TResult] {
+	return &View[TKey, TLeft, TRight, TResult]{Left: left, Right: right, Join: join}
+}
@@ -0,0 +1,91 @@
+package cacheMachine
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//Result of joining the test caches
+type viewResult struct {
+	name  string
+	admin bool
+}
+
+//newTestView joins cache of names with cache of permissions
+func newTestView() (*View[int, string, bool, viewResult], *Cache[int, string], *Cache[int, bool]) {
+	names := New[int, string](nil)
+	admins := New[int, bool](nil)
+
+	v := NewView(&names, &admins, func(k int, name string, admin bool) viewResult {
+		return viewResult{name: name, admin: admin}
+	})
+
+	return v, &names, &admins
+}
+
+//===========[TESTING]====================================================================================================
+
+func TestView_Get(t *testing.T) {
+	v, names, admins := newTestView()
+
+	names.Add(1, "one")
+	admins.Add(1, true)
+	names.Add(2, "two")
+
+	if r, err := v.Get(1); err != nil || r.name != "one" || !r.admin {
+		t.Errorf("Expected to get joined value of key 1, got %+v and error %v", r, err)
+	}
+
+	if _, err := v.Get(2); err != ErrKeyNotFound {
+		t.Errorf("Expected to get ErrKeyNotFound for key missing its right side, got %v", err)
+	}
+
+	v.LoadRight = func(k int) (bool, error) { return false, nil }
+
+	if r, err := v.Get(2); err != nil || r.name != "two" || r.admin {
+		t.Errorf("Expected missing right side of key 2 to be loaded, got %+v and error %v", r, err)
+	}
+
+	if !admins.Exist(2) {
+		t.Errorf("Expected loaded side to be added to its cache, but it was not")
+	}
+
+	failure := errors.New("backend down")
+	v.LoadLeft = func(k int) (string, error) { return "", failure }
+
+	if _, err := v.Get(3); err != failure {
+		t.Errorf("Expected to get error of the loader, got %v", err)
+	}
+}
+
+func TestView_GetBulk(t *testing.T) {
+	v, names, _ := newTestView()
+
+	v.LoadRight = func(k int) (bool, error) { return k%2 == 0, nil }
+
+	for i := 0; i < 5; i++ {
+		names.Add(i, strconv.Itoa(i))
+	}
+
+	results, err := v.GetBulk([]int{0, 1, 2, 3, 4, 5})
+
+	if len(results) != 5 || err != ErrKeyNotFound || !results[4].admin {
+		t.Errorf("Expected to get 5 joined values and ErrKeyNotFound for key 5, got %+v and error %v", results, err)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkView_Get(b *testing.B) {
+	v, names, admins := newTestView()
+
+	names.Add(1, "one")
+	admins.Add(1, true)
+
+	for n := 0; n < b.N; n++ {
+		v.Get(1)
+	}
+}
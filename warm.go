@@ -0,0 +1,34 @@
+package cacheMachine
+
+import (
+	"context"
+	"sync"
+)
+
+//------PUBLIC------
+
+//WaitWarm blocks until the cache holds at least minEntries entries, e.g. to hold back traffic until the warmup
+//completes. Returns the error of the context if it's done first
+func (c *Cache[TKey, TValue]) WaitWarm(ctx context.Context, minEntries int) error {
+	warm := make(chan struct{})
+	once := sync.Once{}
+
+	//Listeners run under the lock, so the entries can be counted safely
+	unsubscribe := c.Subscribe(func(e Event[TKey]) {
+		if e.Kind == EventAdd && len(c.data) >= minEntries {
+			once.Do(func() { close(warm) })
+		}
+	})
+	defer unsubscribe()
+
+	if c.Count() >= minEntries {
+		return nil
+	}
+
+	select {
+	case <-warm:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
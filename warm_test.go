@@ -0,0 +1,48 @@
+package cacheMachine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_WaitWarm(t *testing.T) {
+	c := initializeFullCache(5, nil)
+
+	if err := c.WaitWarm(context.Background(), 5); err != nil {
+		t.Errorf("Expected the cache to be warm already, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(time.Millisecond * 20)
+		for i := 5; i < 10; i++ {
+			c.Add(i, i)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := c.WaitWarm(ctx, 10); err != nil || c.Count() < 10 {
+		t.Errorf("Expected to wait until the cache has %d entries, got %d entries and error %v", 10, c.Count(), err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+
+	if err := c.WaitWarm(ctx, 100); err != context.DeadlineExceeded {
+		t.Errorf("Expected to get context.DeadlineExceeded, got %v", err)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_WaitWarm(b *testing.B) {
+	c := initializeFullCache(100, nil)
+
+	for n := 0; n < b.N; n++ {
+		c.WaitWarm(context.Background(), 100)
+	}
+}
@@ -0,0 +1,91 @@
+package cacheMachine
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+//------PUBLIC------
+
+//WarmFromReader loads the keys read from r, one per line, using GetOrLoad with the loader supplied, e.g. for nightly
+//pre-warming from a file or a pipe. Every line is turned into a key by parse, empty lines are skipped. The line is
+//only valid until parse returns, so it must be copied to be retained. At most concurrency keys are loaded at the same
+//time and reading waits for a free worker, so the list of keys is never held in memory as a whole. Stops at the first
+//error of reading, parsing or loading and returns it along with the number of keys loaded
+func (c *Cache[TKey, TValue]) WarmFromReader(ctx context.Context, r io.Reader, parse func([]byte) (TKey, error), loader Loader[TKey, TValue], concurrency int) (int, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var loaded int64
+	var firstErr error
+	errOnce := sync.Once{}
+
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	keys := make(chan TKey)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for key := range keys {
+				if _, err := c.GetOrLoad(ctx, key, loader); err != nil {
+					fail(err)
+					continue
+				}
+
+				atomic.AddInt64(&loaded, 1)
+			}
+		}()
+	}
+
+	scanner := bufio.NewScanner(r)
+
+read:
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) < 1 {
+			continue
+		}
+
+		key, err := parse(line)
+		if err != nil {
+			fail(err)
+			break
+		}
+
+		select {
+		case keys <- key:
+		case <-ctx.Done():
+			break read
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		fail(err)
+	}
+
+	close(keys)
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		//The parent context was done before all the keys were read
+		firstErr = ctx.Err()
+	}
+
+	return int(loaded), firstErr
+}
@@ -0,0 +1,52 @@
+package cacheMachine
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_WarmFromReader(t *testing.T) {
+	c := New[int, string](nil)
+
+	parse := func(b []byte) (int, error) { return strconv.Atoi(string(b)) }
+	loader := func(ctx context.Context, key int) (string, error) { return strconv.Itoa(key * 2), nil }
+
+	n, err := c.WarmFromReader(context.Background(), strings.NewReader("1\n2\n\n3\n4\n5\n"), parse, loader, 2)
+
+	if err != nil || n != 5 || c.Count() != 5 || c.GetValue(3) != "6" {
+		t.Errorf("Expected 5 keys to be loaded, got %d loaded, %d in the cache and error %v", n, c.Count(), err)
+	}
+
+	if _, err := c.WarmFromReader(context.Background(), strings.NewReader("6\nseven\n"), parse, loader, 2); err == nil {
+		t.Errorf("Expected to get the parse error")
+	}
+
+	loadErr := errors.New("backend is down")
+	failing := func(ctx context.Context, key int) (string, error) { return "", loadErr }
+
+	if _, err := c.WarmFromReader(context.Background(), strings.NewReader("10\n11\n12\n"), parse, failing, 1); err != loadErr {
+		t.Errorf("Expected to get the error of the loader, got %v", err)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_WarmFromReader(b *testing.B) {
+	lines := strings.Builder{}
+	for i := 0; i < 1000; i++ {
+		lines.WriteString(strconv.Itoa(i) + "\n")
+	}
+
+	parse := func(b []byte) (int, error) { return strconv.Atoi(string(b)) }
+	loader := func(ctx context.Context, key int) (int, error) { return key, nil }
+
+	for n := 0; n < b.N; n++ {
+		c := New[int, int](nil)
+		c.WarmFromReader(context.Background(), strings.NewReader(lines.String()), parse, loader, 8)
+	}
+}
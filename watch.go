@@ -0,0 +1,105 @@
+package cacheMachine
+
+import (
+	"context"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//KV is a key along with its value
+type KV[TKey Key, TValue any] struct {
+	Key   TKey
+	Value TValue
+}
+
+//------PUBLIC------
+
+//Watch returns a channel receiving every change made to the cache until the context is done, at which point the
+//subscription is removed and the channel is closed. Events are delivered in the order the changes were made, but
+//they are dropped if the buffer of the channel is full, because the cache never waits for a slow consumer
+func (c *Cache[TKey, TValue]) Watch(ctx context.Context, buffer int) <-chan Event[TKey] {
+	ch := make(chan Event[TKey], buffer)
+
+	unsubscribe := c.Subscribe(func(e Event[TKey]) {
+		select {
+		case ch <- e:
+		default:
+		}
+	})
+
+	go func() {
+		<-ctx.Done()
+
+		//No event can be sent once unsubscribe returns, since events are sent under the lock it takes
+		unsubscribe()
+		close(ch)
+	}()
+
+	return ch
+}
+
+//WatchExpired returns a channel receiving the entries removed because their timeout, time bucket or idle time has
+//passed, e.g. to recompute them, until the context is done, at which point the channel is closed. Entries are
+//dropped if the buffer of the channel is full, the same way as with Watch
+func (c *Cache[TKey, TValue]) WatchExpired(ctx context.Context, buffer int) <-chan KV[TKey, TValue] {
+	ch := make(chan KV[TKey, TValue], buffer)
+
+	c.mx.Lock()
+	if c.expiryListeners == nil {
+		c.expiryListeners = make(map[int]func(TKey, TValue))
+	}
+
+	id := c.nextListener
+	c.nextListener++
+	c.expiryListeners[id] = func(key TKey, val TValue) {
+		select {
+		case ch <- KV[TKey, TValue]{Key: key, Value: val}:
+		default:
+		}
+	}
+	c.mx.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		c.mx.Lock()
+		delete(c.expiryListeners, id)
+		c.mx.Unlock()
+
+		close(ch)
+	}()
+
+	return ch
+}
+
+//GetWait returns the value of the key, waiting for the key to be added if it's missing. Returns the error of the
+//context if it's done before the key is added
+func (c *Cache[TKey, TValue]) GetWait(ctx context.Context, key TKey) (TValue, error) {
+	added := make(chan TValue, 1)
+
+	//Subscribing before the key is looked up, so that an add made in between can't be missed
+	unsubscribe := c.Subscribe(func(e Event[TKey]) {
+		if e.Kind != EventAdd || e.Key != key {
+			return
+		}
+
+		//Listeners are called under the write lock after the entry is stored
+		select {
+		case added <- c.data[key].Val:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+
+	select {
+	case v := <-added:
+		return v, nil
+	case <-ctx.Done():
+		var nilVal TValue
+		return nilVal, ctx.Err()
+	}
+}
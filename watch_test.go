@@ -0,0 +1,158 @@
+package cacheMachine
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//waitGoroutines waits for the number of goroutines to drop to n at most, returning the number of goroutines left
+func waitGoroutines(n int) int {
+	for i := 0; i < 100 && runtime.NumGoroutine() > n; i++ {
+		time.Sleep(time.Millisecond)
+	}
+
+	return runtime.NumGoroutine()
+}
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_Watch(t *testing.T) {
+	c := New[int, int](nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := c.Watch(ctx, 10)
+
+	c.Add(1, 1)
+	c.Remove(1)
+
+	if e := <-ch; e.Kind != EventAdd || e.Key != 1 {
+		t.Errorf("Expected add of key 1, got %+v", e)
+	}
+
+	if e := <-ch; e.Kind != EventRemove || e.Key != 1 {
+		t.Errorf("Expected removal of key 1, got %+v", e)
+	}
+
+	cancel()
+
+	for range ch {
+	}
+
+	c.Add(2, 2)
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if n := len(c.listeners); n != 0 {
+		t.Errorf("Expected no listeners after the context is done, got %d", n)
+	}
+}
+
+func TestCache_Watch_leaks(t *testing.T) {
+	c := New[int, int](nil)
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 100; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		c.Watch(ctx, 1)
+		c.Add(i, i)
+		cancel()
+	}
+
+	if n := waitGoroutines(before); n > before {
+		t.Errorf("Expected %d goroutines after all the watches are done, got %d", before, n)
+	}
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if n := len(c.listeners); n != 0 {
+		t.Errorf("Expected no listeners after all the watches are done, got %d", n)
+	}
+}
+
+func TestCache_WatchExpired(t *testing.T) {
+	c := New[int, int](nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := c.WatchExpired(ctx, 10)
+
+	c.AddWithTimeout(1, 10, time.Millisecond)
+	c.Add(2, 20)
+	c.Remove(2)
+
+	select {
+	case kv := <-ch:
+		if kv.Key != 1 || kv.Value != 10 {
+			t.Errorf("Expected expiry of key 1 with value 10, got %+v", kv)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the expired entry to be received")
+	}
+
+	cancel()
+
+	for kv := range ch {
+		t.Errorf("Expected no more entries, got %+v", kv)
+	}
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if n := len(c.expiryListeners); n != 0 {
+		t.Errorf("Expected no listeners after the context is done, got %d", n)
+	}
+}
+
+func TestCache_GetWait(t *testing.T) {
+	c := New[int, int](nil)
+	c.Add(1, 1)
+
+	if v, err := c.GetWait(context.Background(), 1); err != nil || v != 1 {
+		t.Errorf("Expected to get %d straight away, got %d and %v", 1, v, err)
+	}
+
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		c.Add(3, 2)
+		c.Add(2, 2)
+	}()
+
+	if v, err := c.GetWait(context.Background(), 2); err != nil || v != 2 {
+		t.Errorf("Expected to get %d once added, got %d and %v", 2, v, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	if _, err := c.GetWait(ctx, 100); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if n := len(c.listeners); n != 0 {
+		t.Errorf("Expected no listeners after GetWait returns, got %d", n)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_Watch(b *testing.B) {
+	c := New[int, int](nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := c.Watch(ctx, 1)
+
+	for n := 0; n < b.N; n++ {
+		c.Add(n%100, n)
+		<-ch
+	}
+}
@@ -0,0 +1,60 @@
+package cacheMachine
+
+import (
+	"log"
+	"time"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//SlowCallback describes a single call of a user callback that ran longer than Requirements.CallbackTimeout
+type SlowCallback struct {
+	//Method of the cache that called the callback, e.g. "ForEach"
+	Operation string
+
+	//How long the callback ran
+	Duration time.Duration
+}
+
+//------PRIVATE------
+
+//reportSlow reports the callback of the operation if it ran longer than CallbackTimeout
+func (c *Cache[TKey, TValue]) reportSlow(op string, start time.Time) {
+	d := time.Since(start)
+	if d <= c.cache.Requirements.CallbackTimeout {
+		return
+	}
+
+	if c.cache.Requirements.OnSlowCallback != nil {
+		c.cache.Requirements.OnSlowCallback(SlowCallback{Operation: op, Duration: d})
+		return
+	}
+
+	log.Printf("cacheMachine: %s callback took %s, longer than the limit of %s", op, d, c.cache.Requirements.CallbackTimeout)
+}
+
+//watchPredicate wraps the predicate supplied to the operation so that slow calls are reported. The predicate is
+//returned as is if the watchdog is off
+func (c *Cache[TKey, TValue]) watchPredicate(op string, pred func(TKey, TValue) bool) func(TKey, TValue) bool {
+	if c.cache.Requirements.CallbackTimeout < 1 || pred == nil {
+		return pred
+	}
+
+	return func(key TKey, val TValue) bool {
+		defer c.reportSlow(op, time.Now())
+		return pred(key, val)
+	}
+}
+
+//watchVisitor wraps the function supplied to the operation so that slow calls are reported. The function is
+//returned as is if the watchdog is off
+func (c *Cache[TKey, TValue]) watchVisitor(op string, f func(TKey, TValue)) func(TKey, TValue) {
+	if c.cache.Requirements.CallbackTimeout < 1 || f == nil {
+		return f
+	}
+
+	return func(key TKey, val TValue) {
+		defer c.reportSlow(op, time.Now())
+		f(key, val)
+	}
+}
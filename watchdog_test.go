@@ -0,0 +1,48 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestRequirements_CallbackTimeout(t *testing.T) {
+	var reports []SlowCallback
+
+	c := initializeFullCache(3, &Requirements{
+		CallbackTimeout: time.Millisecond * 10,
+		OnSlowCallback:  func(s SlowCallback) { reports = append(reports, s) },
+	})
+
+	c.ForEach(func(k, v int) {
+		if k == 1 {
+			time.Sleep(time.Millisecond * 20)
+		}
+	})
+
+	if len(reports) != 1 || reports[0].Operation != "ForEach" || reports[0].Duration < time.Millisecond*20 {
+		t.Errorf("Expected single slow ForEach callback to be reported, got %+v", reports)
+	}
+
+	c.RemoveWhere(func(k, v int) bool {
+		time.Sleep(time.Millisecond * 20)
+		return false
+	})
+
+	if len(reports) != 4 || reports[3].Operation != "RemoveWhere" {
+		t.Errorf("Expected 3 slow RemoveWhere callbacks to be reported, got %+v", reports)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkRequirements_CallbackTimeout(b *testing.B) {
+	c := initializeFullCache(100, &Requirements{CallbackTimeout: time.Second, OnSlowCallback: func(SlowCallback) {}})
+
+	f := func(k, v int) {}
+
+	for n := 0; n < b.N; n++ {
+		c.ForEach(f)
+	}
+}
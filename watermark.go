@@ -0,0 +1,46 @@
+package cacheMachine
+
+//------PRIVATE------
+
+//checkWatermark calls OnHighWatermark once the cache reaches HighWatermark, unless it has been called already since
+//the cache was last below it. This method has no mutex protection
+func (c *Cache[TKey, TValue]) checkWatermark() {
+	r := &c.cache.Requirements
+	if r.HighWatermark < 1 || c.aboveWatermark || len(c.data) < r.HighWatermark {
+		return
+	}
+
+	c.aboveWatermark = true
+
+	if r.OnHighWatermark != nil {
+		go r.OnHighWatermark(len(c.data))
+	}
+}
+
+//rearmWatermark allows calling OnHighWatermark again once the cache shrinks below HighWatermark. This method has
+//no mutex protection
+func (c *Cache[TKey, TValue]) rearmWatermark() {
+	if c.aboveWatermark && len(c.data) < c.cache.Requirements.HighWatermark {
+		c.aboveWatermark = false
+	}
+}
+
+//------PUBLIC------
+
+//SetWatermarks changes Requirements.HighWatermark and Requirements.MaxEntries of the live cache. Entries are evicted
+//straight away if the cache no longer fits within the new MaxEntries. 0 turns the respective limit off
+func (c *Cache[TKey, TValue]) SetWatermarks(high, max int) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.cache.Requirements.HighWatermark = high
+	c.cache.Requirements.MaxEntries = max
+	c.aboveWatermark = false
+
+	if max > 0 {
+		c.track()
+	}
+
+	c.evict()
+	c.checkWatermark()
+}
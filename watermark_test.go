@@ -0,0 +1,83 @@
+package cacheMachine
+
+import (
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestRequirements_HighWatermark(t *testing.T) {
+	reached := make(chan int, 10)
+	c := New[int, int](&Requirements{HighWatermark: 5, MaxEntries: 8, OnHighWatermark: func(n int) { reached <- n }})
+
+	for i := 0; i < 10; i++ {
+		c.Add(i, i)
+	}
+
+	select {
+	case n := <-reached:
+		if n != 5 {
+			t.Errorf("Expected OnHighWatermark to be called with %d entries, got %d", 5, n)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected OnHighWatermark to be called")
+	}
+
+	if c.Count() != 8 {
+		t.Errorf("Expected the cache to be limited to %d entries, got %d", 8, c.Count())
+	}
+
+	c.Remove(9)
+	c.Add(9, 9)
+
+	select {
+	case <-reached:
+		t.Errorf("Expected OnHighWatermark not to be called again while the cache stays above it")
+	case <-time.After(time.Millisecond * 20):
+	}
+
+	for i := 0; i < 6; i++ {
+		c.Remove(i + 2)
+	}
+	c.Add(100, 100)
+	c.Add(101, 101)
+	c.Add(102, 102)
+	c.Add(103, 103)
+
+	select {
+	case <-reached:
+	case <-time.After(time.Second):
+		t.Errorf("Expected OnHighWatermark to be called again once the cache grew back")
+	}
+}
+
+func TestCache_SetWatermarks(t *testing.T) {
+	reached := make(chan int, 1)
+	c := initializeFullCache(10, &Requirements{OnHighWatermark: func(n int) { reached <- n }})
+
+	c.SetWatermarks(4, 6)
+
+	if c.Count() != 6 {
+		t.Errorf("Expected the cache to shrink to %d entries, got %d", 6, c.Count())
+	}
+
+	select {
+	case n := <-reached:
+		if n != 6 {
+			t.Errorf("Expected OnHighWatermark to be called with %d entries, got %d", 6, n)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected OnHighWatermark to be called")
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_SetWatermarks(b *testing.B) {
+	c := initializeFullCache(100, nil)
+
+	for n := 0; n < b.N; n++ {
+		c.SetWatermarks(50, 200)
+	}
+}
@@ -0,0 +1,239 @@
+//Package webhook delivers changes of a cache to an HTTP endpoint, so that systems not written in Go can react to
+//them. Events are sent in batches as JSON arrays with POST requests and failed requests are retried
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/emillis/cacheMachine"
+)
+
+//===========[CACHE/STATIC]=============================================================================================
+
+//Default options used when Options are not supplied or their fields are left zero
+const (
+	defaultBatchSize     = 100
+	defaultBufferSize    = 1024
+	defaultFlushInterval = time.Second
+	defaultMaxRetries    = 3
+	defaultRetryBackoff  = time.Millisecond * 100
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//Options of the Emitter. Zero fields take default values
+type Options struct {
+	//Client used to send the requests. Defaults to http.DefaultClient
+	Client *http.Client
+
+	//Header is added to every request, e.g. for authorization
+	Header http.Header
+
+	//BatchSize is the maximum number of events sent in a single request. Defaults to 100
+	BatchSize int
+
+	//BufferSize is the number of events waiting to be sent. Events are dropped once the buffer is full, so that
+	//slow endpoint never blocks the cache. Defaults to 1024
+	BufferSize int
+
+	//FlushInterval is the longest time an event waits for the batch to fill up. Defaults to 1 second
+	FlushInterval time.Duration
+
+	//MaxRetries is the number of times a failed request is retried before the batch is dropped. Set it to a
+	//negative value to disable retries. Defaults to 3
+	MaxRetries int
+
+	//RetryBackoff is the delay before the first retry. It doubles with every following retry. Defaults to 100ms
+	RetryBackoff time.Duration
+}
+
+//Event is a single change of the cache as it is encoded in the request body
+type Event[TKey cacheMachine.Key] struct {
+	Kind string    `json:"kind"`
+	Key  TKey      `json:"key"`
+	Time time.Time `json:"time"`
+
+	//Time the entry was stored at and the number of times it was read since
+	Created time.Time `json:"created"`
+	Hits    uint32    `json:"hits"`
+}
+
+//Emitter sends events of a single cache to the webhook URL
+type Emitter[TKey cacheMachine.Key] struct {
+	//Counters are kept first to be 64-bit aligned for the atomic operations
+	dropped uint64
+	failed  uint64
+
+	url         string
+	opts        Options
+	events      chan Event[TKey]
+	unsubscribe func()
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+//------PRIVATE------
+
+//run collects the events into batches and sends them until the emitter is closed
+func (e *Emitter[TKey]) run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Event[TKey], 0, e.opts.BatchSize)
+
+	for {
+		select {
+		case ev, ok := <-e.events:
+			if !ok {
+				e.send(batch)
+				return
+			}
+
+			if batch = append(batch, ev); len(batch) >= e.opts.BatchSize {
+				e.send(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			e.send(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+//send posts the batch to the webhook URL, retrying failed requests with exponential backoff
+func (e *Emitter[TKey]) send(batch []Event[TKey]) {
+	if len(batch) < 1 {
+		return
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		atomic.AddUint64(&e.failed, 1)
+		return
+	}
+
+	backoff := e.opts.RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		retry, ok := e.post(body)
+		if ok {
+			return
+		}
+
+		if !retry || attempt >= e.opts.MaxRetries {
+			atomic.AddUint64(&e.failed, 1)
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+//post sends a single request. Returns whether the request succeeded and, if not, whether it's worth retrying.
+//Client errors other than 408 and 429 are not retried, since sending the same body again won't help
+func (e *Emitter[TKey]) post(body []byte) (retry bool, ok bool) {
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return false, false
+	}
+
+	for k, v := range e.opts.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.opts.Client.Do(req)
+	if err != nil {
+		return true, false
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, true
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests:
+		return true, false
+	}
+
+	return resp.StatusCode >= 500, false
+}
+
+//------PUBLIC------
+
+//Dropped returns the number of events dropped because the buffer was full
+func (e *Emitter[TKey]) Dropped() uint64 {
+	return atomic.LoadUint64(&e.dropped)
+}
+
+//Failed returns the number of batches that couldn't be delivered, even after retrying
+func (e *Emitter[TKey]) Failed() uint64 {
+	return atomic.LoadUint64(&e.failed)
+}
+
+//Close stops listening to the cache, sends the events still buffered and waits for the delivery to finish
+func (e *Emitter[TKey]) Close() {
+	e.closeOnce.Do(func() {
+		e.unsubscribe()
+		close(e.events)
+	})
+
+	<-e.done
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//New starts sending events of the cache supplied to the url. Options can be nil. Close must be called to stop it
+func New[TKey cacheMachine.Key, TValue any](c *cacheMachine.Cache[TKey, TValue], url string, opts *Options) *Emitter[TKey] {
+	e := &Emitter[TKey]{url: url, done: make(chan struct{})}
+
+	if opts != nil {
+		e.opts = *opts
+	}
+
+	if e.opts.Client == nil {
+		e.opts.Client = http.DefaultClient
+	}
+
+	if e.opts.BatchSize < 1 {
+		e.opts.BatchSize = defaultBatchSize
+	}
+
+	if e.opts.BufferSize < 1 {
+		e.opts.BufferSize = defaultBufferSize
+	}
+
+	if e.opts.FlushInterval <= 0 {
+		e.opts.FlushInterval = defaultFlushInterval
+	}
+
+	if e.opts.MaxRetries == 0 {
+		e.opts.MaxRetries = defaultMaxRetries
+	}
+
+	if e.opts.RetryBackoff <= 0 {
+		e.opts.RetryBackoff = defaultRetryBackoff
+	}
+
+	e.events = make(chan Event[TKey], e.opts.BufferSize)
+
+	go e.run()
+
+	//The listener runs while the cache is locked, so it must never block
+	e.unsubscribe = c.Subscribe(func(ev cacheMachine.Event[TKey]) {
+		select {
+		case e.events <- Event[TKey]{Kind: ev.Kind.String(), Key: ev.Key, Time: time.Now(), Created: ev.Created, Hits: ev.Hits}:
+		default:
+			atomic.AddUint64(&e.dropped, 1)
+		}
+	})
+
+	return e
+}
@@ -0,0 +1,114 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/emillis/cacheMachine"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestNew(t *testing.T) {
+	var mx sync.Mutex
+	var received []Event[string]
+	attempts := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mx.Lock()
+		defer mx.Unlock()
+
+		//The first request fails to exercise retries
+		if attempts++; attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "secret" {
+			t.Errorf("Expected header Authorization to be sent, got %q", r.Header.Get("Authorization"))
+		}
+
+		var batch []Event[string]
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("Expected to decode the batch, got error: %s", err)
+		}
+
+		received = append(received, batch...)
+	}))
+	defer srv.Close()
+
+	c := cacheMachine.New[string, int](nil)
+
+	e := New(&c, srv.URL, &Options{
+		Header:        http.Header{"Authorization": {"secret"}},
+		BatchSize:     2,
+		FlushInterval: time.Millisecond * 10,
+		RetryBackoff:  time.Millisecond,
+	})
+
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Remove("a")
+	c.AddWithTimeout("c", 3, time.Millisecond*10)
+
+	time.Sleep(time.Millisecond * 100)
+	e.Close()
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	expected := []string{"add:a", "add:b", "remove:a", "add:c", "expire:c"}
+
+	if len(received) != len(expected) {
+		t.Fatalf("Expected to receive %d events, got %+v", len(expected), received)
+	}
+
+	for i, ev := range received {
+		if got := ev.Kind + ":" + ev.Key; got != expected[i] {
+			t.Errorf("Expected event %d to be %s, got %s", i, expected[i], got)
+		}
+	}
+
+	if e.Failed() != 0 || e.Dropped() != 0 {
+		t.Errorf("Expected nothing to be failed or dropped, got %d failed and %d dropped", e.Failed(), e.Dropped())
+	}
+}
+
+func TestEmitter_Failed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	c := cacheMachine.New[string, int](nil)
+	e := New(&c, srv.URL, nil)
+
+	c.Add("a", 1)
+	e.Close()
+
+	if e.Failed() != 1 {
+		t.Errorf("Expected rejected batch to fail without retries, got %d failed", e.Failed())
+	}
+
+	c.Add("b", 2)
+	e.Close()
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkNew(b *testing.B) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	c := cacheMachine.New[int, int](nil)
+	e := New(&c, srv.URL, nil)
+	defer e.Close()
+
+	for n := 0; n < b.N; n++ {
+		c.Add(n%100, n)
+	}
+}
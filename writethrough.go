@@ -0,0 +1,106 @@
+package cacheMachine
+
+import (
+	"sync"
+	"time"
+)
+
+//===========[STRUCTS]==================================================================================================
+
+//Coalesces writes of the same key within a window into a single write of the latest value
+type coalescer[TKey Key, TValue any] struct {
+	write  func(TKey, TValue)
+	window time.Duration
+
+	//Latest values not written yet
+	pending map[TKey]TValue
+	mx      sync.Mutex
+
+	//Writes are made one at a time, so that an older value of the key can never be written after a newer one
+	writeMx sync.Mutex
+}
+
+//------PRIVATE------
+
+//add schedules write of the value. If the write of the key is already scheduled, the value replaces the one
+//scheduled instead
+func (w *coalescer[TKey, TValue]) add(key TKey, val TValue) {
+	if w.window < 1 {
+		w.writeMx.Lock()
+		w.write(key, val)
+		w.writeMx.Unlock()
+		return
+	}
+
+	w.mx.Lock()
+	_, scheduled := w.pending[key]
+	w.pending[key] = val
+	w.mx.Unlock()
+
+	if !scheduled {
+		time.AfterFunc(w.window, func() { w.flush(key) })
+	}
+}
+
+//flush writes the latest value of the key, if there is one pending
+func (w *coalescer[TKey, TValue]) flush(key TKey) {
+	w.writeMx.Lock()
+	defer w.writeMx.Unlock()
+
+	w.mx.Lock()
+	val, exist := w.pending[key]
+	delete(w.pending, key)
+	w.mx.Unlock()
+
+	if exist {
+		w.write(key, val)
+	}
+}
+
+//flushAll writes the latest values of all the keys pending
+func (w *coalescer[TKey, TValue]) flushAll() {
+	w.mx.Lock()
+	keys := make([]TKey, 0, len(w.pending))
+	for key := range w.pending {
+		keys = append(keys, key)
+	}
+	w.mx.Unlock()
+
+	for _, key := range keys {
+		w.flush(key)
+	}
+}
+
+//writeThrough hands the value added over to the write-through function, if there is one. It must not be called
+//under the lock of the cache
+func (c *Cache[TKey, TValue]) writeThrough(key TKey, val TValue) {
+	if w, _ := c.writer.Load().(*coalescer[TKey, TValue]); w != nil {
+		w.add(key, val)
+	}
+}
+
+//------PUBLIC------
+
+//SetWriteThrough sets the function that persists values added to the cache, e.g. to a database. Adds of the same
+//key within the window are coalesced into a single write of the latest value, made once the window since the first
+//of them passes, while the cache holds the latest value straight away. With window of 0, every add is written
+//immediately by the goroutine adding the value. Values loaded by GetOrLoad, GetBulkOrLoad and Load are not written.
+//Writes pending when the function is replaced are flushed first. Nil stops writing through
+func (c *Cache[TKey, TValue]) SetWriteThrough(write func(TKey, TValue), window time.Duration) {
+	var w *coalescer[TKey, TValue]
+	if write != nil {
+		w = &coalescer[TKey, TValue]{write: write, window: window, pending: make(map[TKey]TValue)}
+	}
+
+	old, _ := c.writer.Swap(w).(*coalescer[TKey, TValue])
+	if old != nil {
+		old.flushAll()
+	}
+}
+
+//FlushWrites writes all the values pending in the write-through window straight away, e.g. before shutdown
+func (c *Cache[TKey, TValue]) FlushWrites() {
+	if w, _ := c.writer.Load().(*coalescer[TKey, TValue]); w != nil {
+		w.flushAll()
+	}
+}
@@ -0,0 +1,102 @@
+package cacheMachine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+//===========[TESTING]====================================================================================================
+
+func TestCache_SetWriteThrough(t *testing.T) {
+	c := initializeFullCache(0, nil)
+
+	written := make(map[int][]int)
+	mx := sync.Mutex{}
+
+	c.SetWriteThrough(func(k, v int) {
+		mx.Lock()
+		written[k] = append(written[k], v)
+		mx.Unlock()
+	}, time.Millisecond*50)
+
+	for i := 0; i < 10; i++ {
+		c.Add(1, i)
+	}
+	c.AddWithTimeout(2, 2, time.Second*30)
+
+	if v := c.GetValue(1); v != 9 {
+		t.Errorf("Expected the cache to hold the latest value %d straight away, got %d", 9, v)
+	}
+
+	c.GetOrLoad(context.Background(), 3, func(ctx context.Context, k int) (int, error) { return k, nil })
+
+	time.Sleep(time.Millisecond * 150)
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	if len(written[1]) != 1 || written[1][0] != 9 || len(written[2]) != 1 {
+		t.Errorf("Expected adds of key 1 to be coalesced into a single write of %d, got %v", 9, written)
+	}
+
+	if _, exist := written[3]; exist {
+		t.Errorf("Expected loaded value not to be written through, but it was")
+	}
+}
+
+func TestCache_FlushWrites(t *testing.T) {
+	c := initializeFullCache(0, nil)
+
+	var written []int
+	c.SetWriteThrough(func(k, v int) { written = append(written, k) }, time.Minute)
+
+	c.AddBulk(map[int]int{1: 1, 2: 2})
+	c.FlushWrites()
+
+	if len(written) != 2 {
+		t.Errorf("Expected %d pending writes to be flushed, got %v", 2, written)
+	}
+
+	c.SetWriteThrough(nil, 0)
+	c.Add(3, 3)
+
+	if len(written) != 2 {
+		t.Errorf("Expected nothing to be written once write-through is off, got %v", written)
+	}
+}
+
+func TestCache_SetWriteThrough_merge(t *testing.T) {
+	c1 := New[int, int](nil)
+	c2 := New[int, int](nil)
+	c2.AddWithTimeout(1, 1, time.Minute)
+	c2.Add(2, 2)
+
+	written := make(map[int]int)
+	c1.SetWriteThrough(func(k, v int) { written[k] = v }, 0)
+
+	Merge[int, int](&c1, &c2)
+
+	if len(written) != 2 || written[1] != 1 || written[2] != 2 {
+		t.Errorf("Expected merged values to be written through, got %v", written)
+	}
+
+	c2.Add(3, 3)
+	MergeAndReset[int, int](&c1, &c2)
+
+	if written[3] != 3 {
+		t.Errorf("Expected values merged by MergeAndReset to be written through, got %v", written)
+	}
+}
+
+//===========[BENCHMARKS]====================================================================================================
+
+func BenchmarkCache_SetWriteThrough(b *testing.B) {
+	c := initializeFullCache(0, nil)
+	c.SetWriteThrough(func(k, v int) {}, time.Millisecond*10)
+
+	for n := 0; n < b.N; n++ {
+		c.Add(n%100, n)
+	}
+}